@@ -0,0 +1,76 @@
+CREATE TABLE IF NOT EXISTS users (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    avatar_url VARCHAR(2048),
+    role VARCHAR(255) NOT NULL DEFAULT 'MEMBER',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id CHAR(20) PRIMARY KEY,
+    text VARCHAR(255) NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS todos (
+    id CHAR(20) PRIMARY KEY,
+    text VARCHAR(255) NOT NULL,
+    done BOOLEAN NOT NULL,
+    task_id CHAR(20) NOT NULL REFERENCES tasks (id) ON DELETE RESTRICT,
+    parent_id CHAR(20) REFERENCES todos (id) ON DELETE RESTRICT,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS activities (
+    id CHAR(20) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    entity_type VARCHAR(255) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    task_id CHAR(20) REFERENCES tasks (id) ON DELETE RESTRICT,
+    action VARCHAR(255) NOT NULL,
+    changes JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_activities_entity ON activities (entity_type, entity_id);
+
+CREATE TABLE IF NOT EXISTS status_transitions (
+    id CHAR(20) PRIMARY KEY,
+    task_id CHAR(20) NOT NULL REFERENCES tasks (id) ON DELETE RESTRICT,
+    from_status VARCHAR(255),
+    to_status VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_status_transitions_created_at ON status_transitions (created_at);
+
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id CHAR(20) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    name VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_personal_access_tokens_token_hash ON personal_access_tokens (token_hash);
+
+-- MySQL's ON UPDATE CURRENT_TIMESTAMP
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER users_updated_at BEFORE UPDATE ON users FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE OR REPLACE TRIGGER tasks_updated_at BEFORE UPDATE ON tasks FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE OR REPLACE TRIGGER todos_updated_at BEFORE UPDATE ON todos FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE OR REPLACE TRIGGER personal_access_tokens_updated_at BEFORE UPDATE ON personal_access_tokens FOR EACH ROW EXECUTE FUNCTION set_updated_at();
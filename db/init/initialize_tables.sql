@@ -1,6 +1,9 @@
 CREATE TABLE IF NOT EXISTS `users` (
     `id` VARCHAR(255) PRIMARY KEY,
     `name` VARCHAR(255) NOT NULL,
+    `email` VARCHAR(255),
+    `avatar_url` VARCHAR(2048),
+    `role` VARCHAR(255) NOT NULL DEFAULT 'MEMBER',
     `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
@@ -20,7 +23,48 @@ CREATE TABLE IF NOT EXISTS `todos` (
     `text` VARCHAR(255) NOT NULL,
     `done` TINYINT(1) NOT NULL,
     `task_id` CHAR(20) NOT NULL,
+    `parent_id` CHAR(20),
+    `position` INT NOT NULL DEFAULT 0,
     `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (`task_id`) REFERENCES `tasks` (`id`) ON DELETE RESTRICT,
+    FOREIGN KEY (`parent_id`) REFERENCES `todos` (`id`) ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `activities` (
+    `id` CHAR(20) PRIMARY KEY,
+    `user_id` VARCHAR(255) NOT NULL,
+    `entity_type` VARCHAR(255) NOT NULL,
+    `entity_id` VARCHAR(255) NOT NULL,
+    `task_id` CHAR(20),
+    `action` VARCHAR(255) NOT NULL,
+    `changes` JSON NOT NULL,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX `idx_activities_entity` (`entity_type`, `entity_id`),
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE RESTRICT,
     FOREIGN KEY (`task_id`) REFERENCES `tasks` (`id`) ON DELETE RESTRICT
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `status_transitions` (
+    `id` CHAR(20) PRIMARY KEY,
+    `task_id` CHAR(20) NOT NULL,
+    `from_status` VARCHAR(255),
+    `to_status` VARCHAR(255) NOT NULL,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX `idx_status_transitions_created_at` (`created_at`),
+    FOREIGN KEY (`task_id`) REFERENCES `tasks` (`id`) ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `personal_access_tokens` (
+    `id` CHAR(20) PRIMARY KEY,
+    `user_id` VARCHAR(255) NOT NULL,
+    `name` VARCHAR(255) NOT NULL,
+    `token_hash` CHAR(64) NOT NULL,
+    `scopes` VARCHAR(255) NOT NULL,
+    `expires_at` DATETIME,
+    `revoked_at` DATETIME,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX `idx_personal_access_tokens_token_hash` (`token_hash`),
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
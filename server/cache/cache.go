@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shota-tech/graphql/server/logging"
+)
+
+// ErrNil is returned by a RedisClient when the key does not exist.
+var ErrNil = errors.New("redis: nil")
+
+type (
+	// Memory is a graphql.Cache kept in the memory of this process.
+	Memory struct {
+		lru *LRU[string, interface{}]
+	}
+
+	// RedisClient is the subset of commands needed from Redis or any server speaking its protocol.
+	RedisClient interface {
+		Get(ctx context.Context, key string) (string, error)
+		Set(ctx context.Context, key, value string, ttl time.Duration) error
+	}
+
+	// Redis is a graphql.Cache shared by every instance of the server.
+	// Only string values such as query documents can be stored.
+	Redis struct {
+		client RedisClient
+		prefix string
+		ttl    time.Duration
+	}
+)
+
+var (
+	_ graphql.Cache = &Memory{}
+	_ graphql.Cache = &Redis{}
+)
+
+func NewMemory(size int, ttl time.Duration) *Memory {
+	return &Memory{lru: NewLRU[string, interface{}](size, ttl)}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (interface{}, bool) {
+	return m.lru.Get(key)
+}
+
+func (m *Memory) Add(ctx context.Context, key string, value interface{}) {
+	m.lru.Add(key, value)
+}
+
+// NewRedis returns a cache storing its entries under the prefix for the ttl.
+func NewRedis(client RedisClient, prefix string, ttl time.Duration) *Redis {
+	return &Redis{
+		client: client,
+		prefix: prefix,
+		ttl:    ttl,
+	}
+}
+
+// Get treats any error as a miss, clients of APQ resend the query anyway.
+// Errors other than a missing key are logged so that an unavailable server is noticed.
+func (r *Redis) Get(ctx context.Context, key string) (interface{}, bool) {
+	value, err := r.client.Get(ctx, r.prefix+key)
+	if err != nil {
+		if !errors.Is(err, ErrNil) {
+			logging.FromContext(ctx).Warn("failed to get cache entry", "key", r.prefix+key, "error", err)
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+func (r *Redis) Add(ctx context.Context, key string, value interface{}) {
+	s, ok := value.(string)
+	if !ok {
+		return
+	}
+	if err := r.client.Set(ctx, r.prefix+key, s, r.ttl); err != nil {
+		logging.FromContext(ctx).Warn("failed to add cache entry", "key", r.prefix+key, "error", err)
+	}
+}
@@ -0,0 +1,188 @@
+package cache_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shota-tech/graphql/server/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis answers GET and SET like a Redis server, recording the ttl of each key.
+type fakeRedis struct {
+	net.Listener
+	mu     sync.Mutex
+	values map[string]string
+	ttls   map[string]string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeRedis{Listener: l, values: map[string]string{}, ttls: map[string]string{}}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			if v, ok := s.values[args[1]]; ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(v), v)
+			} else {
+				io.WriteString(conn, "$-1\r\n")
+			}
+		case "SET":
+			s.values[args[1]] = args[2]
+			if len(args) == 5 {
+				s.ttls[args[1]] = args[4]
+			}
+			io.WriteString(conn, "+OK\r\n")
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+		s.mu.Unlock()
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedis(t *testing.T) {
+	server := newFakeRedis(t)
+	client, err := cache.NewGoRedisClient("redis://" + server.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	sut := cache.NewRedis(client, "apq:", time.Hour)
+	ctx := context.Background()
+
+	_, ok := sut.Get(ctx, "hash")
+	assert.False(t, ok)
+
+	sut.Add(ctx, "hash", "{ fetchUser { id } }")
+	got, ok := sut.Get(ctx, "hash")
+	assert.True(t, ok)
+	assert.Equal(t, "{ fetchUser { id } }", got)
+	assert.Equal(t, "3600", server.ttls["apq:hash"])
+
+	sut.Add(ctx, "other", 1)
+	_, ok = sut.Get(ctx, "other")
+	assert.False(t, ok)
+}
+
+func TestRedis_Unavailable(t *testing.T) {
+	client, err := cache.NewGoRedisClient("redis://127.0.0.1:1")
+	require.NoError(t, err)
+	sut := cache.NewRedis(client, "apq:", time.Hour)
+	sut.Add(context.Background(), "hash", "{ fetchUser { id } }")
+	_, ok := sut.Get(context.Background(), "hash")
+	assert.False(t, ok)
+}
+
+func TestRedis_Hung(t *testing.T) {
+	// the server accepts connections but never replies
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	client, err := cache.NewGoRedisClient("redis://" + l.Addr().String() + "?read_timeout=50ms&write_timeout=50ms&max_retries=-1")
+	require.NoError(t, err)
+	defer client.Close()
+	sut := cache.NewRedis(client, "apq:", time.Hour)
+	done := make(chan bool)
+	go func() {
+		_, ok := sut.Get(context.Background(), "hash")
+		done <- ok
+	}()
+	select {
+	case ok := <-done:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("get is blocked by the hung server")
+	}
+}
+
+func TestNewGoRedisClient(t *testing.T) {
+	tests := map[string]struct {
+		url       string
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			url:       "redis://:secret@localhost:6379/1",
+			assertErr: assert.NoError,
+		},
+		"default port": {
+			url:       "redis://localhost",
+			assertErr: assert.NoError,
+		},
+		"unsupported scheme": {
+			url:       "http://localhost:6379",
+			assertErr: assert.Error,
+		},
+		"host is empty": {
+			url:       "",
+			assertErr: assert.Error,
+		},
+		"invalid db": {
+			url:       "redis://localhost:6379/cache",
+			assertErr: assert.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := cache.NewGoRedisClient(tt.url)
+			tt.assertErr(t, err)
+		})
+	}
+}
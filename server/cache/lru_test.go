@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		run  func(c *LRU[string, int])
+		want map[string]int
+	}{
+		"least recently used entry is evicted": {
+			run: func(c *LRU[string, int]) {
+				c.Add("a", 1)
+				c.Add("b", 2)
+				c.Get("a")
+				c.Add("c", 3)
+			},
+			want: map[string]int{"a": 1, "c": 3},
+		},
+		"add overwrites the value": {
+			run: func(c *LRU[string, int]) {
+				c.Add("a", 1)
+				c.Add("a", 2)
+			},
+			want: map[string]int{"a": 2},
+		},
+		"expired entry is a miss": {
+			run: func(c *LRU[string, int]) {
+				c.Add("a", 1)
+				now = now.Add(time.Minute)
+				c.Add("b", 2)
+			},
+			want: map[string]int{"b": 2},
+		},
+		"removed entry is a miss": {
+			run: func(c *LRU[string, int]) {
+				c.Add("a", 1)
+				c.Add("b", 2)
+				c.Remove("a")
+			},
+			want: map[string]int{"b": 2},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sut := NewLRU[string, int](2, time.Minute)
+			sut.now = func() time.Time { return now }
+			tt.run(sut)
+			for _, key := range []string{"a", "b", "c"} {
+				got, ok := sut.Get(key)
+				want, wantOK := tt.want[key]
+				assert.Equal(t, wantOK, ok, key)
+				assert.Equal(t, want, got, key)
+			}
+			assert.Equal(t, len(tt.want), sut.Len())
+		})
+	}
+}
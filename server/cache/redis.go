@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultRedisTimeout bounds dialing, reading and writing when the URL sets no timeout,
+// so that a hung server slows the requests down instead of blocking them.
+const defaultRedisTimeout = time.Second
+
+// GoRedisClient is a RedisClient backed by go-redis,
+// so Redis, Valkey, KeyDB or Dragonfly can back the cache.
+type GoRedisClient struct {
+	client *redis.Client
+}
+
+var _ RedisClient = &GoRedisClient{}
+
+// NewGoRedisClient returns a client for a URL such as redis://:password@localhost:6379/0.
+// Options such as ?read_timeout=500ms are read from the query of the URL.
+func NewGoRedisClient(rawURL string) (*GoRedisClient, error) {
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redis url: %w", err)
+	}
+	if options.DialTimeout == 0 {
+		options.DialTimeout = defaultRedisTimeout
+	}
+	if options.ReadTimeout == 0 {
+		options.ReadTimeout = defaultRedisTimeout
+	}
+	if options.WriteTimeout == 0 {
+		options.WriteTimeout = defaultRedisTimeout
+	}
+	return &GoRedisClient{client: redis.NewClient(options)}, nil
+}
+
+func (c *GoRedisClient) Get(ctx context.Context, key string) (string, error) {
+	value, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNil
+	}
+	return value, err
+}
+
+func (c *GoRedisClient) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *GoRedisClient) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shota-tech/graphql/server/logging"
+)
+
+// Config tunes the connection pool and how long to wait for the database on startup.
+type Config struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// ConnectTimeout is the deadline for the database to accept connections.
+	ConnectTimeout time.Duration
+	// InitialBackoff is the wait after the first failed ping, doubled up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+var DefaultConfig = Config{
+	MaxOpenConns:    25,
+	MaxIdleConns:    25,
+	ConnMaxLifetime: 5 * time.Minute,
+	ConnMaxIdleTime: time.Minute,
+	ConnectTimeout:  time.Minute,
+	InitialBackoff:  500 * time.Millisecond,
+	MaxBackoff:      5 * time.Second,
+}
+
+// Bootstrap configures the pool of the db and pings it until it answers,
+// so that the server doesn't start before the database, e.g. with docker compose.
+// It gives up once the connect timeout or the context is over.
+func Bootstrap(ctx context.Context, db *sql.DB, config Config) error {
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+
+	if config.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.ConnectTimeout)
+		defer cancel()
+	}
+	backoff := config.InitialBackoff
+	if backoff <= 0 {
+		backoff = DefaultConfig.InitialBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("database is unavailable after %d attempts: %w", attempt, err)
+		}
+		logging.FromContext(ctx).Warn("database is unavailable, retrying",
+			"attempt", attempt,
+			slog.Duration("backoff", backoff),
+			"error", err,
+		)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("database is unavailable after %d attempts: %w", attempt, err)
+		}
+		backoff *= 2
+		if config.MaxBackoff > 0 && backoff > config.MaxBackoff {
+			backoff = config.MaxBackoff
+		}
+	}
+}
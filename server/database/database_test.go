@@ -0,0 +1,64 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shota-tech/graphql/server/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrap(t *testing.T) {
+	config := database.Config{
+		MaxOpenConns:   10,
+		MaxIdleConns:   10,
+		ConnectTimeout: 100 * time.Millisecond,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	}
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock)
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing()
+			},
+			assertErr: assert.NoError,
+		},
+		"database starts later": {
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing().WillReturnError(assert.AnError)
+				mock.ExpectPing().WillReturnError(assert.AnError)
+				mock.ExpectPing()
+			},
+			assertErr: assert.NoError,
+		},
+		"database never starts": {
+			setup: func(mock sqlmock.Sqlmock) {
+				for i := 0; i < 1000; i++ {
+					mock.ExpectPing().WillReturnError(assert.AnError).WillDelayFor(time.Millisecond)
+				}
+			},
+			assertErr: assert.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// setup sqlmock
+			db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+			require.NoError(t, err)
+			defer db.Close()
+			if tt.setup != nil {
+				tt.setup(mock)
+			}
+			// test
+			err = database.Bootstrap(context.Background(), db, config)
+			tt.assertErr(t, err)
+			assert.Equal(t, 10, db.Stats().MaxOpenConnections)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+// DB routes reads to the replicas and writes to the primary.
+// Once a request has written, its reads go to the primary too so that it reads its own writes
+// regardless of the replication lag, see Middleware. Reads batched with other requests, such as those
+// of the loaders, run with the ctx of another request and may still read a replica:
+// the reads a write depends on belong in the Transaction of the write.
+type DB struct {
+	primary  *sql.DB
+	replicas []*sql.DB
+	next     atomic.Uint64
+}
+
+type (
+	sessionContextKey struct{}
+	txContextKey      struct{}
+
+	session struct {
+		wrote atomic.Bool
+	}
+)
+
+// New returns a DB reading from the replicas in turn, or from the primary without any.
+func New(primary *sql.DB, replicas ...*sql.DB) *DB {
+	return &DB{
+		primary:  primary,
+		replicas: replicas,
+	}
+}
+
+// Reader returns the executor for queries which can tolerate the replication lag.
+func (d *DB) Reader(ctx context.Context) boil.ContextExecutor {
+	if tx, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	if len(d.replicas) == 0 {
+		return d.primary
+	}
+	if s, ok := ctx.Value(sessionContextKey{}).(*session); ok && s.wrote.Load() {
+		return d.primary
+	}
+	return d.replicas[(d.next.Add(1)-1)%uint64(len(d.replicas))]
+}
+
+// Writer returns the primary, making the following reads of the request sticky to it.
+func (d *DB) Writer(ctx context.Context) boil.ContextExecutor {
+	if s, ok := ctx.Value(sessionContextKey{}).(*session); ok {
+		s.wrote.Store(true)
+	}
+	if tx, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return d.primary
+}
+
+// Primary returns the primary for reads which must see the latest writes of any request.
+func (d *DB) Primary() boil.ContextExecutor {
+	return d.primary
+}
+
+// Transaction runs fn in a transaction on the primary, which the repositories called with its ctx
+// read and write through. It is committed when fn returns nil and rolled back otherwise.
+// A transaction started within fn joins the outer one.
+func (d *DB) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	tx, err := d.primary.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if s, ok := ctx.Value(sessionContextKey{}).(*session); ok {
+		s.wrote.Store(true)
+	}
+	if err := fn(context.WithValue(ctx, txContextKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to rollback transaction: %w", rbErr))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// WithSession returns a context whose reads stick to the primary after its first write.
+func WithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, &session{})
+}
+
+// Middleware starts a session for each request.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithSession(r.Context())))
+	})
+}
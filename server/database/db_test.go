@@ -0,0 +1,116 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shota-tech/graphql/server/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB(t *testing.T) {
+	newDB := func() *sql.DB {
+		db, _, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+	primary, replica1, replica2 := newDB(), newDB(), newDB()
+
+	t.Run("reads are spread over the replicas", func(t *testing.T) {
+		sut := database.New(primary, replica1, replica2)
+		ctx := database.WithSession(context.Background())
+		assert.Same(t, replica1, sut.Reader(ctx))
+		assert.Same(t, replica2, sut.Reader(ctx))
+		assert.Same(t, replica1, sut.Reader(ctx))
+	})
+	t.Run("reads stick to the primary after a write", func(t *testing.T) {
+		sut := database.New(primary, replica1)
+		ctx := database.WithSession(context.Background())
+		assert.Same(t, replica1, sut.Reader(ctx))
+		assert.Same(t, primary, sut.Writer(ctx))
+		assert.Same(t, primary, sut.Reader(ctx))
+		// other requests are not affected
+		assert.Same(t, replica1, sut.Reader(database.WithSession(context.Background())))
+	})
+	t.Run("reads outside of a session are not sticky", func(t *testing.T) {
+		sut := database.New(primary, replica1)
+		assert.Same(t, primary, sut.Writer(context.Background()))
+		assert.Same(t, replica1, sut.Reader(context.Background()))
+		assert.Same(t, primary, sut.Primary())
+	})
+	t.Run("without replica", func(t *testing.T) {
+		sut := database.New(primary)
+		assert.Same(t, primary, sut.Reader(context.Background()))
+		assert.Same(t, primary, sut.Writer(context.Background()))
+	})
+}
+
+func TestDB_Transaction(t *testing.T) {
+	tests := map[string]struct {
+		fnErr   error
+		setup   func(mock sqlmock.Sqlmock)
+		wantErr string
+	}{
+		"committed": {
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+		},
+		"rolled back": {
+			fnErr: errors.New("failed to store"),
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			wantErr: "failed to store",
+		},
+		"failed to begin": {
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(assert.AnError)
+			},
+			wantErr: "failed to begin transaction: " + assert.AnError.Error(),
+		},
+		"failed to commit": {
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit().WillReturnError(assert.AnError)
+			},
+			wantErr: "failed to commit transaction: " + assert.AnError.Error(),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			primary, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer primary.Close()
+			replica, _, err := sqlmock.New()
+			require.NoError(t, err)
+			defer replica.Close()
+			tt.setup(mock)
+			sut := database.New(primary, replica)
+			// test
+			err = sut.Transaction(database.WithSession(context.Background()), func(ctx context.Context) error {
+				// reads and writes go through the transaction, nested transactions join it
+				tx := sut.Writer(ctx)
+				assert.IsType(t, &sql.Tx{}, tx)
+				assert.Same(t, tx, sut.Reader(ctx))
+				return sut.Transaction(ctx, func(nested context.Context) error {
+					assert.Same(t, tx, sut.Writer(nested))
+					return tt.fnErr
+				})
+			})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shota-tech/graphql/server/middleware/auth"
+)
+
+// validateAccessTokenScopes checks the scopes requested for an access token.
+// A token never grants more than the caller is granted itself.
+func validateAccessTokenScopes(principal auth.Principal, scopes []string) error {
+	if len(scopes) == 0 {
+		return errors.New("scopes are required")
+	}
+	for _, scope := range scopes {
+		if !isKnownScope(scope) {
+			return fmt.Errorf("unknown scope: %s", scope)
+		}
+		if !principal.HasScope(scope) {
+			return fmt.Errorf("scope is not granted: %s", scope)
+		}
+	}
+	return nil
+}
+
+func isKnownScope(scope string) bool {
+	for _, s := range auth.AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/shota-tech/graphql/server/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMutationResolver_CreateAccessToken(t *testing.T) {
+	tests := map[string]struct {
+		principal auth.Principal
+		input     model.CreateAccessTokenInput
+		wantErr   string
+	}{
+		"access tokens cannot create access tokens": {
+			principal: auth.Principal{UserID: "auth0|123456", Scopes: []string{auth.ScopeWriteUser}, Method: auth.MethodAccessToken, Role: model.RoleMember},
+			input:     model.CreateAccessTokenInput{Name: "ci", Scopes: []string{auth.ScopeWriteUser}},
+			wantErr:   "access tokens cannot create access tokens",
+		},
+		"scope is not granted": {
+			principal: auth.Principal{UserID: "auth0|123456", Scopes: []string{auth.ScopeWriteUser}, Method: auth.MethodJWT, Role: model.RoleMember},
+			input:     model.CreateAccessTokenInput{Name: "ci", Scopes: []string{auth.ScopeReadTasks}},
+			wantErr:   "scope is not granted: read:tasks",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := auth.ContextWithPrincipal(context.Background(), tt.principal)
+			// the checks fail before the repositories are used, so none are set
+			sut := &mutationResolver{&Resolver{Policy: policy.NewEngine(policy.DefaultRules)}}
+			got, err := sut.CreateAccessToken(ctx, tt.input)
+			assert.Nil(t, got)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageSize validates the requested page size of a connection.
+func pageSize(first *int) (int, error) {
+	if first == nil {
+		return defaultPageSize, nil
+	}
+	if *first < 1 || *first > maxPageSize {
+		return 0, fmt.Errorf("first must be between 1 and %d", maxPageSize)
+	}
+	return *first, nil
+}
+
+// newActivityConnection builds a page from activities fetched with limit+1 rows,
+// the extra row only telling whether a next page exists.
+func newActivityConnection(activities []*model.Activity, limit int) *model.ActivityConnection {
+	pageInfo := &model.PageInfo{}
+	if len(activities) > limit {
+		activities = activities[:limit]
+		pageInfo.HasNextPage = true
+	}
+	if len(activities) > 0 {
+		pageInfo.EndCursor = &activities[len(activities)-1].ID
+	}
+	return &model.ActivityConnection{
+		Nodes:    activities,
+		PageInfo: pageInfo,
+	}
+}
+
+// recordActivity appends an activity for the mutation made by the user.
+// Updates and undos without any changed field are not recorded and return nil.
+func (r *Resolver) recordActivity(
+	ctx context.Context,
+	userID string,
+	entityType model.EntityType,
+	entityID string,
+	taskID *string,
+	action model.ActivityAction,
+	changes []*model.FieldChange,
+) (*model.Activity, error) {
+	if action != model.ActivityActionCreate && len(changes) == 0 {
+		return nil, nil
+	}
+	activity := &model.Activity{
+		ID:         xid.New().String(),
+		UserID:     userID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		TaskID:     taskID,
+		Action:     action,
+		Changes:    changes,
+	}
+	if err := r.ActivityRepository.Store(ctx, activity); err != nil {
+		return nil, fmt.Errorf("failed to record activity: %w", err)
+	}
+	return activity, nil
+}
+
+// userChanges returns the fields which differ between before and after.
+// before is nil when the user is created.
+func userChanges(before, after *model.User) []*model.FieldChange {
+	var b model.User
+	if before != nil {
+		b = *before
+	}
+	var changes []*model.FieldChange
+	changes = appendChange(changes, "name", before != nil, b.Name, after.Name)
+	return changes
+}
+
+// taskChanges returns the fields which differ between before and after.
+// before is nil when the task is created.
+func taskChanges(before, after *model.Task) []*model.FieldChange {
+	var b model.Task
+	if before != nil {
+		b = *before
+	}
+	var changes []*model.FieldChange
+	changes = appendChange(changes, "text", before != nil, b.Text, after.Text)
+	changes = appendChange(changes, "status", before != nil, b.Status.String(), after.Status.String())
+	return changes
+}
+
+// todoChanges returns the fields which differ between before and after.
+// before is nil when the todo is created.
+func todoChanges(before, after *model.Todo) []*model.FieldChange {
+	var b model.Todo
+	if before != nil {
+		b = *before
+	}
+	var changes []*model.FieldChange
+	changes = appendChange(changes, "text", before != nil, b.Text, after.Text)
+	changes = appendChange(changes, "done", before != nil, strconv.FormatBool(b.Done), strconv.FormatBool(after.Done))
+	changes = appendChange(changes, "position", before != nil, strconv.Itoa(b.Position), strconv.Itoa(after.Position))
+	return changes
+}
+
+// accessTokenChanges returns the fields which differ between before and after.
+// before is nil when the access token is created. The token and its hash are never recorded.
+func accessTokenChanges(before, after *model.AccessToken) []*model.FieldChange {
+	var b model.AccessToken
+	if before != nil {
+		b = *before
+	}
+	var changes []*model.FieldChange
+	changes = appendChange(changes, "name", before != nil, b.Name, after.Name)
+	changes = appendChange(changes, "scopes", before != nil, strings.Join(b.Scopes, " "), strings.Join(after.Scopes, " "))
+	changes = appendChange(changes, "expiresAt", before != nil, formatTime(b.ExpiresAt), formatTime(after.ExpiresAt))
+	changes = appendChange(changes, "revokedAt", before != nil, formatTime(b.RevokedAt), formatTime(after.RevokedAt))
+	return changes
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func appendChange(changes []*model.FieldChange, field string, existed bool, before, after string) []*model.FieldChange {
+	if existed && before == after {
+		return changes
+	}
+	change := &model.FieldChange{
+		Field: field,
+		After: &after,
+	}
+	if existed {
+		change.Before = &before
+	}
+	return append(changes, change)
+}
+
+var (
+	errUndoNotSupported = errors.New("only updates of tasks and todos can be undone")
+	errUndoConflict     = errors.New("entity has been modified since the activity")
+	// errUndoPositionTaken is returned rather than leaving two todos at the same position.
+	errUndoPositionTaken = errors.New("position is taken by another todo, reorder the todos instead")
+)
+
+// undoTaskActivity reverts the activity on its task, which only those who may write the task may do.
+func (r *Resolver) undoTaskActivity(ctx context.Context, principal auth.Principal, activity *model.Activity) (*model.UndoPayload, error) {
+	task, err := r.lockTask(ctx, activity.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.authorizeOwner(principal, task.UserID); err != nil {
+		return nil, err
+	}
+	if err := r.checkLatest(ctx, activity); err != nil {
+		return nil, err
+	}
+	before := *task
+	if err := undoTask(task, activity.Changes); err != nil {
+		return nil, err
+	}
+	if err := r.TaskRepository.Store(ctx, task); err != nil {
+		return nil, err
+	}
+	undone, err := r.recordActivity(ctx, principal.UserID, model.EntityTypeTask, task.ID, &task.ID, model.ActivityActionUndo, taskChanges(&before, task))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.recordStatusTransition(ctx, task, &before.Status); err != nil {
+		return nil, err
+	}
+	return &model.UndoPayload{Activity: undone, Task: task}, nil
+}
+
+// undoTodoActivity reverts the activity on its todo, which only those who may write the task may do.
+func (r *Resolver) undoTodoActivity(ctx context.Context, principal auth.Principal, activity *model.Activity) (*model.UndoPayload, error) {
+	todo, err := r.lockTodo(ctx, activity.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.authorizeTask(ctx, principal, todo.TaskID); err != nil {
+		return nil, err
+	}
+	if err := r.checkLatest(ctx, activity); err != nil {
+		return nil, err
+	}
+	before := *todo
+	if err := undoTodo(todo, activity.Changes); err != nil {
+		return nil, err
+	}
+	if todo.Position != before.Position {
+		todos, err := r.TodoRepository.ListByTaskIDs(ctx, []string{todo.TaskID})
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPositionFree(todo, siblings(todos, todo.ParentID)); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.TodoRepository.Store(ctx, todo); err != nil {
+		return nil, err
+	}
+	undone, err := r.recordActivity(ctx, principal.UserID, model.EntityTypeTodo, todo.ID, &todo.TaskID, model.ActivityActionUndo, todoChanges(&before, todo))
+	if err != nil {
+		return nil, err
+	}
+	return &model.UndoPayload{Activity: undone, Todo: todo}, nil
+}
+
+// checkLatest returns errUndoConflict unless the activity is the latest of its entity.
+// The entity must be locked beforehand, so that an update of it either waits for the undo or is seen here.
+func (r *Resolver) checkLatest(ctx context.Context, activity *model.Activity) error {
+	latest, err := r.ActivityRepository.GetLatestByEntity(ctx, activity.EntityType, activity.EntityID)
+	if err != nil {
+		return err
+	}
+	if latest.ID != activity.ID {
+		return errUndoConflict
+	}
+	return nil
+}
+
+// undoTask reverts the changes on the task.
+// It fails when a field no longer holds the value the changes left it with.
+func undoTask(task *model.Task, changes []*model.FieldChange) error {
+	for _, change := range changes {
+		switch change.Field {
+		case "text":
+			if !isCurrent(change, task.Text) {
+				return errUndoConflict
+			}
+			task.Text = *change.Before
+		case "status":
+			if !isCurrent(change, task.Status.String()) {
+				return errUndoConflict
+			}
+			status := model.Status(*change.Before)
+			if !status.IsValid() {
+				return fmt.Errorf("%s is not a valid Status", status)
+			}
+			task.Status = status
+		default:
+			return fmt.Errorf("unknown task field: %s", change.Field)
+		}
+	}
+	return nil
+}
+
+// undoTodo reverts the changes on the todo.
+// It fails when a field no longer holds the value the changes left it with.
+func undoTodo(todo *model.Todo, changes []*model.FieldChange) error {
+	for _, change := range changes {
+		switch change.Field {
+		case "text":
+			if !isCurrent(change, todo.Text) {
+				return errUndoConflict
+			}
+			todo.Text = *change.Before
+		case "done":
+			if !isCurrent(change, strconv.FormatBool(todo.Done)) {
+				return errUndoConflict
+			}
+			done, err := strconv.ParseBool(*change.Before)
+			if err != nil {
+				return fmt.Errorf("failed to parse done: %w", err)
+			}
+			todo.Done = done
+		case "position":
+			if !isCurrent(change, strconv.Itoa(todo.Position)) {
+				return errUndoConflict
+			}
+			position, err := strconv.Atoi(*change.Before)
+			if err != nil {
+				return fmt.Errorf("failed to parse position: %w", err)
+			}
+			todo.Position = position
+		default:
+			return fmt.Errorf("unknown todo field: %s", change.Field)
+		}
+	}
+	return nil
+}
+
+// isCurrent reports whether the change can be reverted from the current value.
+func isCurrent(change *model.FieldChange, current string) bool {
+	return change.Before != nil && change.After != nil && *change.After == current
+}
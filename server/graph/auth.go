@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/shota-tech/graphql/server/policy"
+)
+
+var (
+	errUnauthenticated = errors.New("unauthenticated")
+	errInvalidScope    = errors.New("invalid scope")
+)
+
+type permission struct {
+	resource policy.Resource
+	action   policy.Action
+}
+
+// scopePermissions maps each scope onto what the role of the caller must be allowed.
+var scopePermissions = map[string]permission{
+	auth.ScopeReadTasks:  {policy.ResourceTask, policy.ActionRead},
+	auth.ScopeWriteTasks: {policy.ResourceTask, policy.ActionWrite},
+	auth.ScopeReadUser:   {policy.ResourceProfile, policy.ActionRead},
+	auth.ScopeWriteUser:  {policy.ResourceProfile, policy.ActionWrite},
+}
+
+// authorize returns the caller of the request when its token is granted the scope
+// and its role is allowed the matching permission.
+func (r *Resolver) authorize(ctx context.Context, scope string) (auth.Principal, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return auth.Principal{}, errUnauthenticated
+	}
+	if !principal.HasScope(scope) {
+		return auth.Principal{}, errInvalidScope
+	}
+	p, ok := scopePermissions[scope]
+	if !ok {
+		return auth.Principal{}, errInvalidScope
+	}
+	if err := r.Policy.Authorize(principal.Role, p.resource, p.action); err != nil {
+		return auth.Principal{}, err
+	}
+	return principal, nil
+}
+
+// authorizeAdmin is authorize for the operations on every user, which are allowed to admins only.
+func (r *Resolver) authorizeAdmin(ctx context.Context, scope string, action policy.Action) (auth.Principal, error) {
+	principal, err := r.authorize(ctx, scope)
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	if err := r.Policy.Authorize(principal.Role, policy.ResourceUsers, action); err != nil {
+		return auth.Principal{}, err
+	}
+	return principal, nil
+}
+
+// authorizeOwner returns policy.ErrForbidden unless the caller owns the entity,
+// admins being allowed on the entities of every user.
+func (r *Resolver) authorizeOwner(principal auth.Principal, ownerID string) error {
+	if ownerID == principal.UserID {
+		return nil
+	}
+	return r.Policy.Authorize(principal.Role, policy.ResourceUsers, policy.ActionWrite)
+}
+
+// authorizeTask returns the task when the caller may write it and its todos, see authorizeOwner.
+func (r *Resolver) authorizeTask(ctx context.Context, principal auth.Principal, taskID string) (*model.Task, error) {
+	task, err := r.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.authorizeOwner(principal, task.UserID); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// lockTaskToWrite is authorizeTask locking the task, which serializes the writes to the positions of its todos.
+func (r *Resolver) lockTaskToWrite(ctx context.Context, principal auth.Principal, taskID string) error {
+	task, err := r.lockTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	return r.authorizeOwner(principal, task.UserID)
+}
@@ -0,0 +1,22 @@
+package graph
+
+import "context"
+
+// invalidateResponses drops the responses cached for the user.
+func (r *Resolver) invalidateResponses(userID string) {
+	if r.ResponseCache != nil {
+		r.ResponseCache.Invalidate(userID)
+	}
+}
+
+// invalidateTaskResponses drops the responses cached for the owner of the task.
+// The change is already stored, so when the task can't be loaded its responses are left to expire.
+func (r *Resolver) invalidateTaskResponses(ctx context.Context, taskID string) {
+	if r.ResponseCache == nil {
+		return
+	}
+	thunk := r.Loaders.TaskLoader.Load(ctx, taskID)
+	if task, err := thunk(); err == nil {
+		r.ResponseCache.Invalidate(task.UserID)
+	}
+}
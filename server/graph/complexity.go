@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"time"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+)
+
+// Expected sizes of the lists which are not paginated.
+// A list costs its expected size times the cost of an element, so nested lists multiply.
+const (
+	expectedTasks        = 20
+	expectedTodos        = 10
+	expectedUsers        = 50
+	expectedAccessTokens = 5
+)
+
+// NewComplexityRoot returns the complexity functions of the fields which return lists,
+// every other field costs 1 plus its selection.
+func NewComplexityRoot() ComplexityRoot {
+	var c ComplexityRoot
+
+	c.Query.FetchTasks = listComplexity(expectedTasks)
+	c.Query.AccessTokens = listComplexity(expectedAccessTokens)
+	c.Query.Users = func(childComplexity int, _ *model.UserFilter) int {
+		return listComplexity(expectedUsers)(childComplexity)
+	}
+	c.Query.BoardActivity = pageComplexity
+	c.Query.BoardStats = func(childComplexity int, from time.Time, to time.Time) int {
+		n := int(to.Sub(from).Hours()/24) + 1
+		if n < 1 || n > maxStatsDays {
+			n = maxStatsDays
+		}
+		return listComplexity(n)(childComplexity)
+	}
+
+	c.User.Tasks = listComplexity(expectedTasks)
+	c.Task.Todos = listComplexity(expectedTodos)
+	c.Task.Activity = pageComplexity
+	c.Todo.Children = listComplexity(expectedTodos)
+
+	c.Mutation.ReorderTodos = func(childComplexity int, _ string, _ *string, ids []string) int {
+		return listComplexity(len(ids))(childComplexity)
+	}
+	return c
+}
+
+func listComplexity(size int) func(childComplexity int) int {
+	return func(childComplexity int) int {
+		return 1 + size*childComplexity
+	}
+}
+
+// pageComplexity weights a connection by the requested page size.
+func pageComplexity(childComplexity int, first *int, _ *string) int {
+	size := defaultPageSize
+	if first != nil && *first > 0 && *first <= maxPageSize {
+		size = *first
+	}
+	return listComplexity(size)(childComplexity)
+}
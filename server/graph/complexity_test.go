@@ -0,0 +1,65 @@
+package graph_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/shota-tech/graphql/server/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewComplexityRoot(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  int
+	}{
+		"scalar fields": {
+			query: `{ fetchUser { id name } }`,
+			want:  3,
+		},
+		"list weighted by expected size": {
+			query: `{ fetchTasks { id text status user { id name } } }`,
+			want:  1 + 20*6,
+		},
+		"nested lists multiply": {
+			query: `{ fetchTasks { todos { children { id } } } }`,
+			want:  1 + 20*(1+10*(1+10*1)),
+		},
+		"page weighted by first": {
+			query: `{ boardActivity(first: 50) { nodes { id } } }`,
+			want:  1 + 50*2,
+		},
+		"stats weighted by days": {
+			query: `{ boardStats(from: "2023-03-01T00:00:00Z", to: "2023-03-31T00:00:00Z") { averageCycleTimeSeconds } }`,
+			want:  1 + 31*1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := handler.New(graph.NewExecutableSchema(graph.Config{
+				Resolvers:  &graph.Resolver{},
+				Complexity: graph.NewComplexityRoot(),
+			}))
+			srv.AddTransport(transport.POST{})
+			srv.Use(extension.FixedComplexityLimit(10000))
+			var got int
+			// stop operations once the complexity is calculated, the resolvers have no repositories.
+			srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+				got = extension.GetComplexityStats(ctx).Complexity
+				return graphql.OneShot(graphql.ErrorResponse(ctx, "stopped"))
+			})
+			// test
+			c := client.New(srv)
+			var resp interface{}
+			err := c.Post(tt.query, &resp)
+			require.Error(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package graph_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/shota-tech/graphql/server/graph"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/loader"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/shota-tech/graphql/server/middleware/respcache"
+	"github.com/shota-tech/graphql/server/policy"
+	"github.com/shota-tech/graphql/server/repository/memory"
+	"github.com/stretchr/testify/require"
+)
+
+// principals of the requests, their users are seeded by newHarness.
+var (
+	member = auth.Principal{UserID: "auth0|member", Scopes: auth.AllScopes, Method: auth.MethodJWT, Role: model.RoleMember}
+	admin  = auth.Principal{UserID: "auth0|admin", Scopes: auth.AllScopes, Method: auth.MethodJWT, Role: model.RoleAdmin}
+	viewer = auth.Principal{UserID: "auth0|viewer", Scopes: auth.AllScopes, Method: auth.MethodJWT, Role: model.RoleViewer}
+	// stranger is a member who owns nothing and is not seeded.
+	stranger = auth.Principal{UserID: "auth0|stranger", Scopes: auth.AllScopes, Method: auth.MethodJWT, Role: model.RoleMember}
+	// readOnly is the member calling with a token granted the read scopes only.
+	readOnly = auth.Principal{UserID: "auth0|member", Scopes: []string{auth.ScopeReadTasks, auth.ScopeReadUser}, Method: auth.MethodAccessToken, Role: model.RoleMember}
+)
+
+// harness serves the schema on in-memory repositories, as the server does without the HTTP middlewares.
+type harness struct {
+	t                 *testing.T
+	client            *client.Client
+	users             *memory.UserRepository
+	tasks             *memory.TaskRepository
+	todos             *memory.TodoRepository
+	activities        *memory.ActivityRepository
+	statusTransitions *memory.StatusTransitionRepository
+	accessTokens      *memory.AccessTokenRepository
+}
+
+func newHarness(t *testing.T) *harness {
+	db := memory.NewDB()
+	h := &harness{
+		t:                 t,
+		users:             memory.NewUserRepository(db),
+		tasks:             memory.NewTaskRepository(db),
+		todos:             memory.NewTodoRepository(db),
+		activities:        memory.NewActivityRepository(db),
+		statusTransitions: memory.NewStatusTransitionRepository(db),
+		accessTokens:      memory.NewAccessTokenRepository(db),
+	}
+	resolver := &graph.Resolver{
+		Loaders: loader.NewLoaders(
+			loader.NewUserLoader(h.users),
+			loader.NewTaskLoader(h.tasks),
+			loader.NewTodoLoader(h.todos),
+		),
+		Policy:                     policy.NewEngine(policy.DefaultRules),
+		Transactor:                 db,
+		UserRepository:             h.users,
+		TaskRepository:             h.tasks,
+		TodoRepository:             h.todos,
+		ActivityRepository:         h.activities,
+		StatusTransitionRepository: h.statusTransitions,
+		AccessTokenRepository:      h.accessTokens,
+		ResponseCache:              respcache.New(100, 0),
+	}
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{
+		Resolvers:  resolver,
+		Complexity: graph.NewComplexityRoot(),
+	}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(respcache.Extension{Cache: resolver.ResponseCache, Fields: []string{"fetchTasks"}})
+	h.client = client.New(srv)
+	h.seed(
+		&model.User{ID: member.UserID, Name: "member", Role: model.RoleMember},
+		&model.User{ID: admin.UserID, Name: "admin", Role: model.RoleAdmin},
+		&model.User{ID: viewer.UserID, Name: "viewer", Role: model.RoleViewer},
+	)
+	return h
+}
+
+// seed stores the records through the repositories.
+func (h *harness) seed(records ...interface{}) {
+	ctx := context.Background()
+	for _, record := range records {
+		var err error
+		switch r := record.(type) {
+		case *model.User:
+			err = h.users.Store(ctx, r)
+		case *model.Task:
+			err = h.tasks.Store(ctx, r)
+		case *model.Todo:
+			err = h.todos.Store(ctx, r)
+		case *model.Activity:
+			err = h.activities.Store(ctx, r)
+		case *model.StatusTransition:
+			err = h.statusTransitions.Store(ctx, r)
+		case *model.AccessToken:
+			err = h.accessTokens.Store(ctx, r)
+		default:
+			h.t.Fatalf("unknown record: %T", record)
+		}
+		require.NoError(h.t, err)
+	}
+}
+
+// response is the outcome of an operation, data being the JSON of its data.
+// The data of failed operations depends on the null propagation of gqlgen and is not worth asserting.
+type response struct {
+	data   string
+	errors []string
+}
+
+// do runs the operation as the principal, anonymously when it is nil.
+func (h *harness) do(principal *auth.Principal, query string, variables map[string]interface{}) response {
+	options := []client.Option{func(r *client.Request) {
+		if principal != nil {
+			r.HTTP = r.HTTP.WithContext(auth.ContextWithPrincipal(r.HTTP.Context(), *principal))
+		}
+	}}
+	for name, value := range variables {
+		options = append(options, client.Var(name, value))
+	}
+	resp, err := h.client.RawPost(query, options...)
+	require.NoError(h.t, err)
+	data, err := json.Marshal(resp.Data)
+	require.NoError(h.t, err)
+	var errs []struct {
+		Message string `json:"message"`
+	}
+	if len(resp.Errors) > 0 {
+		require.NoError(h.t, json.Unmarshal(resp.Errors, &errs))
+	}
+	var messages []string
+	for _, e := range errs {
+		messages = append(messages, e.Message)
+	}
+	return response{data: string(data), errors: messages}
+}
+
+// seedBoard stores the board of member: task1 in progress with todo1, its child todo3 and the done todo2,
+// task2 done with its status history, and task3 of admin. task1 and todo1 have an update to undo.
+func (h *harness) seedBoard() {
+	h.seed(
+		&model.Task{ID: "task1", Text: "task1", Status: model.StatusInProgress, UserID: member.UserID},
+		&model.Task{ID: "task2", Text: "task2", Status: model.StatusDone, UserID: member.UserID},
+		&model.Task{ID: "task3", Text: "task3", Status: model.StatusTodo, UserID: admin.UserID},
+		&model.Todo{ID: "todo1", Text: "todo1", TaskID: "task1", Position: 0},
+		&model.Todo{ID: "todo2", Text: "todo2", Done: true, TaskID: "task1", Position: 1},
+		&model.Todo{ID: "todo3", Text: "todo3", TaskID: "task1", ParentID: ptr("todo1"), Position: 0},
+		&model.StatusTransition{ID: "transition1", TaskID: "task2", To: model.StatusTodo, CreatedAt: day},
+		&model.StatusTransition{ID: "transition2", TaskID: "task2", From: ptr(model.StatusTodo), To: model.StatusInProgress, CreatedAt: day.Add(time.Hour)},
+		&model.StatusTransition{ID: "transition3", TaskID: "task2", From: ptr(model.StatusInProgress), To: model.StatusDone, CreatedAt: day.Add(25 * time.Hour)},
+		&model.Activity{ID: "activity1", UserID: member.UserID, EntityType: model.EntityTypeTask, EntityID: "task1", TaskID: ptr("task1"), Action: model.ActivityActionCreate,
+			Changes: []*model.FieldChange{{Field: "text", After: ptr("task")}}, CreatedAt: day},
+		&model.Activity{ID: "activity2", UserID: member.UserID, EntityType: model.EntityTypeTask, EntityID: "task1", TaskID: ptr("task1"), Action: model.ActivityActionUpdate,
+			Changes: []*model.FieldChange{{Field: "text", Before: ptr("task"), After: ptr("task1")}}, CreatedAt: day.Add(time.Hour)},
+		&model.Activity{ID: "activity3", UserID: member.UserID, EntityType: model.EntityTypeTodo, EntityID: "todo2", TaskID: ptr("task1"), Action: model.ActivityActionUpdate,
+			Changes: []*model.FieldChange{{Field: "done", Before: ptr("false"), After: ptr("true")}}, CreatedAt: day.Add(2 * time.Hour)},
+		&model.Activity{ID: "activity4", UserID: admin.UserID, EntityType: model.EntityTypeTask, EntityID: "task3", TaskID: ptr("task3"), Action: model.ActivityActionCreate,
+			Changes: []*model.FieldChange{{Field: "text", After: ptr("task3")}}, CreatedAt: day},
+		&model.AccessToken{ID: "token1", UserID: member.UserID, Name: "ci", TokenHash: "hash1", Scopes: []string{auth.ScopeReadTasks}, CreatedAt: day},
+		&model.AccessToken{ID: "token2", UserID: admin.UserID, Name: "admin", TokenHash: "hash2", Scopes: auth.AllScopes, CreatedAt: day},
+	)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+var day = time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
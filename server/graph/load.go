@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+)
+
+// The loaders are shared by every request, a batch runs with the ctx of the request which opened it
+// and may read a replica although the current request has written. The mutations which store what
+// they have read therefore read through these helpers within their transaction, which reads the primary.
+
+func (r *Resolver) getUser(ctx context.Context, id string) (*model.User, error) {
+	user, err := r.UserRepository.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("user not found: %s", id)
+	}
+	return user, err
+}
+
+func (r *Resolver) getTask(ctx context.Context, id string) (*model.Task, error) {
+	task, err := r.TaskRepository.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("task not found: %s", id)
+	}
+	return task, err
+}
+
+func (r *Resolver) getTodo(ctx context.Context, id string) (*model.Todo, error) {
+	todo, err := r.TodoRepository.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("todo not found: %s", id)
+	}
+	return todo, err
+}
+
+// lockTask is getTask locking the task until the transaction ends, for the writes which depend on its current state.
+func (r *Resolver) lockTask(ctx context.Context, id string) (*model.Task, error) {
+	task, err := r.TaskRepository.GetForUpdate(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("task not found: %s", id)
+	}
+	return task, err
+}
+
+// lockTodo is getTodo locking the todo until the transaction ends, for the writes which depend on its current state.
+func (r *Resolver) lockTodo(ctx context.Context, id string) (*model.Todo, error) {
+	todo, err := r.TodoRepository.GetForUpdate(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("todo not found: %s", id)
+	}
+	return todo, err
+}
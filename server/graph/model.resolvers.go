@@ -6,18 +6,24 @@ package graph
 
 import (
 	"context"
-	"errors"
 
 	"github.com/shota-tech/graphql/server/graph/model"
 	"github.com/shota-tech/graphql/server/middleware/auth"
 )
 
+// User is the resolver for the user field.
+func (r *activityResolver) User(ctx context.Context, obj *model.Activity) (*model.User, error) {
+	if _, err := r.authorize(ctx, auth.ScopeReadUser); err != nil {
+		return nil, err
+	}
+	thunk := r.Loaders.UserLoader.Load(ctx, obj.UserID)
+	return thunk()
+}
+
 // User is the resolver for the user field.
 func (r *taskResolver) User(ctx context.Context, obj *model.Task) (*model.User, error) {
-	token := auth.TokenFromContext(ctx)
-	claims := token.CustomClaims.(*auth.CustomClaims)
-	if !claims.HasScope(auth.ScopeReadUser) {
-		return nil, errors.New("invalid scope")
+	if _, err := r.authorize(ctx, auth.ScopeReadUser); err != nil {
+		return nil, err
 	}
 	thunk := r.Loaders.UserLoader.Load(ctx, obj.UserID)
 	return thunk()
@@ -25,37 +31,89 @@ func (r *taskResolver) User(ctx context.Context, obj *model.Task) (*model.User,
 
 // Todos is the resolver for the todos field.
 func (r *taskResolver) Todos(ctx context.Context, obj *model.Task) ([]*model.Todo, error) {
-	token := auth.TokenFromContext(ctx)
-	claims := token.CustomClaims.(*auth.CustomClaims)
-	if !claims.HasScope(auth.ScopeReadTasks) {
-		return nil, errors.New("invalid scope")
+	if _, err := r.authorize(ctx, auth.ScopeReadTasks); err != nil {
+		return nil, err
 	}
 	thunk := r.Loaders.TodoLoaderByTaskID.Load(ctx, obj.ID)
+	todos, err := thunk()
+	if err != nil {
+		return nil, err
+	}
+	// sub-todos are listed by the children of their parent
+	return siblings(todos, nil), nil
+}
+
+// Progress is the resolver for the progress field.
+func (r *taskResolver) Progress(ctx context.Context, obj *model.Task) (*model.Progress, error) {
+	if _, err := r.authorize(ctx, auth.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	thunk := r.Loaders.ProgressLoaderByTaskID.Load(ctx, obj.ID)
 	return thunk()
 }
 
+// Activity is the resolver for the activity field.
+func (r *taskResolver) Activity(ctx context.Context, obj *model.Task, first *int, after *string) (*model.ActivityConnection, error) {
+	if _, err := r.authorize(ctx, auth.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	limit, err := pageSize(first)
+	if err != nil {
+		return nil, err
+	}
+	var cursor string
+	if after != nil {
+		cursor = *after
+	}
+	activities, err := r.ActivityRepository.ListByTaskID(ctx, obj.ID, limit+1, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return newActivityConnection(activities, limit), nil
+}
+
 // Task is the resolver for the task field.
 func (r *todoResolver) Task(ctx context.Context, obj *model.Todo) (*model.Task, error) {
-	token := auth.TokenFromContext(ctx)
-	claims := token.CustomClaims.(*auth.CustomClaims)
-	if !claims.HasScope(auth.ScopeReadTasks) {
-		return nil, errors.New("invalid scope")
+	if _, err := r.authorize(ctx, auth.ScopeReadTasks); err != nil {
+		return nil, err
 	}
 	thunk := r.Loaders.TaskLoader.Load(ctx, obj.TaskID)
 	return thunk()
 }
 
+// Parent is the resolver for the parent field.
+func (r *todoResolver) Parent(ctx context.Context, obj *model.Todo) (*model.Todo, error) {
+	if _, err := r.authorize(ctx, auth.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+	thunk := r.Loaders.TodoLoader.Load(ctx, *obj.ParentID)
+	return thunk()
+}
+
+// Children is the resolver for the children field.
+func (r *todoResolver) Children(ctx context.Context, obj *model.Todo) ([]*model.Todo, error) {
+	if _, err := r.authorize(ctx, auth.ScopeReadTasks); err != nil {
+		return nil, err
+	}
+	thunk := r.Loaders.TodoLoaderByParentID.Load(ctx, obj.ID)
+	return thunk()
+}
+
 // Tasks is the resolver for the tasks field.
 func (r *userResolver) Tasks(ctx context.Context, obj *model.User) ([]*model.Task, error) {
-	token := auth.TokenFromContext(ctx)
-	claims := token.CustomClaims.(*auth.CustomClaims)
-	if !claims.HasScope(auth.ScopeReadTasks) {
-		return nil, errors.New("invalid scope")
+	if _, err := r.authorize(ctx, auth.ScopeReadTasks); err != nil {
+		return nil, err
 	}
 	thunk := r.Loaders.TaskLoaderByUserID.Load(ctx, obj.ID)
 	return thunk()
 }
 
+// Activity returns ActivityResolver implementation.
+func (r *Resolver) Activity() ActivityResolver { return &activityResolver{r} }
+
 // Task returns TaskResolver implementation.
 func (r *Resolver) Task() TaskResolver { return &taskResolver{r} }
 
@@ -65,6 +123,7 @@ func (r *Resolver) Todo() TodoResolver { return &todoResolver{r} }
 // User returns UserResolver implementation.
 func (r *Resolver) User() UserResolver { return &userResolver{r} }
 
+type activityResolver struct{ *Resolver }
 type taskResolver struct{ *Resolver }
 type todoResolver struct{ *Resolver }
 type userResolver struct{ *Resolver }
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type Activity struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"userId"`
+	EntityType EntityType     `json:"entityType"`
+	EntityID   string         `json:"entityID"`
+	TaskID     *string        `json:"taskId"`
+	Action     ActivityAction `json:"action"`
+	Changes    []*FieldChange `json:"changes"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
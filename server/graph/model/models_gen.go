@@ -6,21 +6,78 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 )
 
+type ActivityConnection struct {
+	Nodes    []*Activity `json:"nodes"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type BoardStats struct {
+	CountsByStatus          []*StatusCount         `json:"countsByStatus"`
+	Throughput              []*DailyCount          `json:"throughput"`
+	AverageCycleTimeSeconds *float64               `json:"averageCycleTimeSeconds"`
+	CumulativeFlow          []*CumulativeFlowPoint `json:"cumulativeFlow"`
+}
+
+type CreateAccessTokenInput struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type CreateAccessTokenPayload struct {
+	AccessToken *AccessToken `json:"accessToken"`
+	Token       string       `json:"token"`
+}
+
 type CreateTaskInput struct {
 	Text string `json:"text"`
 }
 
 type CreateTodoInput struct {
-	Text   string `json:"text"`
-	TaskID string `json:"taskID"`
+	Text     string  `json:"text"`
+	TaskID   string  `json:"taskID"`
+	ParentID *string `json:"parentID"`
 }
 
 type CreateUserInput struct {
 	Name string `json:"name"`
 }
 
+type CumulativeFlowPoint struct {
+	Date   time.Time      `json:"date"`
+	Counts []*StatusCount `json:"counts"`
+}
+
+type DailyCount struct {
+	Date  time.Time `json:"date"`
+	Count int       `json:"count"`
+}
+
+type FieldChange struct {
+	Field  string  `json:"field"`
+	Before *string `json:"before"`
+	After  *string `json:"after"`
+}
+
+type PageInfo struct {
+	EndCursor   *string `json:"endCursor"`
+	HasNextPage bool    `json:"hasNextPage"`
+}
+
+type StatusCount struct {
+	Status Status `json:"status"`
+	Count  int    `json:"count"`
+}
+
+type UndoPayload struct {
+	Activity *Activity `json:"activity"`
+	Task     *Task     `json:"task"`
+	Todo     *Todo     `json:"todo"`
+}
+
 type UpdateTaskInput struct {
 	ID     string  `json:"id"`
 	Text   *string `json:"text"`
@@ -33,6 +90,147 @@ type UpdateTodoInput struct {
 	Done *bool   `json:"done"`
 }
 
+type UpdateUserRoleInput struct {
+	ID   string `json:"id"`
+	Role Role   `json:"role"`
+}
+
+type UserFilter struct {
+	Role  *Role   `json:"role"`
+	Query *string `json:"query"`
+}
+
+type ActivityAction string
+
+const (
+	ActivityActionCreate ActivityAction = "CREATE"
+	ActivityActionUpdate ActivityAction = "UPDATE"
+	ActivityActionUndo   ActivityAction = "UNDO"
+)
+
+var AllActivityAction = []ActivityAction{
+	ActivityActionCreate,
+	ActivityActionUpdate,
+	ActivityActionUndo,
+}
+
+func (e ActivityAction) IsValid() bool {
+	switch e {
+	case ActivityActionCreate, ActivityActionUpdate, ActivityActionUndo:
+		return true
+	}
+	return false
+}
+
+func (e ActivityAction) String() string {
+	return string(e)
+}
+
+func (e *ActivityAction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ActivityAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ActivityAction", str)
+	}
+	return nil
+}
+
+func (e ActivityAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type EntityType string
+
+const (
+	EntityTypeUser        EntityType = "USER"
+	EntityTypeTask        EntityType = "TASK"
+	EntityTypeTodo        EntityType = "TODO"
+	EntityTypeAccessToken EntityType = "ACCESS_TOKEN"
+)
+
+var AllEntityType = []EntityType{
+	EntityTypeUser,
+	EntityTypeTask,
+	EntityTypeTodo,
+	EntityTypeAccessToken,
+}
+
+func (e EntityType) IsValid() bool {
+	switch e {
+	case EntityTypeUser, EntityTypeTask, EntityTypeTodo, EntityTypeAccessToken:
+		return true
+	}
+	return false
+}
+
+func (e EntityType) String() string {
+	return string(e)
+}
+
+func (e *EntityType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EntityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EntityType", str)
+	}
+	return nil
+}
+
+func (e EntityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Role string
+
+const (
+	RoleAdmin  Role = "ADMIN"
+	RoleMember Role = "MEMBER"
+	RoleViewer Role = "VIEWER"
+)
+
+var AllRole = []Role{
+	RoleAdmin,
+	RoleMember,
+	RoleViewer,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleAdmin, RoleMember, RoleViewer:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type Status string
 
 const (
@@ -0,0 +1,15 @@
+package model
+
+type Progress struct {
+	TaskID string `json:"taskId"`
+	Done   int    `json:"done"`
+	Total  int    `json:"total"`
+}
+
+// Percent returns the percentage of done todos, or 0 when there are no todos.
+func (p *Progress) Percent() float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return float64(p.Done) / float64(p.Total) * 100
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type StatusTransition struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"taskId"`
+	From      *Status   `json:"from"`
+	To        Status    `json:"to"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -1,8 +1,11 @@
 package model
 
+import "time"
+
 type Task struct {
-	ID     string `json:"id"`
-	Text   string `json:"text"`
-	Status Status `json:"status"`
-	UserID string `json:"userId"`
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	Status    Status    `json:"status"`
+	UserID    string    `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
 }
@@ -1,8 +1,10 @@
 package model
 
 type Todo struct {
-	ID     string `json:"id"`
-	Text   string `json:"text"`
-	Done   bool   `json:"done"`
-	TaskID string `json:"taskId"`
+	ID       string  `json:"id"`
+	Text     string  `json:"text"`
+	Done     bool    `json:"done"`
+	TaskID   string  `json:"taskId"`
+	ParentID *string `json:"parentId"`
+	Position int     `json:"position"`
 }
@@ -7,113 +7,384 @@ package graph
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/rs/xid"
 	"github.com/shota-tech/graphql/server/graph/model"
 	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/shota-tech/graphql/server/policy"
+	"github.com/shota-tech/graphql/server/repository"
 )
 
 // CreateUser is the resolver for the createUser field.
 func (r *mutationResolver) CreateUser(ctx context.Context, input model.CreateUserInput) (*model.User, error) {
-	token := auth.TokenFromContext(ctx)
-	claims := token.CustomClaims.(*auth.CustomClaims)
-	if !claims.HasScope(auth.ScopeWriteUser) {
-		return nil, errors.New("invalid scope")
-	}
-	user := &model.User{
-		ID:   token.RegisteredClaims.Subject,
-		Name: input.Name,
+	principal, err := r.authorize(ctx, auth.ScopeWriteUser)
+	if err != nil {
+		return nil, err
 	}
-	if err := r.UserRepository.Store(ctx, user); err != nil {
+	var user *model.User
+	// an existing user is only renamed, its role and profile are left to the admins and provisioning
+	err = r.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		before, err := r.UserRepository.Get(ctx, principal.UserID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		action := model.ActivityActionCreate
+		if before != nil {
+			action = model.ActivityActionUpdate
+			renamed := *before
+			renamed.Name = input.Name
+			user = &renamed
+			err = r.UserRepository.UpdateName(ctx, user.ID, user.Name)
+		} else {
+			user = &model.User{ID: principal.UserID, Name: input.Name, Role: model.RoleMember}
+			err = r.UserRepository.Store(ctx, user)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = r.recordActivity(ctx, user.ID, model.EntityTypeUser, user.ID, nil, action, userChanges(before, user))
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
+	r.invalidateResponses(user.ID)
 	return user, nil
 }
 
 // CreateTask is the resolver for the createTask field.
 func (r *mutationResolver) CreateTask(ctx context.Context, input model.CreateTaskInput) (*model.Task, error) {
-	token := auth.TokenFromContext(ctx)
-	claims := token.CustomClaims.(*auth.CustomClaims)
-	if !claims.HasScope(auth.ScopeWriteTasks) {
-		return nil, errors.New("invalid scope")
+	principal, err := r.authorize(ctx, auth.ScopeWriteTasks)
+	if err != nil {
+		return nil, err
 	}
 	task := &model.Task{
 		ID:     xid.New().String(),
 		Text:   input.Text,
 		Status: model.StatusTodo,
-		UserID: token.RegisteredClaims.Subject,
+		UserID: principal.UserID,
 	}
-	if err := r.TaskRepository.Store(ctx, task); err != nil {
+	// the history of the task is recorded along with it
+	err = r.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := r.TaskRepository.Store(ctx, task); err != nil {
+			return err
+		}
+		if _, err := r.recordActivity(ctx, task.UserID, model.EntityTypeTask, task.ID, &task.ID, model.ActivityActionCreate, taskChanges(nil, task)); err != nil {
+			return err
+		}
+		return r.recordStatusTransition(ctx, task, nil)
+	})
+	if err != nil {
 		return nil, err
 	}
+	r.invalidateResponses(task.UserID)
 	return task, nil
 }
 
 // UpdateTask is the resolver for the updateTask field.
 func (r *mutationResolver) UpdateTask(ctx context.Context, input model.UpdateTaskInput) (*model.Task, error) {
-	token := auth.TokenFromContext(ctx)
-	claims := token.CustomClaims.(*auth.CustomClaims)
-	if !claims.HasScope(auth.ScopeWriteTasks) {
-		return nil, errors.New("invalid scope")
-	}
-	thunk := r.Loaders.TaskLoader.Load(ctx, input.ID)
-	task, err := thunk()
+	principal, err := r.authorize(ctx, auth.ScopeWriteTasks)
 	if err != nil {
 		return nil, err
 	}
-	if input.Text != nil {
-		task.Text = *input.Text
-	}
-	if input.Status != nil {
-		task.Status = *input.Status
-	}
-	if err := r.TaskRepository.Store(ctx, task); err != nil {
+	var task *model.Task
+	err = r.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if task, err = r.authorizeTask(ctx, principal, input.ID); err != nil {
+			return err
+		}
+		before := *task
+		if input.Text != nil {
+			task.Text = *input.Text
+		}
+		if input.Status != nil {
+			task.Status = *input.Status
+		}
+		if err := r.TaskRepository.Store(ctx, task); err != nil {
+			return err
+		}
+		if _, err := r.recordActivity(ctx, principal.UserID, model.EntityTypeTask, task.ID, &task.ID, model.ActivityActionUpdate, taskChanges(&before, task)); err != nil {
+			return err
+		}
+		return r.recordStatusTransition(ctx, task, &before.Status)
+	})
+	if err != nil {
 		return nil, err
 	}
+	r.invalidateResponses(task.UserID)
 	return task, nil
 }
 
 // CreateTodo is the resolver for the createTodo field.
 func (r *mutationResolver) CreateTodo(ctx context.Context, input model.CreateTodoInput) (*model.Todo, error) {
-	token := auth.TokenFromContext(ctx)
-	claims := token.CustomClaims.(*auth.CustomClaims)
-	if !claims.HasScope(auth.ScopeWriteTasks) {
-		return nil, errors.New("invalid scope")
-	}
-	todo := &model.Todo{
-		ID:     xid.New().String(),
-		Text:   input.Text,
-		Done:   false,
-		TaskID: input.TaskID,
+	principal, err := r.authorize(ctx, auth.ScopeWriteTasks)
+	if err != nil {
+		return nil, err
 	}
-	if err := r.TodoRepository.Store(ctx, todo); err != nil {
+	var todo *model.Todo
+	// the task is locked so that the todos created at the same time are given different positions
+	err = r.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := r.lockTaskToWrite(ctx, principal, input.TaskID); err != nil {
+			return err
+		}
+		if input.ParentID != nil {
+			parent, err := r.getTodo(ctx, *input.ParentID)
+			if err != nil {
+				return err
+			}
+			if parent.TaskID != input.TaskID {
+				return errors.New("parent todo belongs to another task")
+			}
+		}
+		todos, err := r.TodoRepository.ListByTaskIDs(ctx, []string{input.TaskID})
+		if err != nil {
+			return err
+		}
+		todo = &model.Todo{
+			ID:       xid.New().String(),
+			Text:     input.Text,
+			Done:     false,
+			TaskID:   input.TaskID,
+			ParentID: input.ParentID,
+			Position: nextPosition(siblings(todos, input.ParentID)),
+		}
+		if err := r.TodoRepository.Store(ctx, todo); err != nil {
+			return err
+		}
+		_, err = r.recordActivity(ctx, principal.UserID, model.EntityTypeTodo, todo.ID, &todo.TaskID, model.ActivityActionCreate, todoChanges(nil, todo))
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
+	r.invalidateTaskResponses(ctx, todo.TaskID)
 	return todo, nil
 }
 
 // UpdateTodo is the resolver for the updateTodo field.
 func (r *mutationResolver) UpdateTodo(ctx context.Context, input model.UpdateTodoInput) (*model.Todo, error) {
-	token := auth.TokenFromContext(ctx)
-	claims := token.CustomClaims.(*auth.CustomClaims)
-	if !claims.HasScope(auth.ScopeWriteTasks) {
-		return nil, errors.New("invalid scope")
+	principal, err := r.authorize(ctx, auth.ScopeWriteTasks)
+	if err != nil {
+		return nil, err
 	}
-	thunk := r.Loaders.TodoLoader.Load(ctx, input.ID)
-	todo, err := thunk()
+	var todo *model.Todo
+	err = r.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if todo, err = r.getTodo(ctx, input.ID); err != nil {
+			return err
+		}
+		if _, err := r.authorizeTask(ctx, principal, todo.TaskID); err != nil {
+			return err
+		}
+		before := *todo
+		if input.Text != nil {
+			todo.Text = *input.Text
+		}
+		if input.Done != nil {
+			todo.Done = *input.Done
+		}
+		if err := r.TodoRepository.Store(ctx, todo); err != nil {
+			return err
+		}
+		_, err = r.recordActivity(ctx, principal.UserID, model.EntityTypeTodo, todo.ID, &todo.TaskID, model.ActivityActionUpdate, todoChanges(&before, todo))
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	if input.Text != nil {
-		todo.Text = *input.Text
+	r.invalidateTaskResponses(ctx, todo.TaskID)
+	return todo, nil
+}
+
+// ReorderTodos is the resolver for the reorderTodos field.
+func (r *mutationResolver) ReorderTodos(ctx context.Context, taskID string, parentID *string, ids []string) ([]*model.Todo, error) {
+	principal, err := r.authorize(ctx, auth.ScopeWriteTasks)
+	if err != nil {
+		return nil, err
 	}
-	if input.Done != nil {
-		todo.Done = *input.Done
+	var ordered []*model.Todo
+	// the order is applied as a whole or not at all, the task being locked against concurrent reorders and creations
+	err = r.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := r.lockTaskToWrite(ctx, principal, taskID); err != nil {
+			return err
+		}
+		todos, err := r.TodoRepository.ListByTaskIDs(ctx, []string{taskID})
+		if err != nil {
+			return err
+		}
+		if ordered, err = orderTodos(siblings(todos, parentID), ids); err != nil {
+			return err
+		}
+		for position, todo := range ordered {
+			if todo.Position == position {
+				continue
+			}
+			before := *todo
+			todo.Position = position
+			if err := r.TodoRepository.Store(ctx, todo); err != nil {
+				return err
+			}
+			if _, err := r.recordActivity(ctx, principal.UserID, model.EntityTypeTodo, todo.ID, &todo.TaskID, model.ActivityActionUpdate, todoChanges(&before, todo)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if err := r.TodoRepository.Store(ctx, todo); err != nil {
+	r.invalidateTaskResponses(ctx, taskID)
+	return ordered, nil
+}
+
+// Undo is the resolver for the undo field.
+func (r *mutationResolver) Undo(ctx context.Context, activityID string) (*model.UndoPayload, error) {
+	principal, err := r.authorize(ctx, auth.ScopeWriteTasks)
+	if err != nil {
 		return nil, err
 	}
-	return todo, nil
+	var payload *model.UndoPayload
+	// the activities and the entity are read in the transaction, from the primary
+	err = r.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		activity, err := r.ActivityRepository.Get(ctx, activityID)
+		if err != nil {
+			return err
+		}
+		if activity.Action == model.ActivityActionCreate {
+			return errUndoNotSupported
+		}
+		switch activity.EntityType {
+		case model.EntityTypeTask:
+			payload, err = r.undoTaskActivity(ctx, principal, activity)
+		case model.EntityTypeTodo:
+			payload, err = r.undoTodoActivity(ctx, principal, activity)
+		default:
+			err = errUndoNotSupported
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if payload.Task != nil {
+		r.invalidateResponses(payload.Task.UserID)
+	} else {
+		r.invalidateTaskResponses(ctx, payload.Todo.TaskID)
+	}
+	return payload, nil
+}
+
+// CreateAccessToken is the resolver for the createAccessToken field.
+func (r *mutationResolver) CreateAccessToken(ctx context.Context, input model.CreateAccessTokenInput) (*model.CreateAccessTokenPayload, error) {
+	principal, err := r.authorize(ctx, auth.ScopeWriteUser)
+	if err != nil {
+		return nil, err
+	}
+	if principal.Method == auth.MethodAccessToken {
+		return nil, errors.New("access tokens cannot create access tokens")
+	}
+	if input.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if err := validateAccessTokenScopes(principal, input.Scopes); err != nil {
+		return nil, err
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiresAt must be in the future")
+	}
+	secret, hash, err := auth.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	accessToken := &model.AccessToken{
+		ID:        xid.New().String(),
+		UserID:    principal.UserID,
+		Name:      input.Name,
+		TokenHash: hash,
+		Scopes:    input.Scopes,
+		ExpiresAt: input.ExpiresAt,
+	}
+	// the token is only stored along with its activity, as its secret is lost when the mutation fails
+	err = r.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := r.AccessTokenRepository.Store(ctx, accessToken); err != nil {
+			return err
+		}
+		_, err := r.recordActivity(ctx, principal.UserID, model.EntityTypeAccessToken, accessToken.ID, nil, model.ActivityActionCreate, accessTokenChanges(nil, accessToken))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.CreateAccessTokenPayload{
+		AccessToken: accessToken,
+		Token:       secret,
+	}, nil
+}
+
+// RevokeAccessToken is the resolver for the revokeAccessToken field.
+func (r *mutationResolver) RevokeAccessToken(ctx context.Context, id string) (*model.AccessToken, error) {
+	principal, err := r.authorize(ctx, auth.ScopeWriteUser)
+	if err != nil {
+		return nil, err
+	}
+	var accessToken *model.AccessToken
+	// a token is read from the primary and not revoked without the activity recording it
+	err = r.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if accessToken, err = r.AccessTokenRepository.Get(ctx, id); err != nil {
+			return err
+		}
+		if accessToken.UserID != principal.UserID {
+			return errors.New("record not found")
+		}
+		if accessToken.RevokedAt != nil {
+			return nil
+		}
+		before := *accessToken
+		now := time.Now()
+		accessToken.RevokedAt = &now
+		if err := r.AccessTokenRepository.Store(ctx, accessToken); err != nil {
+			return err
+		}
+		_, err = r.recordActivity(ctx, principal.UserID, model.EntityTypeAccessToken, accessToken.ID, nil, model.ActivityActionUpdate, accessTokenChanges(&before, accessToken))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accessToken, nil
+}
+
+// UpdateUserRole is the resolver for the updateUserRole field.
+func (r *mutationResolver) UpdateUserRole(ctx context.Context, input model.UpdateUserRoleInput) (*model.User, error) {
+	principal, err := r.authorizeAdmin(ctx, auth.ScopeWriteUser, policy.ActionWrite)
+	if err != nil {
+		return nil, err
+	}
+	if input.ID == principal.UserID {
+		return nil, errors.New("admins cannot change their own role")
+	}
+	var user *model.User
+	// only the role is written, so that the profile synced meanwhile is kept
+	err = r.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if user, err = r.getUser(ctx, input.ID); err != nil {
+			return err
+		}
+		before := *user
+		user.Role = input.Role
+		if err := r.UserRepository.UpdateRole(ctx, user.ID, user.Role); err != nil {
+			return err
+		}
+		changes := appendChange(nil, "role", true, before.Role.String(), user.Role.String())
+		_, err = r.recordActivity(ctx, principal.UserID, model.EntityTypeUser, user.ID, nil, model.ActivityActionUpdate, changes)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 // Mutation returns MutationResolver implementation.
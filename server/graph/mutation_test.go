@@ -0,0 +1,671 @@
+package graph_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutation_CreateUser(t *testing.T) {
+	query := `mutation($input: CreateUserInput!) { createUser(input: $input) { id name role } }`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		input      map[string]interface{}
+		wantData   string
+		wantErrors []string
+	}{
+		"create": {
+			principal: &stranger,
+			input:     map[string]interface{}{"name": "stranger"},
+			wantData:  `{"createUser":{"id":"auth0|stranger","name":"stranger","role":"MEMBER"}}`,
+		},
+		"update keeps role": {
+			principal: &admin,
+			input:     map[string]interface{}{"name": "renamed"},
+			wantData:  `{"createUser":{"id":"auth0|admin","name":"renamed","role":"ADMIN"}}`,
+		},
+		"viewer is forbidden": {
+			principal:  &viewer,
+			input:      map[string]interface{}{"name": "renamed"},
+			wantErrors: []string{"forbidden"},
+		},
+		"unauthenticated": {
+			principal:  nil,
+			input:      map[string]interface{}{"name": "renamed"},
+			wantErrors: []string{"unauthenticated"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			got := h.do(tt.principal, query, map[string]interface{}{"input": tt.input})
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
+
+func TestMutation_CreateTask(t *testing.T) {
+	query := `mutation($input: CreateTaskInput!) { createTask(input: $input) { text status user { id } progress { total } } }`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		wantData   string
+		wantErrors []string
+		check      func(t *testing.T, h *harness)
+	}{
+		"happy path": {
+			principal: &member,
+			wantData:  `{"createTask":{"text":"task4","status":"TODO","user":{"id":"auth0|member"},"progress":{"total":0}}}`,
+			check: func(t *testing.T, h *harness) {
+				got := h.do(&member, `{ fetchTasks { text activity { nodes { action } } } }`, nil)
+				// the xid of the new task sorts before the seeded ids
+				assert.JSONEq(t, `{"fetchTasks":[
+					{"text":"task4","activity":{"nodes":[{"action":"CREATE"}]}},
+					{"text":"task1","activity":{"nodes":[{"action":"UPDATE"},{"action":"UPDATE"},{"action":"CREATE"}]}},
+					{"text":"task2","activity":{"nodes":[]}}
+				]}`, got.data)
+			},
+		},
+		"viewer is forbidden": {
+			principal:  &viewer,
+			wantErrors: []string{"forbidden"},
+		},
+		"scope is not granted": {
+			principal:  &readOnly,
+			wantErrors: []string{"invalid scope"},
+		},
+		"unauthenticated": {
+			principal:  nil,
+			wantErrors: []string{"unauthenticated"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			got := h.do(tt.principal, query, map[string]interface{}{"input": map[string]interface{}{"text": "task4"}})
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+			if tt.check != nil {
+				tt.check(t, h)
+			}
+		})
+	}
+}
+
+func TestMutation_UpdateTask(t *testing.T) {
+	query := `mutation($input: UpdateTaskInput!) { updateTask(input: $input) { id text status } }`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		input      map[string]interface{}
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &member,
+			input:     map[string]interface{}{"id": "task1", "status": "DONE"},
+			wantData:  `{"updateTask":{"id":"task1","text":"task1","status":"DONE"}}`,
+		},
+		"task not found": {
+			principal:  &member,
+			input:      map[string]interface{}{"id": "task9", "status": "DONE"},
+			wantErrors: []string{"task not found: task9"},
+		},
+		"admin updates the task of another user": {
+			principal: &admin,
+			input:     map[string]interface{}{"id": "task1", "status": "DONE"},
+			wantData:  `{"updateTask":{"id":"task1","text":"task1","status":"DONE"}}`,
+		},
+		"task of another member is forbidden": {
+			principal:  &stranger,
+			input:      map[string]interface{}{"id": "task1", "status": "DONE"},
+			wantErrors: []string{"forbidden"},
+		},
+		"viewer is forbidden": {
+			principal:  &viewer,
+			input:      map[string]interface{}{"id": "task1", "status": "DONE"},
+			wantErrors: []string{"forbidden"},
+		},
+		"unauthenticated": {
+			principal:  nil,
+			input:      map[string]interface{}{"id": "task1", "status": "DONE"},
+			wantErrors: []string{"unauthenticated"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			got := h.do(tt.principal, query, map[string]interface{}{"input": tt.input})
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
+
+func TestMutation_CreateTodo(t *testing.T) {
+	query := `mutation($input: CreateTodoInput!) { createTodo(input: $input) { text done position task { id } parent { id } } }`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		input      map[string]interface{}
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &member,
+			input:     map[string]interface{}{"text": "todo4", "taskID": "task1", "parentID": "todo1"},
+			wantData:  `{"createTodo":{"text":"todo4","done":false,"position":1,"task":{"id":"task1"},"parent":{"id":"todo1"}}}`,
+		},
+		"top-level": {
+			principal: &member,
+			input:     map[string]interface{}{"text": "todo4", "taskID": "task1"},
+			wantData:  `{"createTodo":{"text":"todo4","done":false,"position":2,"task":{"id":"task1"},"parent":null}}`,
+		},
+		"parent belongs to another task": {
+			principal:  &member,
+			input:      map[string]interface{}{"text": "todo4", "taskID": "task2", "parentID": "todo1"},
+			wantErrors: []string{"parent todo belongs to another task"},
+		},
+		"parent not found": {
+			principal:  &member,
+			input:      map[string]interface{}{"text": "todo4", "taskID": "task1", "parentID": "todo9"},
+			wantErrors: []string{"todo not found: todo9"},
+		},
+		"task not found": {
+			principal:  &member,
+			input:      map[string]interface{}{"text": "todo4", "taskID": "task9"},
+			wantErrors: []string{"task not found: task9"},
+		},
+		"task of another member is forbidden": {
+			principal:  &stranger,
+			input:      map[string]interface{}{"text": "todo4", "taskID": "task1"},
+			wantErrors: []string{"forbidden"},
+		},
+		"scope is not granted": {
+			principal:  &readOnly,
+			input:      map[string]interface{}{"text": "todo4", "taskID": "task1"},
+			wantErrors: []string{"invalid scope"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			got := h.do(tt.principal, query, map[string]interface{}{"input": tt.input})
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
+
+func TestMutation_UpdateTodo(t *testing.T) {
+	query := `mutation($input: UpdateTodoInput!) { updateTodo(input: $input) { id text done task { progress { done total } } } }`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		input      map[string]interface{}
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &member,
+			input:     map[string]interface{}{"id": "todo1", "text": "done", "done": true},
+			wantData:  `{"updateTodo":{"id":"todo1","text":"done","done":true,"task":{"progress":{"done":2,"total":3}}}}`,
+		},
+		"todo not found": {
+			principal:  &member,
+			input:      map[string]interface{}{"id": "todo9", "done": true},
+			wantErrors: []string{"todo not found: todo9"},
+		},
+		"todo of another member is forbidden": {
+			principal:  &stranger,
+			input:      map[string]interface{}{"id": "todo1", "done": true},
+			wantErrors: []string{"forbidden"},
+		},
+		"viewer is forbidden": {
+			principal:  &viewer,
+			input:      map[string]interface{}{"id": "todo1", "done": true},
+			wantErrors: []string{"forbidden"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			got := h.do(tt.principal, query, map[string]interface{}{"input": tt.input})
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
+
+func TestMutation_ReorderTodos(t *testing.T) {
+	query := `mutation($taskID: ID!, $parentID: ID, $ids: [ID!]!) { reorderTodos(taskID: $taskID, parentID: $parentID, ids: $ids) { id position } }`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		parentID   *string
+		ids        []string
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &member,
+			ids:       []string{"todo2", "todo1"},
+			wantData:  `{"reorderTodos":[{"id":"todo2","position":0},{"id":"todo1","position":1}]}`,
+		},
+		"children of a todo": {
+			principal: &member,
+			parentID:  ptr("todo1"),
+			ids:       []string{"todo3"},
+			wantData:  `{"reorderTodos":[{"id":"todo3","position":0}]}`,
+		},
+		"sub-todos are not top-level": {
+			principal:  &member,
+			ids:        []string{"todo3", "todo1", "todo2"},
+			wantErrors: []string{"ids must list all 2 todos under the parent"},
+		},
+		"todos are missing": {
+			principal:  &member,
+			ids:        []string{"todo1"},
+			wantErrors: []string{"ids must list all 2 todos under the parent"},
+		},
+		"todo not found": {
+			principal:  &member,
+			ids:        []string{"todo1", "todo9"},
+			wantErrors: []string{"todo not found under the parent: todo9"},
+		},
+		"todos of another member are forbidden": {
+			principal:  &stranger,
+			ids:        []string{"todo2", "todo1"},
+			wantErrors: []string{"forbidden"},
+		},
+		"viewer is forbidden": {
+			principal:  &viewer,
+			ids:        []string{"todo2", "todo1"},
+			wantErrors: []string{"forbidden"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			got := h.do(tt.principal, query, map[string]interface{}{"taskID": "task1", "parentID": tt.parentID, "ids": tt.ids})
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
+
+func TestMutation_Undo(t *testing.T) {
+	query := `mutation($activityID: ID!) {
+		undo(activityID: $activityID) {
+			activity { entityID action changes { field before after } }
+			task { id text }
+			todo { id done }
+		}
+	}`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		setup      func(h *harness)
+		activityID string
+		wantData   string
+		wantErrors []string
+	}{
+		"task update": {
+			principal:  &member,
+			activityID: "activity2",
+			wantData: `{"undo":{
+				"activity":{"entityID":"task1","action":"UNDO","changes":[{"field":"text","before":"task1","after":"task"}]},
+				"task":{"id":"task1","text":"task"},
+				"todo":null
+			}}`,
+		},
+		"todo update": {
+			principal:  &member,
+			activityID: "activity3",
+			wantData: `{"undo":{
+				"activity":{"entityID":"todo2","action":"UNDO","changes":[{"field":"done","before":"true","after":"false"}]},
+				"task":null,
+				"todo":{"id":"todo2","done":false}
+			}}`,
+		},
+		"modified since the activity": {
+			principal: &member,
+			setup: func(h *harness) {
+				got := h.do(&member, `mutation { updateTask(input: {id: "task1", text: "edited"}) { id } }`, nil)
+				require.Empty(h.t, got.errors)
+			},
+			activityID: "activity2",
+			wantErrors: []string{"entity has been modified since the activity"},
+		},
+		"creation cannot be undone": {
+			principal:  &member,
+			activityID: "activity1",
+			wantErrors: []string{"only updates of tasks and todos can be undone"},
+		},
+		"activity not found": {
+			principal:  &member,
+			activityID: "activity9",
+			wantErrors: []string{"record not found"},
+		},
+		"admin undoes the task of another user": {
+			principal:  &admin,
+			activityID: "activity2",
+			wantData: `{"undo":{
+				"activity":{"entityID":"task1","action":"UNDO","changes":[{"field":"text","before":"task1","after":"task"}]},
+				"task":{"id":"task1","text":"task"},
+				"todo":null
+			}}`,
+		},
+		"admin undoes the todo of another user": {
+			principal:  &admin,
+			activityID: "activity3",
+			wantData: `{"undo":{
+				"activity":{"entityID":"todo2","action":"UNDO","changes":[{"field":"done","before":"true","after":"false"}]},
+				"task":null,
+				"todo":{"id":"todo2","done":false}
+			}}`,
+		},
+		"task of another user is forbidden": {
+			principal:  &stranger,
+			activityID: "activity2",
+			wantErrors: []string{"forbidden"},
+		},
+		"todo of another user is forbidden": {
+			principal:  &stranger,
+			activityID: "activity3",
+			wantErrors: []string{"forbidden"},
+		},
+		"viewer is forbidden": {
+			principal:  &viewer,
+			activityID: "activity2",
+			wantErrors: []string{"forbidden"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			if tt.setup != nil {
+				tt.setup(h)
+			}
+			got := h.do(tt.principal, query, map[string]interface{}{"activityID": tt.activityID})
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
+
+func TestMutation_UndoPosition(t *testing.T) {
+	h := newHarness(t)
+	h.seedBoard()
+	got := h.do(&member, `mutation { reorderTodos(taskID: "task1", ids: ["todo2", "todo1"]) { id } }`, nil)
+	require.Empty(t, got.errors)
+	// todo2 took position 0 of todo1, moving todo1 back alone would leave both at position 0
+	activity, err := h.activities.GetLatestByEntity(context.Background(), model.EntityTypeTodo, "todo1")
+	require.NoError(t, err)
+	got = h.do(&member, `mutation($activityID: ID!) { undo(activityID: $activityID) { todo { id } } }`, map[string]interface{}{"activityID": activity.ID})
+	assert.Equal(t, []string{"position is taken by another todo, reorder the todos instead"}, got.errors)
+	todo, err := h.todos.Get(context.Background(), "todo1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, todo.Position)
+}
+
+func TestMutation_CreateAccessToken(t *testing.T) {
+	query := `mutation($input: CreateAccessTokenInput!) { createAccessToken(input: $input) { accessToken { id name scopes expiresAt } token } }`
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	tests := map[string]struct {
+		principal  *auth.Principal
+		input      map[string]interface{}
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &member,
+			input:     map[string]interface{}{"name": "ci", "scopes": []string{"read:tasks"}, "expiresAt": expiresAt.Format(time.RFC3339)},
+			wantData:  `{"accessToken":{"name":"ci","scopes":["read:tasks"],"expiresAt":"` + expiresAt.Format(time.RFC3339) + `"}}`,
+		},
+		"access tokens cannot create access tokens": {
+			principal:  &auth.Principal{UserID: member.UserID, Scopes: auth.AllScopes, Method: auth.MethodAccessToken, Role: member.Role},
+			input:      map[string]interface{}{"name": "ci", "scopes": []string{"read:tasks"}},
+			wantErrors: []string{"access tokens cannot create access tokens"},
+		},
+		"name is empty": {
+			principal:  &member,
+			input:      map[string]interface{}{"name": "", "scopes": []string{"read:tasks"}},
+			wantErrors: []string{"name is required"},
+		},
+		"unknown scope": {
+			principal:  &member,
+			input:      map[string]interface{}{"name": "ci", "scopes": []string{"admin"}},
+			wantErrors: []string{"unknown scope: admin"},
+		},
+		"scope is not granted": {
+			principal:  &auth.Principal{UserID: member.UserID, Scopes: []string{auth.ScopeWriteUser}, Method: auth.MethodJWT, Role: member.Role},
+			input:      map[string]interface{}{"name": "ci", "scopes": []string{"read:tasks"}},
+			wantErrors: []string{"scope is not granted: read:tasks"},
+		},
+		"expired": {
+			principal:  &member,
+			input:      map[string]interface{}{"name": "ci", "scopes": []string{"read:tasks"}, "expiresAt": "2023-04-01T00:00:00Z"},
+			wantErrors: []string{"expiresAt must be in the future"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			got := h.do(tt.principal, query, map[string]interface{}{"input": tt.input})
+			assert.Equal(t, tt.wantErrors, got.errors)
+			if tt.wantData == "" {
+				return
+			}
+			// the secret is random, so only its presence is asserted.
+			var resp struct {
+				CreateAccessToken struct {
+					AccessToken map[string]interface{} `json:"accessToken"`
+					Token       string                 `json:"token"`
+				} `json:"createAccessToken"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(got.data), &resp))
+			assert.NotEmpty(t, resp.CreateAccessToken.Token)
+			id, _ := resp.CreateAccessToken.AccessToken["id"].(string)
+			delete(resp.CreateAccessToken.AccessToken, "id")
+			activity, err := h.activities.GetLatestByEntity(context.Background(), model.EntityTypeAccessToken, id)
+			require.NoError(t, err)
+			assert.Equal(t, model.ActivityActionCreate, activity.Action)
+			changes, err := json.Marshal(activity.Changes)
+			require.NoError(t, err)
+			assert.NotContains(t, string(changes), resp.CreateAccessToken.Token)
+			accessToken, err := json.Marshal(map[string]interface{}{"accessToken": resp.CreateAccessToken.AccessToken})
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.wantData, string(accessToken))
+		})
+	}
+}
+
+func TestMutation_RevokeAccessToken(t *testing.T) {
+	query := `mutation($id: ID!) { revokeAccessToken(id: $id) { id name } }`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		id         string
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &member,
+			id:        "token1",
+			wantData:  `{"revokeAccessToken":{"id":"token1","name":"ci"}}`,
+		},
+		"token of another user": {
+			principal:  &member,
+			id:         "token2",
+			wantErrors: []string{"record not found"},
+		},
+		"token not found": {
+			principal:  &member,
+			id:         "token9",
+			wantErrors: []string{"record not found"},
+		},
+		"scope is not granted": {
+			principal:  &readOnly,
+			id:         "token1",
+			wantErrors: []string{"invalid scope"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			got := h.do(tt.principal, query, map[string]interface{}{"id": tt.id})
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+			// only the revoked token loses its access
+			token, err := h.accessTokens.Get(context.Background(), "token1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantErrors == nil, token.RevokedAt != nil)
+			_, err = h.activities.GetLatestByEntity(context.Background(), model.EntityTypeAccessToken, "token1")
+			assert.Equal(t, tt.wantErrors == nil, err == nil)
+		})
+	}
+}
+
+func TestMutation_UpdateUserRole(t *testing.T) {
+	query := `mutation($input: UpdateUserRoleInput!) { updateUserRole(input: $input) { id role } }`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		input      map[string]interface{}
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &admin,
+			input:     map[string]interface{}{"id": "auth0|member", "role": "VIEWER"},
+			wantData:  `{"updateUserRole":{"id":"auth0|member","role":"VIEWER"}}`,
+		},
+		"own role": {
+			principal:  &admin,
+			input:      map[string]interface{}{"id": "auth0|admin", "role": "MEMBER"},
+			wantErrors: []string{"admins cannot change their own role"},
+		},
+		"user not found": {
+			principal:  &admin,
+			input:      map[string]interface{}{"id": "auth0|stranger", "role": "MEMBER"},
+			wantErrors: []string{"user not found: auth0|stranger"},
+		},
+		"member is forbidden": {
+			principal:  &member,
+			input:      map[string]interface{}{"id": "auth0|viewer", "role": "ADMIN"},
+			wantErrors: []string{"forbidden"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			got := h.do(tt.principal, query, map[string]interface{}{"input": tt.input})
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+			activity, err := h.activities.GetLatestByEntity(context.Background(), model.EntityTypeUser, "auth0|member")
+			if tt.wantErrors != nil {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, admin.UserID, activity.UserID)
+			assert.Equal(t, model.ActivityActionUpdate, activity.Action)
+			assert.Equal(t, []*model.FieldChange{{Field: "role", Before: ptr("MEMBER"), After: ptr("VIEWER")}}, activity.Changes)
+		})
+	}
+}
+
+func TestMutation_InvalidateResponseCache(t *testing.T) {
+	query := `{ fetchTasks { text status todos { text done position } } }`
+	tests := map[string]struct {
+		change      func(h *harness)
+		wantChanged bool
+	}{
+		"cached": {
+			change: func(h *harness) {
+				h.seed(&model.Task{ID: "task1", Text: "edited", Status: model.StatusInProgress, UserID: member.UserID})
+			},
+			wantChanged: false,
+		},
+		"createTask": {
+			change: func(h *harness) {
+				h.do(&member, `mutation { createTask(input: {text: "task4"}) { id } }`, nil)
+			},
+			wantChanged: true,
+		},
+		"updateTask of another user": {
+			change: func(h *harness) {
+				h.do(&admin, `mutation { updateTask(input: {id: "task1", text: "edited"}) { id } }`, nil)
+			},
+			wantChanged: true,
+		},
+		"createTodo": {
+			change: func(h *harness) {
+				h.do(&member, `mutation { createTodo(input: {text: "todo4", taskID: "task2"}) { id } }`, nil)
+			},
+			wantChanged: true,
+		},
+		"updateTodo": {
+			change: func(h *harness) {
+				h.do(&member, `mutation { updateTodo(input: {id: "todo1", done: true}) { id } }`, nil)
+			},
+			wantChanged: true,
+		},
+		"reorderTodos": {
+			change: func(h *harness) {
+				h.do(&member, `mutation { reorderTodos(taskID: "task1", ids: ["todo2", "todo1"]) { id } }`, nil)
+			},
+			wantChanged: true,
+		},
+		"undo": {
+			change: func(h *harness) {
+				h.do(&member, `mutation { undo(activityID: "activity3") { activity { id } } }`, nil)
+			},
+			wantChanged: true,
+		},
+		"mutation of another board": {
+			change: func(h *harness) {
+				h.do(&admin, `mutation { createTask(input: {text: "task4"}) { id } }`, nil)
+				h.seed(&model.Task{ID: "task1", Text: "edited", Status: model.StatusInProgress, UserID: member.UserID})
+			},
+			wantChanged: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			before := h.do(&member, query, nil)
+			require.Empty(t, before.errors)
+			tt.change(h)
+			got := h.do(&member, query, nil)
+			require.Empty(t, got.errors)
+			assert.Equal(t, tt.wantChanged, got.data != before.data)
+		})
+	}
+}
@@ -6,34 +6,90 @@ package graph
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"github.com/shota-tech/graphql/server/graph/model"
 	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/shota-tech/graphql/server/policy"
 )
 
 // FetchUser is the resolver for the fetchUser field.
 func (r *queryResolver) FetchUser(ctx context.Context) (*model.User, error) {
-	token := auth.TokenFromContext(ctx)
-	claims := token.CustomClaims.(*auth.CustomClaims)
-	if !claims.HasScope(auth.ScopeReadUser) {
-		return nil, errors.New("invalid scope")
+	principal, err := r.authorize(ctx, auth.ScopeReadUser)
+	if err != nil {
+		return nil, err
 	}
-	thunk := r.Loaders.UserLoader.Load(ctx, token.RegisteredClaims.Subject)
+	thunk := r.Loaders.UserLoader.Load(ctx, principal.UserID)
 	return thunk()
 }
 
 // FetchTasks is the resolver for the fetchTasks field.
 func (r *queryResolver) FetchTasks(ctx context.Context) ([]*model.Task, error) {
-	token := auth.TokenFromContext(ctx)
-	claims := token.CustomClaims.(*auth.CustomClaims)
-	if !claims.HasScope(auth.ScopeReadTasks) {
-		return nil, errors.New("invalid scope")
+	principal, err := r.authorize(ctx, auth.ScopeReadTasks)
+	if err != nil {
+		return nil, err
 	}
-	thunk := r.Loaders.TaskLoaderByUserID.Load(ctx, token.RegisteredClaims.Subject)
+	thunk := r.Loaders.TaskLoaderByUserID.Load(ctx, principal.UserID)
 	return thunk()
 }
 
+// BoardActivity is the resolver for the boardActivity field.
+func (r *queryResolver) BoardActivity(ctx context.Context, first *int, after *string) (*model.ActivityConnection, error) {
+	principal, err := r.authorize(ctx, auth.ScopeReadTasks)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := pageSize(first)
+	if err != nil {
+		return nil, err
+	}
+	var cursor string
+	if after != nil {
+		cursor = *after
+	}
+	activities, err := r.ActivityRepository.ListByTaskUserID(ctx, principal.UserID, limit+1, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return newActivityConnection(activities, limit), nil
+}
+
+// BoardStats is the resolver for the boardStats field.
+func (r *queryResolver) BoardStats(ctx context.Context, from time.Time, to time.Time) (*model.BoardStats, error) {
+	principal, err := r.authorize(ctx, auth.ScopeReadTasks)
+	if err != nil {
+		return nil, err
+	}
+	thunk := r.Loaders.TaskLoaderByUserID.Load(ctx, principal.UserID)
+	tasks, err := thunk()
+	if err != nil {
+		return nil, err
+	}
+	// the transitions after to tell the status of the tasks changed since
+	transitions, err := r.StatusTransitionRepository.ListByTaskUserID(ctx, principal.UserID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return buildBoardStats(tasks, transitions, from, to)
+}
+
+// AccessTokens is the resolver for the accessTokens field.
+func (r *queryResolver) AccessTokens(ctx context.Context) ([]*model.AccessToken, error) {
+	principal, err := r.authorize(ctx, auth.ScopeReadUser)
+	if err != nil {
+		return nil, err
+	}
+	return r.AccessTokenRepository.ListByUserID(ctx, principal.UserID)
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, filter *model.UserFilter) ([]*model.User, error) {
+	if _, err := r.authorizeAdmin(ctx, auth.ScopeReadUser, policy.ActionRead); err != nil {
+		return nil, err
+	}
+	return r.UserRepository.Search(ctx, filter)
+}
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
@@ -0,0 +1,269 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_FetchUser(t *testing.T) {
+	query := `{ fetchUser { id name role tasks { id } } }`
+	stranger := auth.Principal{UserID: "auth0|stranger", Scopes: auth.AllScopes, Method: auth.MethodJWT, Role: model.RoleMember}
+	tests := map[string]struct {
+		principal  *auth.Principal
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &member,
+			wantData:  `{"fetchUser":{"id":"auth0|member","name":"member","role":"MEMBER","tasks":[{"id":"task1"},{"id":"task2"}]}}`,
+		},
+		"unauthenticated": {
+			principal:  nil,
+			wantData:   `{"fetchUser":null}`,
+			wantErrors: []string{"unauthenticated"},
+		},
+		"scope is not granted": {
+			principal:  &auth.Principal{UserID: member.UserID, Scopes: []string{auth.ScopeReadTasks}, Method: auth.MethodAccessToken, Role: member.Role},
+			wantData:   `{"fetchUser":null}`,
+			wantErrors: []string{"invalid scope"},
+		},
+		"user not found": {
+			principal:  &stranger,
+			wantData:   `{"fetchUser":null}`,
+			wantErrors: []string{"user not found: auth0|stranger"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			got := h.do(tt.principal, query, nil)
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
+
+func TestQuery_FetchTasks(t *testing.T) {
+	query := `{
+		fetchTasks {
+			id text status
+			user { id }
+			todos { id done position parent { id } children { id } task { id } }
+			progress { done total percent }
+		}
+	}`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &member,
+			wantData: `{"fetchTasks":[
+				{"id":"task1","text":"task1","status":"IN_PROGRESS","user":{"id":"auth0|member"},"todos":[
+					{"id":"todo1","done":false,"position":0,"parent":null,"children":[{"id":"todo3"}],"task":{"id":"task1"}},
+					{"id":"todo2","done":true,"position":1,"parent":null,"children":[],"task":{"id":"task1"}}
+				],"progress":{"done":1,"total":3,"percent":33.33333333333333}},
+				{"id":"task2","text":"task2","status":"DONE","user":{"id":"auth0|member"},"todos":[],"progress":{"done":0,"total":0,"percent":0}}
+			]}`,
+		},
+		"viewer reads own board": {
+			principal: &viewer,
+			wantData:  `{"fetchTasks":[]}`,
+		},
+		"unauthenticated": {
+			principal:  nil,
+			wantErrors: []string{"unauthenticated"},
+		},
+		"nested field needs its own scope": {
+			principal:  &auth.Principal{UserID: member.UserID, Scopes: []string{auth.ScopeReadTasks}, Method: auth.MethodAccessToken, Role: member.Role},
+			wantErrors: []string{"invalid scope", "invalid scope"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			got := h.do(tt.principal, query, nil)
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
+
+func TestQuery_BoardActivity(t *testing.T) {
+	query := `query($first: Int, $after: ID) {
+		boardActivity(first: $first, after: $after) {
+			nodes { id user { id } entityType entityID action changes { field before after } }
+			pageInfo { endCursor hasNextPage }
+		}
+	}`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		variables  map[string]interface{}
+		wantData   string
+		wantErrors []string
+	}{
+		"first page": {
+			principal: &member,
+			variables: map[string]interface{}{"first": 2},
+			wantData: `{"boardActivity":{"nodes":[
+				{"id":"activity3","user":{"id":"auth0|member"},"entityType":"TODO","entityID":"todo2","action":"UPDATE","changes":[{"field":"done","before":"false","after":"true"}]},
+				{"id":"activity2","user":{"id":"auth0|member"},"entityType":"TASK","entityID":"task1","action":"UPDATE","changes":[{"field":"text","before":"task","after":"task1"}]}
+			],"pageInfo":{"endCursor":"activity2","hasNextPage":true}}}`,
+		},
+		"last page": {
+			principal: &member,
+			variables: map[string]interface{}{"first": 2, "after": "activity2"},
+			wantData: `{"boardActivity":{"nodes":[
+				{"id":"activity1","user":{"id":"auth0|member"},"entityType":"TASK","entityID":"task1","action":"CREATE","changes":[{"field":"text","before":null,"after":"task"}]}
+			],"pageInfo":{"endCursor":"activity1","hasNextPage":false}}}`,
+		},
+		"first is out of range": {
+			principal:  &member,
+			variables:  map[string]interface{}{"first": 101},
+			wantErrors: []string{"first must be between 1 and 100"},
+		},
+		"unauthenticated": {
+			principal:  nil,
+			wantErrors: []string{"unauthenticated"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			got := h.do(tt.principal, query, tt.variables)
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
+
+func TestQuery_BoardStats(t *testing.T) {
+	query := `query($from: Time!, $to: Time!) {
+		boardStats(from: $from, to: $to) {
+			countsByStatus { status count }
+			throughput { date count }
+			averageCycleTimeSeconds
+		}
+	}`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		variables  map[string]interface{}
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &member,
+			variables: map[string]interface{}{"from": "2023-04-01T00:00:00Z", "to": "2023-04-02T23:59:59Z"},
+			wantData: `{"boardStats":{
+				"countsByStatus":[{"status":"TODO","count":0},{"status":"IN_PROGRESS","count":1},{"status":"DONE","count":1}],
+				"throughput":[{"date":"2023-04-01T00:00:00Z","count":0},{"date":"2023-04-02T00:00:00Z","count":1}],
+				"averageCycleTimeSeconds":86400
+			}}`,
+		},
+		"to is before from": {
+			principal:  &member,
+			variables:  map[string]interface{}{"from": "2023-04-02T00:00:00Z", "to": "2023-04-01T00:00:00Z"},
+			wantErrors: []string{"to must not be before from"},
+		},
+		"unauthenticated": {
+			principal:  nil,
+			variables:  map[string]interface{}{"from": "2023-04-01T00:00:00Z", "to": "2023-04-02T00:00:00Z"},
+			wantErrors: []string{"unauthenticated"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			got := h.do(tt.principal, query, tt.variables)
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
+
+func TestQuery_AccessTokens(t *testing.T) {
+	query := `{ accessTokens { id name scopes expiresAt revokedAt createdAt } }`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &member,
+			wantData:  `{"accessTokens":[{"id":"token1","name":"ci","scopes":["read:tasks"],"expiresAt":null,"revokedAt":null,"createdAt":"2023-04-01T00:00:00Z"}]}`,
+		},
+		"scope is not granted": {
+			principal:  &auth.Principal{UserID: member.UserID, Scopes: []string{auth.ScopeReadTasks}, Method: auth.MethodAccessToken, Role: member.Role},
+			wantErrors: []string{"invalid scope"},
+		},
+		"unauthenticated": {
+			principal:  nil,
+			wantErrors: []string{"unauthenticated"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			h.seedBoard()
+			got := h.do(tt.principal, query, nil)
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
+
+func TestQuery_Users(t *testing.T) {
+	query := `query($filter: UserFilter) { users(filter: $filter) { id role } }`
+	tests := map[string]struct {
+		principal  *auth.Principal
+		variables  map[string]interface{}
+		wantData   string
+		wantErrors []string
+	}{
+		"happy path": {
+			principal: &admin,
+			wantData:  `{"users":[{"id":"auth0|admin","role":"ADMIN"},{"id":"auth0|member","role":"MEMBER"},{"id":"auth0|viewer","role":"VIEWER"}]}`,
+		},
+		"filtered": {
+			principal: &admin,
+			variables: map[string]interface{}{"filter": map[string]interface{}{"role": "VIEWER", "query": "VIEW"}},
+			wantData:  `{"users":[{"id":"auth0|viewer","role":"VIEWER"}]}`,
+		},
+		"member is forbidden": {
+			principal:  &member,
+			wantErrors: []string{"forbidden"},
+		},
+		"unauthenticated": {
+			principal:  nil,
+			wantErrors: []string{"unauthenticated"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t)
+			got := h.do(tt.principal, query, tt.variables)
+			if tt.wantData != "" {
+				assert.JSONEq(t, tt.wantData, got.data)
+			}
+			assert.Equal(t, tt.wantErrors, got.errors)
+		})
+	}
+}
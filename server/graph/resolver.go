@@ -2,6 +2,8 @@ package graph
 
 import (
 	"github.com/shota-tech/graphql/server/loader"
+	"github.com/shota-tech/graphql/server/middleware/respcache"
+	"github.com/shota-tech/graphql/server/policy"
 	"github.com/shota-tech/graphql/server/repository"
 )
 
@@ -10,8 +12,15 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	Loaders        *loader.Loaders
-	UserRepository repository.IUserRepository
-	TaskRepository repository.ITaskRepository
-	TodoRepository repository.ITodoRepository
+	Loaders                    *loader.Loaders
+	Policy                     *policy.Engine
+	Transactor                 repository.ITransactor
+	UserRepository             repository.IUserRepository
+	TaskRepository             repository.ITaskRepository
+	TodoRepository             repository.ITodoRepository
+	ActivityRepository         repository.IActivityRepository
+	StatusTransitionRepository repository.IStatusTransitionRepository
+	AccessTokenRepository      repository.IAccessTokenRepository
+	// ResponseCache is optional, mutations invalidate the responses cached for the owners of their tasks.
+	ResponseCache *respcache.Cache
 }
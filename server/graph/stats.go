@@ -0,0 +1,170 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/shota-tech/graphql/server/graph/model"
+)
+
+// maxStatsDays bounds the number of daily buckets a board stats query may produce.
+const maxStatsDays = 366
+
+// recordStatusTransition appends the status change of the task to its history.
+// from is nil when the task is created.
+func (r *Resolver) recordStatusTransition(ctx context.Context, task *model.Task, from *model.Status) error {
+	if from != nil && *from == task.Status {
+		return nil
+	}
+	transition := &model.StatusTransition{
+		ID:     xid.New().String(),
+		TaskID: task.ID,
+		From:   from,
+		To:     task.Status,
+	}
+	if err := r.StatusTransitionRepository.Store(ctx, transition); err != nil {
+		return fmt.Errorf("failed to record status transition: %w", err)
+	}
+	return nil
+}
+
+// days returns the start of every day between from and to in the location of from.
+func days(from, to time.Time) ([]time.Time, error) {
+	if to.Before(from) {
+		return nil, errors.New("to must not be before from")
+	}
+	to = to.In(from.Location())
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	var days []time.Time
+	for !day.After(to) {
+		if len(days) == maxStatsDays {
+			return nil, fmt.Errorf("range must not exceed %d days", maxStatsDays)
+		}
+		days = append(days, day)
+		day = day.AddDate(0, 0, 1)
+	}
+	return days, nil
+}
+
+// buildBoardStats computes the statistics of the tasks between from and to.
+// transitions must be sorted oldest first and contain every transition of the tasks,
+// including those after to which tell the status the tasks had before.
+func buildBoardStats(tasks []*model.Task, transitions []*model.StatusTransition, from, to time.Time) (*model.BoardStats, error) {
+	buckets, err := days(from, to)
+	if err != nil {
+		return nil, err
+	}
+	dayIndex := func(t time.Time) int {
+		t = t.In(from.Location())
+		for i := len(buckets) - 1; i >= 0; i-- {
+			if !t.Before(buckets[i]) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	currentCount := make(map[model.Status]int, len(model.AllStatus))
+	for _, task := range tasks {
+		currentCount[task.Status]++
+	}
+
+	// throughput and cycle time of the tasks done within the range
+	throughput := make([]int, len(buckets))
+	startedAt := make(map[string]time.Time)
+	var cycleTimeTotal time.Duration
+	var done int
+	for _, transition := range transitions {
+		switch transition.To {
+		case model.StatusInProgress:
+			startedAt[transition.TaskID] = transition.CreatedAt
+		case model.StatusDone:
+			if transition.CreatedAt.Before(from) || transition.CreatedAt.After(to) {
+				continue
+			}
+			throughput[dayIndex(transition.CreatedAt)]++
+			if started, ok := startedAt[transition.TaskID]; ok {
+				cycleTimeTotal += transition.CreatedAt.Sub(started)
+				done++
+			}
+		}
+	}
+
+	// cumulative flow at the end of each day, counting the tasks created by then
+	// with the status they had at that time.
+	firstByTaskID := make(map[string]*model.StatusTransition)
+	for _, transition := range transitions {
+		if _, ok := firstByTaskID[transition.TaskID]; !ok {
+			firstByTaskID[transition.TaskID] = transition
+		}
+	}
+	statusByTaskID := make(map[string]model.Status)
+	cumulativeFlow := make([]*model.CumulativeFlowPoint, len(buckets))
+	next := 0
+	for i, day := range buckets {
+		end := day.AddDate(0, 0, 1)
+		if end.After(to) {
+			end = to
+		}
+		for ; next < len(transitions) && !transitions[next].CreatedAt.After(end); next++ {
+			statusByTaskID[transitions[next].TaskID] = transitions[next].To
+		}
+		count := make(map[model.Status]int, len(model.AllStatus))
+		for _, task := range tasks {
+			if task.CreatedAt.After(end) {
+				continue
+			}
+			if status, ok := statusAt(task, statusByTaskID, firstByTaskID); ok {
+				count[status]++
+			}
+		}
+		cumulativeFlow[i] = &model.CumulativeFlowPoint{
+			Date:   day,
+			Counts: statusCounts(count),
+		}
+	}
+
+	stats := &model.BoardStats{
+		CountsByStatus: statusCounts(currentCount),
+		Throughput:     make([]*model.DailyCount, len(buckets)),
+		CumulativeFlow: cumulativeFlow,
+	}
+	for i, day := range buckets {
+		stats.Throughput[i] = &model.DailyCount{Date: day, Count: throughput[i]}
+	}
+	if done > 0 {
+		average := cycleTimeTotal.Seconds() / float64(done)
+		stats.AverageCycleTimeSeconds = &average
+	}
+	return stats, nil
+}
+
+// statusAt returns the status of the task given the transitions made until some time,
+// statusByTaskID holding the latest of them. Before its first transition a task had the status
+// it was changed from, and it did not exist when that transition is its creation.
+// Tasks without any transition have not changed since the history was introduced.
+func statusAt(task *model.Task, statusByTaskID map[string]model.Status, firstByTaskID map[string]*model.StatusTransition) (model.Status, bool) {
+	if status, ok := statusByTaskID[task.ID]; ok {
+		return status, true
+	}
+	first, ok := firstByTaskID[task.ID]
+	if !ok {
+		return task.Status, true
+	}
+	if first.From == nil {
+		return "", false
+	}
+	return *first.From, true
+}
+
+// statusCounts lists the count of every status in the order of the Status enum.
+func statusCounts(count map[model.Status]int) []*model.StatusCount {
+	counts := make([]*model.StatusCount, len(model.AllStatus))
+	for i, status := range model.AllStatus {
+		counts[i] = &model.StatusCount{Status: status, Count: count[status]}
+	}
+	return counts
+}
@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDays(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	tests := map[string]struct {
+		from    time.Time
+		to      time.Time
+		want    []time.Time
+		wantErr string
+	}{
+		"single day": {
+			from: time.Date(2023, 4, 1, 9, 0, 0, 0, time.UTC),
+			to:   time.Date(2023, 4, 1, 18, 0, 0, 0, time.UTC),
+			want: []time.Time{time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		"days in the location of from": {
+			from: time.Date(2023, 4, 1, 0, 0, 0, 0, jst),
+			to:   time.Date(2023, 4, 2, 15, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2023, 4, 1, 0, 0, 0, 0, jst),
+				time.Date(2023, 4, 2, 0, 0, 0, 0, jst),
+				time.Date(2023, 4, 3, 0, 0, 0, 0, jst),
+			},
+		},
+		"to before from": {
+			from:    time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+			wantErr: "to must not be before from",
+		},
+		"too many days": {
+			from:    time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			wantErr: "range must not exceed 366 days",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := days(tt.from, tt.to)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildBoardStats(t *testing.T) {
+	day := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days, hours int) time.Time {
+		return day.AddDate(0, 0, days).Add(time.Duration(hours) * time.Hour)
+	}
+	status := func(s model.Status) *model.Status {
+		return &s
+	}
+	counts := func(todo, inProgress, done int) []*model.StatusCount {
+		return []*model.StatusCount{
+			{Status: model.StatusTodo, Count: todo},
+			{Status: model.StatusInProgress, Count: inProgress},
+			{Status: model.StatusDone, Count: done},
+		}
+	}
+	// the range is in the past, from the first day to the middle of the third one
+	from, to := day, at(2, 12)
+	tests := map[string]struct {
+		tasks       []*model.Task
+		transitions []*model.StatusTransition
+		want        *model.BoardStats
+	}{
+		"tasks created inside the range": {
+			tasks: []*model.Task{
+				{ID: "task1", Status: model.StatusDone, CreatedAt: at(0, 1)},
+				{ID: "task2", Status: model.StatusTodo, CreatedAt: at(1, 2)},
+			},
+			transitions: []*model.StatusTransition{
+				{TaskID: "task1", To: model.StatusTodo, CreatedAt: at(0, 1)},
+				{TaskID: "task1", From: status(model.StatusTodo), To: model.StatusInProgress, CreatedAt: at(1, 1)},
+				{TaskID: "task2", To: model.StatusTodo, CreatedAt: at(1, 2)},
+				{TaskID: "task1", From: status(model.StatusInProgress), To: model.StatusDone, CreatedAt: at(2, 1)},
+			},
+			want: &model.BoardStats{
+				CountsByStatus: counts(1, 0, 1),
+				Throughput: []*model.DailyCount{
+					{Date: at(0, 0), Count: 0},
+					{Date: at(1, 0), Count: 0},
+					{Date: at(2, 0), Count: 1},
+				},
+				AverageCycleTimeSeconds: ptr((24 * time.Hour).Seconds()),
+				CumulativeFlow: []*model.CumulativeFlowPoint{
+					{Date: at(0, 0), Counts: counts(1, 0, 0)},
+					{Date: at(1, 0), Counts: counts(1, 1, 0)},
+					{Date: at(2, 0), Counts: counts(1, 0, 1)},
+				},
+			},
+		},
+		"past range ignores the tasks created and changed after it": {
+			tasks: []*model.Task{
+				// task1 predates the history and never changed
+				{ID: "task1", Status: model.StatusInProgress, CreatedAt: at(-10, 0)},
+				// task2 predates the history and was started within the range
+				{ID: "task2", Status: model.StatusInProgress, CreatedAt: at(-10, 0)},
+				// task3 predates the history and was only done after the range
+				{ID: "task3", Status: model.StatusDone, CreatedAt: at(-10, 0)},
+				// task4 was created after the range
+				{ID: "task4", Status: model.StatusDone, CreatedAt: at(5, 0)},
+			},
+			transitions: []*model.StatusTransition{
+				{TaskID: "task2", From: status(model.StatusTodo), To: model.StatusInProgress, CreatedAt: at(1, 3)},
+				{TaskID: "task4", To: model.StatusTodo, CreatedAt: at(5, 0)},
+				{TaskID: "task3", From: status(model.StatusTodo), To: model.StatusDone, CreatedAt: at(6, 0)},
+				{TaskID: "task4", From: status(model.StatusTodo), To: model.StatusDone, CreatedAt: at(6, 0)},
+			},
+			want: &model.BoardStats{
+				CountsByStatus: counts(0, 2, 2),
+				Throughput: []*model.DailyCount{
+					{Date: at(0, 0), Count: 0},
+					{Date: at(1, 0), Count: 0},
+					{Date: at(2, 0), Count: 0},
+				},
+				CumulativeFlow: []*model.CumulativeFlowPoint{
+					{Date: at(0, 0), Counts: counts(2, 1, 0)},
+					{Date: at(1, 0), Counts: counts(1, 2, 0)},
+					{Date: at(2, 0), Counts: counts(1, 2, 0)},
+				},
+			},
+		},
+		"no task": {
+			want: &model.BoardStats{
+				CountsByStatus: counts(0, 0, 0),
+				Throughput: []*model.DailyCount{
+					{Date: at(0, 0), Count: 0},
+					{Date: at(1, 0), Count: 0},
+					{Date: at(2, 0), Count: 0},
+				},
+				CumulativeFlow: []*model.CumulativeFlowPoint{
+					{Date: at(0, 0), Counts: counts(0, 0, 0)},
+					{Date: at(1, 0), Counts: counts(0, 0, 0)},
+					{Date: at(2, 0), Counts: counts(0, 0, 0)},
+				},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := buildBoardStats(tt.tasks, tt.transitions, from, to)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
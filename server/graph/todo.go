@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+)
+
+// nextPosition returns the position placing a new todo after all the others.
+func nextPosition(todos []*model.Todo) int {
+	position := 0
+	for _, todo := range todos {
+		if todo.Position >= position {
+			position = todo.Position + 1
+		}
+	}
+	return position
+}
+
+// siblings returns the todos under the parent, the top-level todos when parentID is nil.
+// Positions are numbered among siblings.
+func siblings(todos []*model.Todo, parentID *string) []*model.Todo {
+	var filtered []*model.Todo
+	for _, todo := range todos {
+		if sameParent(todo.ParentID, parentID) {
+			filtered = append(filtered, todo)
+		}
+	}
+	return filtered
+}
+
+func sameParent(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// orderTodos sorts the sibling todos in the order of ids,
+// which must list every one of them exactly once.
+func orderTodos(todos []*model.Todo, ids []string) ([]*model.Todo, error) {
+	if len(ids) != len(todos) {
+		return nil, fmt.Errorf("ids must list all %d todos under the parent", len(todos))
+	}
+	todoByID := make(map[string]*model.Todo, len(todos))
+	for _, todo := range todos {
+		todoByID[todo.ID] = todo
+	}
+	ordered := make([]*model.Todo, len(ids))
+	for i, id := range ids {
+		todo, ok := todoByID[id]
+		if !ok {
+			return nil, fmt.Errorf("todo not found under the parent: %s", id)
+		}
+		delete(todoByID, id)
+		ordered[i] = todo
+	}
+	return ordered, nil
+}
+
+// checkPositionFree fails when another of the siblings is at the position of the todo,
+// e.g. when undoing the move of one todo of a reorder whose former position went to another.
+func checkPositionFree(todo *model.Todo, siblings []*model.Todo) error {
+	for _, sibling := range siblings {
+		if sibling.ID != todo.ID && sibling.Position == todo.Position {
+			return errUndoPositionTaken
+		}
+	}
+	return nil
+}
@@ -5,18 +5,23 @@ import (
 	"github.com/shota-tech/graphql/server/graph/model"
 )
 
+// Loaders are shared by every request. A batch runs with the ctx of the request which opened it,
+// so its reads don't follow the session of the others and may come from a replica.
 type Loaders struct {
-	UserLoader         dataloader.Interface[string, *model.User]
-	TaskLoader         dataloader.Interface[string, *model.Task]
-	TodoLoader         dataloader.Interface[string, *model.Todo]
-	TaskLoaderByUserID dataloader.Interface[string, []*model.Task]
-	TodoLoaderByTaskID dataloader.Interface[string, []*model.Todo]
+	UserLoader             dataloader.Interface[string, *model.User]
+	TaskLoader             dataloader.Interface[string, *model.Task]
+	TodoLoader             dataloader.Interface[string, *model.Todo]
+	TaskLoaderByUserID     dataloader.Interface[string, []*model.Task]
+	TodoLoaderByTaskID     dataloader.Interface[string, []*model.Todo]
+	TodoLoaderByParentID   dataloader.Interface[string, []*model.Todo]
+	ProgressLoaderByTaskID dataloader.Interface[string, *model.Progress]
 }
 
 func NewLoaders(
 	userLoader *UserLoader,
 	taskLoader *TaskLoader,
 	todoLoader *TodoLoader,
+	observers ...Observer,
 ) *Loaders {
 	return &Loaders{
 		UserLoader: dataloader.NewBatchedLoader(
@@ -24,30 +29,49 @@ func NewLoaders(
 			dataloader.WithCache[string, *model.User](
 				&dataloader.NoCache[string, *model.User]{},
 			),
+			newTracer[*model.User]("user", observers),
 		),
 		TaskLoader: dataloader.NewBatchedLoader(
 			taskLoader.BulkGet,
 			dataloader.WithCache[string, *model.Task](
 				&dataloader.NoCache[string, *model.Task]{},
 			),
+			newTracer[*model.Task]("task", observers),
 		),
 		TodoLoader: dataloader.NewBatchedLoader(
 			todoLoader.BulkGet,
 			dataloader.WithCache[string, *model.Todo](
 				&dataloader.NoCache[string, *model.Todo]{},
 			),
+			newTracer[*model.Todo]("todo", observers),
 		),
 		TaskLoaderByUserID: dataloader.NewBatchedLoader(
 			taskLoader.BulkGetByUserIDs,
 			dataloader.WithCache[string, []*model.Task](
 				&dataloader.NoCache[string, []*model.Task]{},
 			),
+			newTracer[[]*model.Task]("task_by_user_id", observers),
 		),
 		TodoLoaderByTaskID: dataloader.NewBatchedLoader(
 			todoLoader.BulkGetByTaskIDs,
 			dataloader.WithCache[string, []*model.Todo](
 				&dataloader.NoCache[string, []*model.Todo]{},
 			),
+			newTracer[[]*model.Todo]("todo_by_task_id", observers),
+		),
+		TodoLoaderByParentID: dataloader.NewBatchedLoader(
+			todoLoader.BulkGetByParentIDs,
+			dataloader.WithCache[string, []*model.Todo](
+				&dataloader.NoCache[string, []*model.Todo]{},
+			),
+			newTracer[[]*model.Todo]("todo_by_parent_id", observers),
+		),
+		ProgressLoaderByTaskID: dataloader.NewBatchedLoader(
+			todoLoader.BulkGetProgressByTaskIDs,
+			dataloader.WithCache[string, *model.Progress](
+				&dataloader.NoCache[string, *model.Progress]{},
+			),
+			newTracer[*model.Progress]("progress_by_task_id", observers),
 		),
 	}
 }
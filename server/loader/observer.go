@@ -0,0 +1,73 @@
+package loader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// Observer is notified about the batches of every loader, e.g. to export metrics.
+type Observer interface {
+	// StartBatch is called before a batch of the named loader runs, wait being how long its first key was pending.
+	// The returned func is called once the batch is done.
+	StartBatch(ctx context.Context, loader string, size int, wait time.Duration) (context.Context, func())
+}
+
+// tracer adapts the observers to the tracer of a loader.
+type tracer[V any] struct {
+	name         string
+	observers    []Observer
+	now          func() time.Time
+	mu           sync.Mutex
+	pendingSince time.Time
+}
+
+func newTracer[V any](name string, observers []Observer) dataloader.Option[string, V] {
+	return dataloader.WithTracer[string, V](&tracer[V]{
+		name:      name,
+		observers: observers,
+		now:       time.Now,
+	})
+}
+
+func (t *tracer[V]) TraceLoad(ctx context.Context, key string) (context.Context, dataloader.TraceLoadFinishFunc[V]) {
+	t.pending()
+	return ctx, func(dataloader.Thunk[V]) {}
+}
+
+func (t *tracer[V]) TraceLoadMany(ctx context.Context, keys []string) (context.Context, dataloader.TraceLoadManyFinishFunc[V]) {
+	t.pending()
+	return ctx, func(dataloader.ThunkMany[V]) {}
+}
+
+func (t *tracer[V]) TraceBatch(ctx context.Context, keys []string) (context.Context, dataloader.TraceBatchFinishFunc[V]) {
+	t.mu.Lock()
+	var wait time.Duration
+	if !t.pendingSince.IsZero() {
+		wait = t.now().Sub(t.pendingSince)
+		t.pendingSince = time.Time{}
+	}
+	t.mu.Unlock()
+
+	finishes := make([]func(), len(t.observers))
+	for i, observer := range t.observers {
+		ctx, finishes[i] = observer.StartBatch(ctx, t.name, len(keys), wait)
+	}
+	// the results given by dataloader are always empty, they are evaluated before the batch runs.
+	return ctx, func([]*dataloader.Result[V]) {
+		for i := len(finishes) - 1; i >= 0; i-- {
+			finishes[i]()
+		}
+	}
+}
+
+// pending records when the oldest key not batched yet was loaded.
+func (t *tracer[V]) pending() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pendingSince.IsZero() {
+		t.pendingSince = t.now()
+	}
+}
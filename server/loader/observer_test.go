@@ -0,0 +1,57 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type batch struct {
+	loader string
+	size   int
+	wait   time.Duration
+	done   bool
+}
+
+type fakeObserver struct {
+	batches []batch
+}
+
+func (o *fakeObserver) StartBatch(ctx context.Context, loader string, size int, wait time.Duration) (context.Context, func()) {
+	o.batches = append(o.batches, batch{loader: loader, size: size, wait: wait})
+	i := len(o.batches) - 1
+	return ctx, func() {
+		o.batches[i].done = true
+	}
+}
+
+func TestTracer(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	observer := &fakeObserver{}
+	tracer := &tracer[string]{name: "user", observers: []Observer{observer}, now: func() time.Time { return now }}
+	batchFn := func(ctx context.Context, keys []string) []*dataloader.Result[string] {
+		results := make([]*dataloader.Result[string], len(keys))
+		for i, key := range keys {
+			if key == "unknown" {
+				results[i] = &dataloader.Result[string]{Error: errors.New("not found")}
+			} else {
+				results[i] = &dataloader.Result[string]{Data: key}
+			}
+		}
+		return results
+	}
+	sut := dataloader.NewBatchedLoader(batchFn, dataloader.WithTracer[string, string](tracer), dataloader.WithBatchCapacity[string, string](3))
+
+	thunk := sut.LoadMany(context.Background(), []string{"a", "b"})
+	now = now.Add(10 * time.Millisecond)
+	sut.Load(context.Background(), "unknown")
+	_, errs := thunk()
+	require.Empty(t, errs)
+
+	assert.Equal(t, []batch{{loader: "user", size: 3, wait: 10 * time.Millisecond, done: true}}, observer.batches)
+}
@@ -3,10 +3,10 @@ package loader
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/graph-gophers/dataloader/v7"
 	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/logging"
 	"github.com/shota-tech/graphql/server/repository"
 )
 
@@ -23,7 +23,7 @@ func NewTaskLoader(repository repository.ITaskRepository) *TaskLoader {
 func (l *TaskLoader) BulkGet(ctx context.Context, ids []string) []*dataloader.Result[*model.Task] {
 	tasks, err := l.repository.List(ctx, ids)
 	if err != nil {
-		log.Printf("failed to list tasks: %v", err)
+		logging.FromContext(ctx).Error("failed to list tasks", "error", err)
 		return nil
 	}
 
@@ -47,7 +47,7 @@ func (l *TaskLoader) BulkGet(ctx context.Context, ids []string) []*dataloader.Re
 func (l *TaskLoader) BulkGetByUserIDs(ctx context.Context, userIDs []string) []*dataloader.Result[[]*model.Task] {
 	tasks, err := l.repository.ListByUserIDs(ctx, userIDs)
 	if err != nil {
-		log.Printf("failed to list tasks: %v", err)
+		logging.FromContext(ctx).Error("failed to list tasks", "error", err)
 		return nil
 	}
 
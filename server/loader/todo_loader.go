@@ -3,10 +3,11 @@ package loader
 import (
 	"context"
 	"fmt"
-	"log"
+	"sort"
 
 	"github.com/graph-gophers/dataloader/v7"
 	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/logging"
 	"github.com/shota-tech/graphql/server/repository"
 )
 
@@ -23,7 +24,7 @@ func NewTodoLoader(repository repository.ITodoRepository) *TodoLoader {
 func (l *TodoLoader) BulkGet(ctx context.Context, ids []string) []*dataloader.Result[*model.Todo] {
 	todo, err := l.repository.List(ctx, ids)
 	if err != nil {
-		log.Printf("failed to list todos: %v", err)
+		logging.FromContext(ctx).Error("failed to list todos", "error", err)
 		return nil
 	}
 
@@ -47,7 +48,7 @@ func (l *TodoLoader) BulkGet(ctx context.Context, ids []string) []*dataloader.Re
 func (l *TodoLoader) BulkGetByTaskIDs(ctx context.Context, taskIDs []string) []*dataloader.Result[[]*model.Todo] {
 	todos, err := l.repository.ListByTaskIDs(ctx, taskIDs)
 	if err != nil {
-		log.Printf("failed to list todos: %v", err)
+		logging.FromContext(ctx).Error("failed to list todos", "error", err)
 		return nil
 	}
 
@@ -55,6 +56,9 @@ func (l *TodoLoader) BulkGetByTaskIDs(ctx context.Context, taskIDs []string) []*
 	for _, todo := range todos {
 		todoByTaskID[todo.TaskID] = append(todoByTaskID[todo.TaskID], todo)
 	}
+	for _, todos := range todoByTaskID {
+		sortByPosition(todos)
+	}
 
 	results := make([]*dataloader.Result[[]*model.Todo], len(taskIDs))
 	for i, taskID := range taskIDs {
@@ -64,3 +68,56 @@ func (l *TodoLoader) BulkGetByTaskIDs(ctx context.Context, taskIDs []string) []*
 	}
 	return results
 }
+
+func (l *TodoLoader) BulkGetByParentIDs(ctx context.Context, parentIDs []string) []*dataloader.Result[[]*model.Todo] {
+	todos, err := l.repository.ListByParentIDs(ctx, parentIDs)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to list todos", "error", err)
+		return nil
+	}
+
+	todoByParentID := make(map[string][]*model.Todo, len(parentIDs))
+	for _, todo := range todos {
+		todoByParentID[*todo.ParentID] = append(todoByParentID[*todo.ParentID], todo)
+	}
+	for _, todos := range todoByParentID {
+		sortByPosition(todos)
+	}
+
+	results := make([]*dataloader.Result[[]*model.Todo], len(parentIDs))
+	for i, parentID := range parentIDs {
+		results[i] = &dataloader.Result[[]*model.Todo]{
+			Data: todoByParentID[parentID],
+		}
+	}
+	return results
+}
+
+func (l *TodoLoader) BulkGetProgressByTaskIDs(ctx context.Context, taskIDs []string) []*dataloader.Result[*model.Progress] {
+	progresses, err := l.repository.CountByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to count todos", "error", err)
+		return nil
+	}
+
+	progressByTaskID := make(map[string]*model.Progress, len(taskIDs))
+	for _, progress := range progresses {
+		progressByTaskID[progress.TaskID] = progress
+	}
+
+	results := make([]*dataloader.Result[*model.Progress], len(taskIDs))
+	for i, taskID := range taskIDs {
+		progress, ok := progressByTaskID[taskID]
+		if !ok {
+			progress = &model.Progress{TaskID: taskID}
+		}
+		results[i] = &dataloader.Result[*model.Progress]{Data: progress}
+	}
+	return results
+}
+
+func sortByPosition(todos []*model.Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		return todos[i].Position < todos[j].Position
+	})
+}
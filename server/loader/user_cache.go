@@ -0,0 +1,106 @@
+package loader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shota-tech/graphql/server/cache"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+)
+
+// CacheObserver is notified about the lookups of the caches behind loaders, e.g. to export metrics.
+type CacheObserver interface {
+	ObserveCache(ctx context.Context, loader string, hits, misses int)
+}
+
+// UserCache keeps the users loaded by UserLoader across requests.
+// A user is dropped when it is stored through the repository returned by Repository,
+// so writes made by other instances of the server are only seen once it expires.
+type UserCache struct {
+	lru       *cache.LRU[string, model.User]
+	observers []CacheObserver
+	mu        sync.Mutex
+	// version changes on every invalidation, so that users read before it are not cached.
+	version uint64
+}
+
+func NewUserCache(size int, ttl time.Duration, observers ...CacheObserver) *UserCache {
+	return &UserCache{
+		lru:       cache.NewLRU[string, model.User](size, ttl),
+		observers: observers,
+	}
+}
+
+// Repository wraps the repository so that storing a user drops it from the cache.
+func (c *UserCache) Repository(r repository.IUserRepository) repository.IUserRepository {
+	return &invalidatingUserRepository{IUserRepository: r, cache: c}
+}
+
+// get returns copies of the cached users and the ids which are not cached.
+func (c *UserCache) get(ctx context.Context, ids []string) (map[string]*model.User, []string) {
+	found := make(map[string]*model.User, len(ids))
+	var missing []string
+	for _, id := range ids {
+		if user, ok := c.lru.Get(id); ok {
+			found[id] = &user
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	for _, observer := range c.observers {
+		observer.ObserveCache(ctx, "user", len(found), len(missing))
+	}
+	return found, missing
+}
+
+// add caches the users unless any user was invalidated since the version was read.
+func (c *UserCache) add(version uint64, users []*model.User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if version != c.version {
+		return
+	}
+	for _, user := range users {
+		c.lru.Add(user.ID, *user)
+	}
+}
+
+func (c *UserCache) currentVersion() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.version
+}
+
+func (c *UserCache) invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version++
+	c.lru.Remove(id)
+}
+
+type invalidatingUserRepository struct {
+	repository.IUserRepository
+	cache *UserCache
+}
+
+func (r *invalidatingUserRepository) Store(ctx context.Context, user *model.User) error {
+	err := r.IUserRepository.Store(ctx, user)
+	if user != nil {
+		r.cache.invalidate(user.ID)
+	}
+	return err
+}
+
+func (r *invalidatingUserRepository) UpdateName(ctx context.Context, id, name string) error {
+	err := r.IUserRepository.UpdateName(ctx, id, name)
+	r.cache.invalidate(id)
+	return err
+}
+
+func (r *invalidatingUserRepository) UpdateRole(ctx context.Context, id string, role model.Role) error {
+	err := r.IUserRepository.UpdateRole(ctx, id, role)
+	r.cache.invalidate(id)
+	return err
+}
@@ -0,0 +1,109 @@
+package loader
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+	"github.com/shota-tech/graphql/server/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lookup struct {
+	hits   int
+	misses int
+}
+
+type fakeCacheObserver struct {
+	lookups []lookup
+}
+
+func (o *fakeCacheObserver) ObserveCache(ctx context.Context, loader string, hits, misses int) {
+	o.lookups = append(o.lookups, lookup{hits: hits, misses: misses})
+}
+
+// countingUserRepository counts the ids listed from the repository.
+type countingUserRepository struct {
+	repository.IUserRepository
+	listed []string
+}
+
+func (r *countingUserRepository) List(ctx context.Context, ids []string) ([]*model.User, error) {
+	r.listed = append(r.listed, ids...)
+	return r.IUserRepository.List(ctx, ids)
+}
+
+func TestUserCache(t *testing.T) {
+	tests := map[string]struct {
+		run         func(ctx context.Context, sut *UserLoader, users repository.IUserRepository)
+		wantListed  []string
+		wantLookups []lookup
+		wantName    string
+	}{
+		"cached across batches": {
+			run: func(ctx context.Context, sut *UserLoader, users repository.IUserRepository) {
+				sut.BulkGet(ctx, []string{"user1"})
+			},
+			wantListed:  []string{"user1", "user2"},
+			wantLookups: []lookup{{hits: 0, misses: 2}, {hits: 1, misses: 0}, {hits: 2, misses: 0}},
+			wantName:    "user1",
+		},
+		"missing users are not cached": {
+			run: func(ctx context.Context, sut *UserLoader, users repository.IUserRepository) {
+				sut.BulkGet(ctx, []string{"user1", "user3"})
+			},
+			wantListed:  []string{"user1", "user2", "user3"},
+			wantLookups: []lookup{{hits: 0, misses: 2}, {hits: 1, misses: 1}, {hits: 2, misses: 0}},
+			wantName:    "user1",
+		},
+		"invalidated on store": {
+			run: func(ctx context.Context, sut *UserLoader, users repository.IUserRepository) {
+				require.NoError(t, users.Store(ctx, &model.User{ID: "user1", Name: "renamed", Role: model.RoleMember}))
+			},
+			wantListed:  []string{"user1", "user2", "user1"},
+			wantLookups: []lookup{{hits: 0, misses: 2}, {hits: 1, misses: 1}},
+			wantName:    "renamed",
+		},
+		"invalidated on update": {
+			run: func(ctx context.Context, sut *UserLoader, users repository.IUserRepository) {
+				require.NoError(t, users.UpdateName(ctx, "user1", "renamed"))
+			},
+			wantListed:  []string{"user1", "user2", "user1"},
+			wantLookups: []lookup{{hits: 0, misses: 2}, {hits: 1, misses: 1}},
+			wantName:    "renamed",
+		},
+		"results are copies": {
+			run: func(ctx context.Context, sut *UserLoader, users repository.IUserRepository) {
+				results := sut.BulkGet(ctx, []string{"user1"})
+				results[0].Data.Name = "modified"
+			},
+			wantListed:  []string{"user1", "user2"},
+			wantLookups: []lookup{{hits: 0, misses: 2}, {hits: 1, misses: 0}, {hits: 2, misses: 0}},
+			wantName:    "user1",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			db := memory.NewDB()
+			require.NoError(t, memory.NewUserRepository(db).Store(ctx, &model.User{ID: "user1", Name: "user1", Role: model.RoleMember}))
+			require.NoError(t, memory.NewUserRepository(db).Store(ctx, &model.User{ID: "user2", Name: "user2", Role: model.RoleMember}))
+			observer := &fakeCacheObserver{}
+			cache := NewUserCache(10, 0, observer)
+			counting := &countingUserRepository{IUserRepository: memory.NewUserRepository(db)}
+			users := cache.Repository(counting)
+			sut := NewCachedUserLoader(users, cache)
+			// test
+			sut.BulkGet(ctx, []string{"user1", "user2"})
+			tt.run(ctx, sut, users)
+			results := sut.BulkGet(ctx, []string{"user1", "user2"})
+			require.Len(t, results, 2)
+			require.NoError(t, results[0].Error)
+			assert.Equal(t, tt.wantName, results[0].Data.Name)
+			assert.Equal(t, tt.wantListed, counting.listed)
+			assert.Equal(t, tt.wantLookups, observer.lookups)
+		})
+	}
+}
@@ -3,15 +3,16 @@ package loader
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/graph-gophers/dataloader/v7"
 	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/logging"
 	"github.com/shota-tech/graphql/server/repository"
 )
 
 type UserLoader struct {
 	repository repository.IUserRepository
+	cache      *UserCache
 }
 
 func NewUserLoader(repository repository.IUserRepository) *UserLoader {
@@ -20,16 +21,37 @@ func NewUserLoader(repository repository.IUserRepository) *UserLoader {
 	}
 }
 
-func (l *UserLoader) BulkGet(ctx context.Context, ids []string) []*dataloader.Result[*model.User] {
-	users, err := l.repository.List(ctx, ids)
-	if err != nil {
-		log.Printf("failed to list users: %v", err)
-		return nil
+// NewCachedUserLoader returns a loader reading the users from the cache first.
+// The repository should be wrapped by the cache, see UserCache.Repository.
+func NewCachedUserLoader(repository repository.IUserRepository, cache *UserCache) *UserLoader {
+	return &UserLoader{
+		repository: repository,
+		cache:      cache,
 	}
+}
 
+func (l *UserLoader) BulkGet(ctx context.Context, ids []string) []*dataloader.Result[*model.User] {
 	userByID := make(map[string]*model.User, len(ids))
-	for _, user := range users {
-		userByID[user.ID] = user
+	missing := ids
+	if l.cache != nil {
+		userByID, missing = l.cache.get(ctx, ids)
+	}
+	if len(missing) > 0 {
+		var version uint64
+		if l.cache != nil {
+			version = l.cache.currentVersion()
+		}
+		users, err := l.repository.List(ctx, missing)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to list users", "error", err)
+			return nil
+		}
+		if l.cache != nil {
+			l.cache.add(version, users)
+		}
+		for _, user := range users {
+			userByID[user.ID] = user
+		}
 	}
 
 	results := make([]*dataloader.Result[*model.User], len(ids))
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// OperationLogger adds the operation name to the logger of the resolvers,
+// and logs the duration and errors of every response, including the ones rejected before execution.
+type OperationLogger struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+	graphql.ResponseInterceptor
+} = OperationLogger{}
+
+type operationContextKey struct{}
+
+func (OperationLogger) ExtensionName() string {
+	return "OperationLogger"
+}
+
+func (OperationLogger) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (OperationLogger) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return next(withOperation(ctx))
+}
+
+func (OperationLogger) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if resp == nil {
+		return nil
+	}
+	ctx = withOperation(ctx)
+	attrs := []any{}
+	if rc := operationContext(ctx); rc != nil && !rc.Stats.OperationStart.IsZero() {
+		attrs = append(attrs, slog.Duration("duration", time.Since(rc.Stats.OperationStart)))
+	}
+	logger := FromContext(ctx)
+	if len(resp.Errors) == 0 {
+		logger.InfoContext(ctx, "operation completed", attrs...)
+		return resp
+	}
+	messages := make([]string, len(resp.Errors))
+	for i, err := range resp.Errors {
+		messages[i] = err.Error()
+	}
+	attrs = append(attrs, slog.Any("errors", messages))
+	logger.WarnContext(ctx, "operation completed with errors", attrs...)
+	return resp
+}
+
+// withOperation adds the operation name once, responses of executed operations already have it.
+func withOperation(ctx context.Context) context.Context {
+	if ctx.Value(operationContextKey{}) != nil {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, operationContextKey{}, true)
+	rc := operationContext(ctx)
+	if rc == nil {
+		return ctx
+	}
+	name := rc.OperationName
+	if name == "" && rc.Operation != nil {
+		name = rc.Operation.Name
+	}
+	return With(ctx, "operation", name)
+}
+
+func operationContext(ctx context.Context) *graphql.OperationContext {
+	if !graphql.HasOperationContext(ctx) {
+		return nil
+	}
+	return graphql.GetOperationContext(ctx)
+}
@@ -0,0 +1,114 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/shota-tech/graphql/server/graph"
+	"github.com/shota-tech/graphql/server/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationLogger(t *testing.T) {
+	tests := map[string]struct {
+		body        string
+		wantRecords []map[string]interface{}
+	}{
+		"operation": {
+			body: `{"query":"query fetchUser { fetchUser { id } }"}`,
+			wantRecords: []map[string]interface{}{
+				{"level": "INFO", "msg": "resolver", "request_id": "req-1", "operation": "fetchUser"},
+				{"level": "INFO", "msg": "operation completed", "request_id": "req-1", "operation": "fetchUser"},
+			},
+		},
+		"operation rejected before execution": {
+			body: `{"query":"query fetchUser { fetchUser { password } }","operationName":"fetchUser"}`,
+			wantRecords: []map[string]interface{}{
+				{
+					"level":      "WARN",
+					"msg":        "operation completed with errors",
+					"request_id": "req-1",
+					"operation":  "fetchUser",
+					"errors":     []interface{}{`input:1: Cannot query field "password" on type "User".`},
+				},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+			srv.AddTransport(transport.POST{})
+			srv.Use(logging.OperationLogger{})
+			// log instead of running the resolvers, they have no repositories.
+			srv.AroundRootFields(func(ctx context.Context, next graphql.RootResolver) graphql.Marshaler {
+				logging.FromContext(ctx).Info("resolver")
+				return graphql.Null
+			})
+			sut := chiMiddleware.RequestID(logging.Middleware(srv))
+			// test
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(chiMiddleware.RequestIDHeader, "req-1")
+			req = req.WithContext(logging.WithContext(req.Context(), logger))
+			sut.ServeHTTP(httptest.NewRecorder(), req)
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			require.Len(t, lines, len(tt.wantRecords))
+			for i, line := range lines {
+				var got map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(line), &got))
+				for key, want := range tt.wantRecords[i] {
+					assert.Equal(t, want, got[key], key)
+				}
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := map[string]struct {
+		format    string
+		level     string
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"json": {
+			format:    "json",
+			level:     "info",
+			assertErr: assert.NoError,
+		},
+		"text": {
+			format:    "text",
+			level:     "DEBUG",
+			assertErr: assert.NoError,
+		},
+		"unknown format": {
+			format:    "xml",
+			level:     "info",
+			assertErr: assert.Error,
+		},
+		"unknown level": {
+			format:    "json",
+			level:     "verbose",
+			assertErr: assert.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := logging.New(&bytes.Buffer{}, tt.format, tt.level)
+			tt.assertErr(t, err)
+		})
+	}
+}
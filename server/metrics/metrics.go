@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/shota-tech/graphql/server/loader"
+)
+
+const (
+	namespace = "graphql"
+	// otherOperation labels the operations not known in advance, their names being chosen by the clients.
+	otherOperation = "other"
+)
+
+// Metrics records the latency of operations, resolvers and loaders.
+type Metrics struct {
+	registry          *prometheus.Registry
+	operationDuration *prometheus.HistogramVec
+	fieldDuration     *prometheus.HistogramVec
+	batchSize         *prometheus.HistogramVec
+	batchWait         *prometheus.HistogramVec
+	batchDuration     *prometheus.HistogramVec
+	cacheLookups      *prometheus.CounterVec
+	operations        map[string]bool
+}
+
+var (
+	_ loader.Observer      = &Metrics{}
+	_ loader.CacheObserver = &Metrics{}
+)
+
+// New returns the metrics labelling the operations by the names given, usually those of the persisted queries,
+// and any other operation as other so that clients can't grow the number of series.
+func New(operations ...string) *Metrics {
+	m := &Metrics{
+		registry:   prometheus.NewRegistry(),
+		operations: map[string]bool{},
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of GraphQL operations.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "type", "status"}),
+		fieldDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "field_duration_seconds",
+			Help:      "Duration of GraphQL field resolvers, fields read from a struct are not observed.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"object", "field", "status"}),
+		batchSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "dataloader_batch_size",
+			Help:      "Number of keys of dataloader batches.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 9),
+		}, []string{"loader"}),
+		batchWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "dataloader_wait_seconds",
+			Help:      "Time the first key of dataloader batches waited for the batch to run.",
+			Buckets:   []float64{.001, .0025, .005, .01, .016, .025, .05, .1},
+		}, []string{"loader"}),
+		batchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "dataloader_batch_duration_seconds",
+			Help:      "Duration of dataloader batch functions.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"loader"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dataloader_cache_lookups_total",
+			Help:      "Number of keys looked up in the caches behind dataloaders, by whether they were found.",
+		}, []string{"loader", "result"}),
+	}
+	for _, name := range operations {
+		m.operations[name] = true
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.operationDuration,
+		m.fieldDuration,
+		m.batchSize,
+		m.batchWait,
+		m.batchDuration,
+		m.cacheLookups,
+	)
+	return m
+}
+
+// RegisterDB exports the connection pool stats of the db.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the metrics to Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// AroundOperations observes the duration of each operation response.
+func (m *Metrics) AroundOperations(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	rc := graphql.GetOperationContext(ctx)
+	responses := next(ctx)
+	return func(ctx context.Context) *graphql.Response {
+		resp := responses(ctx)
+		if resp == nil {
+			return nil
+		}
+		name, typ := otherOperation, ""
+		if rc.Operation != nil {
+			if m.operations[rc.Operation.Name] {
+				name = rc.Operation.Name
+			}
+			typ = string(rc.Operation.Operation)
+		}
+		status := "ok"
+		if len(resp.Errors) > 0 {
+			status = "error"
+		}
+		m.operationDuration.WithLabelValues(name, typ, status).Observe(time.Since(rc.Stats.OperationStart).Seconds())
+		return resp
+	}
+}
+
+// AroundFields observes the duration of resolvers.
+func (m *Metrics) AroundFields(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if !fc.IsResolver {
+		return next(ctx)
+	}
+	start := time.Now()
+	res, err := next(ctx)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	m.fieldDuration.WithLabelValues(fc.Object, fc.Field.Name, status).Observe(time.Since(start).Seconds())
+	return res, err
+}
+
+func (m *Metrics) StartBatch(ctx context.Context, name string, size int, wait time.Duration) (context.Context, func()) {
+	m.batchSize.WithLabelValues(name).Observe(float64(size))
+	m.batchWait.WithLabelValues(name).Observe(wait.Seconds())
+	start := time.Now()
+	return ctx, func() {
+		m.batchDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	}
+}
+
+func (m *Metrics) ObserveCache(ctx context.Context, name string, hits, misses int) {
+	m.cacheLookups.WithLabelValues(name, "hit").Add(float64(hits))
+	m.cacheLookups.WithLabelValues(name, "miss").Add(float64(misses))
+}
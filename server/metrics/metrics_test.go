@@ -0,0 +1,52 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/shota-tech/graphql/server/graph"
+	"github.com/shota-tech/graphql/server/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	sut := metrics.New("fetchUser")
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+	srv.AddTransport(transport.POST{})
+	srv.AroundOperations(sut.AroundOperations)
+	srv.AroundFields(sut.AroundFields)
+	// the request is anonymous, so the resolver fails without touching the repositories.
+	var resp interface{}
+	err := client.New(srv).Post(`query fetchUser { fetchUser { id } }`, &resp)
+	assert.Error(t, err)
+	// operations not known in advance share a label, whatever the client names them.
+	err = client.New(srv).Post(`query random1234 { fetchUser { id } }`, &resp)
+	assert.Error(t, err)
+	_, finish := sut.StartBatch(context.Background(), "user", 3, 10*time.Millisecond)
+	finish()
+	sut.ObserveCache(context.Background(), "user", 2, 1)
+
+	rec := httptest.NewRecorder()
+	sut.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`graphql_operation_duration_seconds_count{operation="fetchUser",status="error",type="query"} 1`,
+		`graphql_operation_duration_seconds_count{operation="other",status="error",type="query"} 1`,
+		`graphql_field_duration_seconds_count{field="fetchUser",object="Query",status="error"} 2`,
+		`graphql_dataloader_batch_size_sum{loader="user"} 3`,
+		`graphql_dataloader_wait_seconds_sum{loader="user"} 0.01`,
+		`graphql_dataloader_batch_duration_seconds_count{loader="user"} 1`,
+		`graphql_dataloader_cache_lookups_total{loader="user",result="hit"} 2`,
+		`graphql_dataloader_cache_lookups_total{loader="user",result="miss"} 1`,
+	} {
+		assert.Contains(t, body, want)
+	}
+	assert.NotContains(t, body, `field="id"`)
+	assert.NotContains(t, body, `operation="random1234"`)
+}
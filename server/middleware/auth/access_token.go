@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/shota-tech/graphql/server/graph/model"
+)
+
+// AccessTokenPrefix tells personal access tokens apart from JWTs.
+const AccessTokenPrefix = "pat_"
+
+var errInvalidAccessToken = errors.New("invalid access token")
+
+type (
+	// AccessTokenClaims are the custom claims of a request authenticated by a personal access token.
+	AccessTokenClaims struct {
+		Scopes []string
+	}
+
+	AccessTokenFinder interface {
+		GetByTokenHash(context.Context, string) (*model.AccessToken, error)
+	}
+
+	// AccessTokenAuthenticator validates personal access tokens
+	// and hands any other token over to the next authenticator.
+	AccessTokenAuthenticator struct {
+		tokens AccessTokenFinder
+		next   Authenticator
+		now    func() time.Time
+	}
+)
+
+func (c AccessTokenClaims) Validate(ctx context.Context) error {
+	return nil
+}
+
+func (c AccessTokenClaims) HasScope(expectedScope string) bool {
+	for _, scope := range c.Scopes {
+		if scope == expectedScope {
+			return true
+		}
+	}
+	return false
+}
+
+func NewAccessTokenAuthenticator(tokens AccessTokenFinder, next Authenticator) *AccessTokenAuthenticator {
+	return &AccessTokenAuthenticator{
+		tokens: tokens,
+		next:   next,
+		now:    time.Now,
+	}
+}
+
+// ValidateToken returns the same claims as a JWT would, the subject being the owner of the token.
+func (a *AccessTokenAuthenticator) ValidateToken(ctx context.Context, token string) (interface{}, error) {
+	if !strings.HasPrefix(token, AccessTokenPrefix) {
+		return a.next.ValidateToken(ctx, token)
+	}
+	accessToken, err := a.tokens.GetByTokenHash(ctx, HashAccessToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidAccessToken, err)
+	}
+	if !accessToken.IsActive(a.now()) {
+		return nil, fmt.Errorf("%w: revoked or expired", errInvalidAccessToken)
+	}
+	claims := &validator.ValidatedClaims{
+		RegisteredClaims: validator.RegisteredClaims{
+			Subject:  accessToken.UserID,
+			ID:       accessToken.ID,
+			IssuedAt: accessToken.CreatedAt.Unix(),
+		},
+		CustomClaims: &AccessTokenClaims{
+			Scopes: accessToken.Scopes,
+		},
+	}
+	if accessToken.ExpiresAt != nil {
+		claims.RegisteredClaims.Expiry = accessToken.ExpiresAt.Unix()
+	}
+	return claims, nil
+}
+
+// GenerateAccessToken returns a new personal access token and the hash to store in its place.
+func GenerateAccessToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate access token: %w", err)
+	}
+	token = AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, HashAccessToken(token), nil
+}
+
+func HashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
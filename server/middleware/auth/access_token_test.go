@@ -0,0 +1,121 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type accessTokenFinder map[string]*model.AccessToken
+
+func (f accessTokenFinder) GetByTokenHash(_ context.Context, hash string) (*model.AccessToken, error) {
+	token, ok := f[hash]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return token, nil
+}
+
+type authenticatorFunc func(context.Context, string) (interface{}, error)
+
+func (f authenticatorFunc) ValidateToken(ctx context.Context, token string) (interface{}, error) {
+	return f(ctx, token)
+}
+
+func TestAccessTokenAuthenticator_ValidateToken(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	active, activeHash, err := auth.GenerateAccessToken()
+	require.NoError(t, err)
+	revoked, revokedHash, err := auth.GenerateAccessToken()
+	require.NoError(t, err)
+	expired, expiredHash, err := auth.GenerateAccessToken()
+	require.NoError(t, err)
+	unknown, _, err := auth.GenerateAccessToken()
+	require.NoError(t, err)
+	finder := accessTokenFinder{
+		activeHash:  {ID: "cgj2k1tvqc7kfo1h3uq0", UserID: "auth0|123456", Scopes: []string{auth.ScopeReadTasks}, ExpiresAt: &future},
+		revokedHash: {ID: "cgj2k2tvqc7kfo1h3uqg", UserID: "auth0|123456", Scopes: []string{auth.ScopeReadTasks}, RevokedAt: &past},
+		expiredHash: {ID: "cgj2k3tvqc7kfo1h3ur0", UserID: "auth0|123456", Scopes: []string{auth.ScopeReadTasks}, ExpiresAt: &past},
+	}
+	jwtClaims := &validator.ValidatedClaims{}
+	next := authenticatorFunc(func(_ context.Context, token string) (interface{}, error) {
+		if token == "jwt" {
+			return jwtClaims, nil
+		}
+		return nil, errors.New("invalid jwt")
+	})
+
+	tests := map[string]struct {
+		token     string
+		want      interface{}
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"access token": {
+			token: active,
+			want: &validator.ValidatedClaims{
+				RegisteredClaims: validator.RegisteredClaims{
+					Subject:  "auth0|123456",
+					ID:       "cgj2k1tvqc7kfo1h3uq0",
+					Expiry:   future.Unix(),
+					IssuedAt: time.Time{}.Unix(),
+				},
+				CustomClaims: &auth.AccessTokenClaims{Scopes: []string{auth.ScopeReadTasks}},
+			},
+			assertErr: assert.NoError,
+		},
+		"revoked access token": {
+			token:     revoked,
+			want:      nil,
+			assertErr: assert.Error,
+		},
+		"expired access token": {
+			token:     expired,
+			want:      nil,
+			assertErr: assert.Error,
+		},
+		"unknown access token": {
+			token:     unknown,
+			want:      nil,
+			assertErr: assert.Error,
+		},
+		"jwt": {
+			token:     "jwt",
+			want:      jwtClaims,
+			assertErr: assert.NoError,
+		},
+		"invalid jwt": {
+			token:     "invalid",
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sut := auth.NewAccessTokenAuthenticator(finder, next)
+			got, err := sut.ValidateToken(context.Background(), tt.token)
+			assert.Equal(t, tt.want, got)
+			tt.assertErr(t, err)
+		})
+	}
+}
+
+func TestGenerateAccessToken(t *testing.T) {
+	token, hash, err := auth.GenerateAccessToken()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, auth.AccessTokenPrefix))
+	assert.Equal(t, auth.HashAccessToken(token), hash)
+	assert.NotContains(t, hash, token)
+
+	other, _, err := auth.GenerateAccessToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/auth0/go-jwt-middleware/v2/jwks"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+)
+
+// Authenticator validates a raw token and returns its *validator.ValidatedClaims.
+type Authenticator interface {
+	ValidateToken(ctx context.Context, token string) (interface{}, error)
+}
+
+// NewJWKSAuthenticator returns an authenticator for RS256 tokens issued by the Auth0 tenant,
+// fetching its signing keys from the JWKS endpoint.
+func NewJWKSAuthenticator(domain, audience string) (Authenticator, error) {
+	if domain == "" {
+		return nil, errors.New("domain is required")
+	}
+	issuerURL, err := url.Parse("https://" + domain + "/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse issuer url: %w", err)
+	}
+	provider := jwks.NewCachingProvider(issuerURL, 5*time.Minute)
+	return newValidator(provider.KeyFunc, validator.RS256, issuerURL.String(), []string{audience}, newCustomClaims([]string{audience}, nil))
+}
+
+// NewKeyFileAuthenticator returns an authenticator verifying tokens with the key in the file.
+// A PEM encoded RSA key verifies RS256 tokens, private keys being reduced to their public part.
+// Any other content is used as the shared secret of HS256 tokens.
+func NewKeyFileAuthenticator(path, issuer, audience string) (Authenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return NewDevAuthenticator(string(bytes.TrimSpace(data)), issuer, audience)
+	}
+	key, err := parseRSAPublicKey(block)
+	if err != nil {
+		return nil, err
+	}
+	keyFunc := func(context.Context) (interface{}, error) {
+		return key, nil
+	}
+	return newValidator(keyFunc, validator.RS256, issuer, []string{audience}, newCustomClaims([]string{audience}, nil))
+}
+
+// NewDevAuthenticator returns an authenticator for HS256 tokens signed with the secret,
+// such as the ones minted by an HS256 Issuer.
+func NewDevAuthenticator(secret, issuer, audience string) (Authenticator, error) {
+	if secret == "" {
+		return nil, errors.New("secret is required")
+	}
+	keyFunc := func(context.Context) (interface{}, error) {
+		return []byte(secret), nil
+	}
+	return newValidator(keyFunc, validator.HS256, issuer, []string{audience}, newCustomClaims([]string{audience}, nil))
+}
+
+func newValidator(
+	keyFunc func(context.Context) (interface{}, error),
+	algorithm validator.SignatureAlgorithm,
+	issuer string,
+	audiences []string,
+	claims func() validator.CustomClaims,
+) (Authenticator, error) {
+	v, err := validator.New(
+		keyFunc,
+		algorithm,
+		issuer,
+		audiences,
+		validator.WithCustomClaims(claims),
+		validator.WithAllowedClockSkew(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup jwt validator: %w", err)
+	}
+	return v, nil
+}
+
+func parseRSAPublicKey(block *pem.Block) (*rsa.PublicKey, error) {
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("public key is not an RSA key")
+		}
+		return rsaKey, nil
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		return key, nil
+	default:
+		key, err := parseRSAPrivateKey(block)
+		if err != nil {
+			return nil, err
+		}
+		return &key.PublicKey, nil
+	}
+}
+
+func parseRSAPrivateKey(block *pem.Block) (*rsa.PrivateKey, error) {
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("private key is not an RSA key")
+		}
+		return rsaKey, nil
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported pem block: %s", block.Type)
+	}
+}
+
+// newCustomClaims returns the constructor of the claims of the tokens validated for the audiences.
+func newCustomClaims(audiences []string, scopeMapping map[string][]string) func() validator.CustomClaims {
+	return func() validator.CustomClaims {
+		return &CustomClaims{scopeMapping: scopeMapping, clients: audiences}
+	}
+}
@@ -0,0 +1,156 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	issuer   = "http://localhost:8080/"
+	audience = "graphql"
+)
+
+func TestAuthenticator_ValidateToken(t *testing.T) {
+	dir := t.TempDir()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKeyFile := filepath.Join(dir, "private.pem")
+	writePEM(t, privateKeyFile, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKeyFile := filepath.Join(dir, "public.pem")
+	writePEM(t, publicKeyFile, "PUBLIC KEY", publicKey)
+	secretFile := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("secret\n"), 0o600))
+
+	tests := map[string]struct {
+		authenticator func(t *testing.T) auth.Authenticator
+		issuer        func(t *testing.T) *auth.Issuer
+		ttl           time.Duration
+		assertErr     assert.ErrorAssertionFunc
+	}{
+		"dev": {
+			authenticator: func(t *testing.T) auth.Authenticator {
+				a, err := auth.NewDevAuthenticator("secret", issuer, audience)
+				require.NoError(t, err)
+				return a
+			},
+			issuer:    hs256Issuer("secret"),
+			ttl:       time.Hour,
+			assertErr: assert.NoError,
+		},
+		"dev with wrong secret": {
+			authenticator: func(t *testing.T) auth.Authenticator {
+				a, err := auth.NewDevAuthenticator("secret", issuer, audience)
+				require.NoError(t, err)
+				return a
+			},
+			issuer:    hs256Issuer("other"),
+			ttl:       time.Hour,
+			assertErr: assert.Error,
+		},
+		"expired": {
+			authenticator: func(t *testing.T) auth.Authenticator {
+				a, err := auth.NewDevAuthenticator("secret", issuer, audience)
+				require.NoError(t, err)
+				return a
+			},
+			issuer:    hs256Issuer("secret"),
+			ttl:       -time.Hour,
+			assertErr: assert.Error,
+		},
+		"key file with public key": {
+			authenticator: func(t *testing.T) auth.Authenticator {
+				a, err := auth.NewKeyFileAuthenticator(publicKeyFile, issuer, audience)
+				require.NoError(t, err)
+				return a
+			},
+			issuer: func(t *testing.T) *auth.Issuer {
+				i, err := auth.NewRS256Issuer(privateKeyFile, issuer, audience)
+				require.NoError(t, err)
+				return i
+			},
+			ttl:       time.Hour,
+			assertErr: assert.NoError,
+		},
+		"key file with private key": {
+			authenticator: func(t *testing.T) auth.Authenticator {
+				a, err := auth.NewKeyFileAuthenticator(privateKeyFile, issuer, audience)
+				require.NoError(t, err)
+				return a
+			},
+			issuer: func(t *testing.T) *auth.Issuer {
+				i, err := auth.NewRS256Issuer(privateKeyFile, issuer, audience)
+				require.NoError(t, err)
+				return i
+			},
+			ttl:       time.Hour,
+			assertErr: assert.NoError,
+		},
+		"key file with secret": {
+			authenticator: func(t *testing.T) auth.Authenticator {
+				a, err := auth.NewKeyFileAuthenticator(secretFile, issuer, audience)
+				require.NoError(t, err)
+				return a
+			},
+			issuer:    hs256Issuer("secret"),
+			ttl:       time.Hour,
+			assertErr: assert.NoError,
+		},
+		"key file with algorithm mismatch": {
+			authenticator: func(t *testing.T) auth.Authenticator {
+				a, err := auth.NewKeyFileAuthenticator(publicKeyFile, issuer, audience)
+				require.NoError(t, err)
+				return a
+			},
+			issuer:    hs256Issuer("secret"),
+			ttl:       time.Hour,
+			assertErr: assert.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			token, err := tt.issuer(t).Issue("dev|user", []string{auth.ScopeReadTasks, auth.ScopeWriteTasks}, tt.ttl)
+			require.NoError(t, err)
+			// test
+			sut := tt.authenticator(t)
+			got, err := sut.ValidateToken(context.Background(), token)
+			tt.assertErr(t, err)
+			if err != nil {
+				return
+			}
+			claims := got.(*validator.ValidatedClaims)
+			assert.Equal(t, "dev|user", claims.RegisteredClaims.Subject)
+			customClaims := claims.CustomClaims.(*auth.CustomClaims)
+			assert.True(t, customClaims.HasScope(auth.ScopeReadTasks))
+			assert.True(t, customClaims.HasScope(auth.ScopeWriteTasks))
+			assert.False(t, customClaims.HasScope(auth.ScopeReadUser))
+		})
+	}
+}
+
+func hs256Issuer(secret string) func(t *testing.T) *auth.Issuer {
+	return func(t *testing.T) *auth.Issuer {
+		i, err := auth.NewHS256Issuer(secret, issuer, audience)
+		require.NoError(t, err)
+		return i
+	}
+}
+
+func writePEM(t *testing.T, path, blockType string, b []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: b})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// Issuer mints tokens for development and tests.
+type Issuer struct {
+	signer   jose.Signer
+	issuer   string
+	audience string
+}
+
+// NewHS256Issuer returns an issuer signing tokens with the shared secret.
+func NewHS256Issuer(secret, issuer, audience string) (*Issuer, error) {
+	if secret == "" {
+		return nil, errors.New("secret is required")
+	}
+	return newIssuer(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(secret)}, issuer, audience)
+}
+
+// NewRS256Issuer returns an issuer signing tokens with the PEM encoded RSA private key in the file.
+func NewRS256Issuer(path, issuer, audience string) (*Issuer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("key file is not pem encoded")
+	}
+	key, err := parseRSAPrivateKey(block)
+	if err != nil {
+		return nil, err
+	}
+	return newIssuer(jose.SigningKey{Algorithm: jose.RS256, Key: key}, issuer, audience)
+}
+
+func newIssuer(key jose.SigningKey, issuer, audience string) (*Issuer, error) {
+	signer, err := jose.NewSigner(key, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer: %w", err)
+	}
+	return &Issuer{
+		signer:   signer,
+		issuer:   issuer,
+		audience: audience,
+	}, nil
+}
+
+// Issue mints a token for the subject granting the scopes, valid for ttl.
+func (i *Issuer) Issue(subject string, scopes []string, ttl time.Duration) (string, error) {
+	if subject == "" {
+		return "", errors.New("subject is required")
+	}
+	now := time.Now()
+	claims := jwt.Claims{
+		Issuer:    i.issuer,
+		Subject:   subject,
+		Audience:  jwt.Audience{i.audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Expiry:    jwt.NewNumericDate(now.Add(ttl)),
+	}
+	token, err := jwt.Signed(i.signer).
+		Claims(claims).
+		Claims(map[string]interface{}{"scope": strings.Join(scopes, " ")}).
+		CompactSerialize()
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return token, nil
+}
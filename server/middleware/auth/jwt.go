@@ -2,16 +2,11 @@ package auth
 
 import (
 	"context"
-	"log"
 	"net/http"
-	"net/url"
-	"os"
 	"strings"
-	"time"
 
 	jwtMiddleware "github.com/auth0/go-jwt-middleware/v2"
-	"github.com/auth0/go-jwt-middleware/v2/jwks"
-	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/shota-tech/graphql/server/logging"
 )
 
 const (
@@ -21,64 +16,94 @@ const (
 	ScopeWriteUser  = "write:user"
 )
 
-type CustomClaims struct {
-	Scope string `json:"scope"`
-}
+// AllScopes lists every scope the API checks.
+var AllScopes = []string{ScopeReadTasks, ScopeWriteTasks, ScopeReadUser, ScopeWriteUser}
+
+type (
+	CustomClaims struct {
+		Scope string `json:"scope"`
+		// Name, Email and Picture are the standard OIDC profile claims.
+		Name    string `json:"name"`
+		Email   string `json:"email"`
+		Picture string `json:"picture"`
+		// Permissions, Roles and Groups are the arrays providers such as Auth0 RBAC put in their tokens.
+		Permissions []string `json:"permissions"`
+		Roles       []string `json:"roles"`
+		Groups      []string `json:"groups"`
+		// RealmAccess and ResourceAccess hold the realm and client roles of Keycloak tokens.
+		RealmAccess    Access            `json:"realm_access"`
+		ResourceAccess map[string]Access `json:"resource_access"`
+
+		scopeMapping map[string][]string
+		// clients are the audiences the token is validated for,
+		// only their roles in ResourceAccess are granted.
+		clients []string
+	}
+
+	Access struct {
+		Roles []string `json:"roles"`
+	}
+)
 
 func (c CustomClaims) Validate(ctx context.Context) error {
 	return nil
 }
 
+// HasScope reports whether the scope is granted by the scope claim, or by a permission, role or group
+// either named after the scope or mapped to it.
 func (c CustomClaims) HasScope(expectedScope string) bool {
-	result := strings.Split(c.Scope, " ")
-	for i := range result {
-		if result[i] == expectedScope {
+	for _, grant := range c.grants() {
+		if grant == expectedScope {
 			return true
 		}
+		for _, scope := range c.scopeMapping[grant] {
+			if scope == expectedScope {
+				return true
+			}
+		}
 	}
 	return false
 }
 
-func EnsureValidToken() func(next http.Handler) http.Handler {
-	issuerURL, err := url.Parse("https://" + os.Getenv("AUTH0_DOMAIN") + "/")
-	if err != nil {
-		log.Fatalf("failed to parse issuer url: %v", err)
+func (c CustomClaims) grants() []string {
+	grants := strings.Fields(c.Scope)
+	grants = append(grants, c.Permissions...)
+	grants = append(grants, c.Roles...)
+	for _, group := range c.Groups {
+		// keycloak puts the full path of groups
+		grants = append(grants, strings.TrimPrefix(group, "/"))
 	}
-	provider := jwks.NewCachingProvider(issuerURL, 5*time.Minute)
-
-	jwtValidator, err := validator.New(
-		provider.KeyFunc,
-		validator.RS256,
-		issuerURL.String(),
-		[]string{os.Getenv("AUTH0_AUDIENCE")},
-		validator.WithCustomClaims(
-			func() validator.CustomClaims {
-				return &CustomClaims{}
-			},
-		),
-		validator.WithAllowedClockSkew(time.Minute),
-	)
-	if err != nil {
-		log.Fatal("failed to setup jwt validator")
+	grants = append(grants, c.RealmAccess.Roles...)
+	for _, client := range c.clients {
+		grants = append(grants, c.ResourceAccess[client].Roles...)
 	}
+	return grants
+}
 
+func EnsureValidToken(authenticator Authenticator) func(next http.Handler) http.Handler {
 	errorHandler := func(w http.ResponseWriter, r *http.Request, err error) {
-		log.Printf("encounterd error while validating JWT: %v", err)
+		logging.FromContext(r.Context()).Warn("failed to validate JWT", "error", err)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
 		w.Write([]byte(`{"message":"failed to validate JWT."}`))
 	}
 
 	middleware := jwtMiddleware.New(
-		jwtValidator.ValidateToken,
+		authenticator.ValidateToken,
 		jwtMiddleware.WithErrorHandler(errorHandler),
 	)
 
 	return func(next http.Handler) http.Handler {
-		return middleware.CheckJWT(next)
+		return middleware.CheckJWT(withSubject(next))
 	}
 }
 
-func TokenFromContext(ctx context.Context) *validator.ValidatedClaims {
-	return ctx.Value(jwtMiddleware.ContextKey{}).(*validator.ValidatedClaims)
+// withSubject adds the subject of the principal to the logger of the request.
+func withSubject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, ok := PrincipalFromContext(r.Context()); ok {
+			r = r.WithContext(logging.With(r.Context(), "sub", principal.UserID))
+		}
+		next.ServeHTTP(w, r)
+	})
 }
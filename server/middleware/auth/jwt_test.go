@@ -0,0 +1,53 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomClaims_HasScope(t *testing.T) {
+	tests := map[string]struct {
+		claims auth.CustomClaims
+		want   bool
+	}{
+		"scope": {
+			claims: auth.CustomClaims{Scope: "openid read:tasks"},
+			want:   true,
+		},
+		"permissions": {
+			claims: auth.CustomClaims{Permissions: []string{"read:tasks"}},
+			want:   true,
+		},
+		"roles": {
+			claims: auth.CustomClaims{Roles: []string{"read:tasks"}},
+			want:   true,
+		},
+		"groups": {
+			claims: auth.CustomClaims{Groups: []string{"/read:tasks"}},
+			want:   true,
+		},
+		"realm roles": {
+			claims: auth.CustomClaims{RealmAccess: auth.Access{Roles: []string{"read:tasks"}}},
+			want:   true,
+		},
+		"client roles without audience": {
+			claims: auth.CustomClaims{ResourceAccess: map[string]auth.Access{"graphql": {Roles: []string{"read:tasks"}}}},
+			want:   false,
+		},
+		"other scope": {
+			claims: auth.CustomClaims{Scope: "read:tasks:all", Permissions: []string{"write:tasks"}},
+			want:   false,
+		},
+		"no scope": {
+			claims: auth.CustomClaims{},
+			want:   false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.claims.HasScope(auth.ScopeReadTasks))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/auth0/go-jwt-middleware/v2/jwks"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+type (
+	// OIDCConfig configures the OIDC providers whose tokens are trusted.
+	OIDCConfig struct {
+		Providers []OIDCProvider
+		// ScopeMapping grants scopes to the roles, groups and permissions a provider puts in its tokens.
+		ScopeMapping map[string][]string
+		// Client fetches the discovery documents and keys, http.DefaultClient when nil.
+		Client *http.Client
+	}
+
+	// OIDCProvider is a trusted issuer.
+	OIDCProvider struct {
+		Issuer    string
+		Audiences []string
+		// Algorithms accepted for the tokens of the issuer, RS256 when empty.
+		Algorithms []string
+	}
+
+	// OIDCAuthenticator validates the tokens of several issuers,
+	// routing each token by its issuer and signing algorithm.
+	OIDCAuthenticator struct {
+		validators map[string]map[string]Authenticator
+	}
+)
+
+type discoveryDocument struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// NewOIDCAuthenticator reads the discovery document of each provider
+// and returns an authenticator verifying tokens with the keys published at its jwks_uri.
+func NewOIDCAuthenticator(ctx context.Context, config OIDCConfig) (*OIDCAuthenticator, error) {
+	if len(config.Providers) == 0 {
+		return nil, errors.New("provider is required")
+	}
+	client := config.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	validators := make(map[string]map[string]Authenticator, len(config.Providers))
+	for _, provider := range config.Providers {
+		if _, ok := validators[provider.Issuer]; ok {
+			return nil, fmt.Errorf("duplicated issuer: %s", provider.Issuer)
+		}
+		if len(provider.Audiences) == 0 {
+			return nil, fmt.Errorf("audience is required for issuer: %s", provider.Issuer)
+		}
+		document, err := discover(ctx, client, provider.Issuer)
+		if err != nil {
+			return nil, err
+		}
+		issuerURL, err := url.Parse(provider.Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse issuer url: %w", err)
+		}
+		jwksURI, err := url.Parse(document.JWKSURI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwks uri: %w", err)
+		}
+		keys := jwks.NewCachingProvider(issuerURL, 5*time.Minute,
+			jwks.WithCustomJWKSURI(jwksURI),
+			jwks.WithCustomClient(client),
+		)
+
+		algorithms := provider.Algorithms
+		if len(algorithms) == 0 {
+			algorithms = []string{string(validator.RS256)}
+		}
+		validators[provider.Issuer] = make(map[string]Authenticator, len(algorithms))
+		claims := newCustomClaims(provider.Audiences, config.ScopeMapping)
+		for _, algorithm := range algorithms {
+			v, err := newValidator(keys.KeyFunc, validator.SignatureAlgorithm(algorithm), provider.Issuer, provider.Audiences, claims)
+			if err != nil {
+				return nil, err
+			}
+			validators[provider.Issuer][algorithm] = v
+		}
+	}
+	return &OIDCAuthenticator{validators: validators}, nil
+}
+
+func (a *OIDCAuthenticator) ValidateToken(ctx context.Context, token string) (interface{}, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the token: %w", err)
+	}
+	// the issuer only selects the keys, the claims are verified by the validator afterwards.
+	var claims jwt.Claims
+	if err := parsed.UnsafeClaimsWithoutVerification(&claims); err != nil {
+		return nil, fmt.Errorf("could not get token claims: %w", err)
+	}
+	validators, ok := a.validators[claims.Issuer]
+	if !ok {
+		return nil, fmt.Errorf("untrusted issuer: %s", claims.Issuer)
+	}
+	v, ok := validators[parsed.Headers[0].Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", parsed.Headers[0].Algorithm)
+	}
+	return v.ValidateToken(ctx, token)
+}
+
+// discover fetches the discovery document of the issuer.
+func discover(ctx context.Context, client *http.Client, issuer string) (*discoveryDocument, error) {
+	endpoint := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build discovery request: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get discovery document: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get discovery document: %s", res.Status)
+	}
+	var document discoveryDocument
+	if err := json.NewDecoder(res.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode discovery document: %w", err)
+	}
+	if document.Issuer != issuer {
+		return nil, fmt.Errorf("discovery document is issued by %s, not %s", document.Issuer, issuer)
+	}
+	if document.JWKSURI == "" {
+		return nil, errors.New("jwks_uri is missing in discovery document")
+	}
+	return &document, nil
+}
@@ -0,0 +1,193 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// oidcServer serves the discovery documents and keys of a realm per path, like keycloak does.
+type oidcServer struct {
+	*httptest.Server
+	keys map[string]*rsa.PrivateKey
+}
+
+func newOIDCServer(t *testing.T, realms ...string) *oidcServer {
+	s := &oidcServer{keys: map[string]*rsa.PrivateKey{}}
+	mux := http.NewServeMux()
+	for _, realm := range realms {
+		realm := realm
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		s.keys[realm] = key
+		mux.HandleFunc("/realms/"+realm+"/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]string{
+				"issuer":   s.issuer(realm),
+				"jwks_uri": s.issuer(realm) + "/protocol/openid-connect/certs",
+			})
+		})
+		mux.HandleFunc("/realms/"+realm+"/protocol/openid-connect/certs", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+				{Key: &key.PublicKey, KeyID: realm, Algorithm: string(jose.RS256), Use: "sig"},
+			}})
+		})
+	}
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *oidcServer) issuer(realm string) string {
+	return s.URL + "/realms/" + realm
+}
+
+func (s *oidcServer) sign(t *testing.T, realm string, key *rsa.PrivateKey, audience string, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: key, KeyID: realm}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	require.NoError(t, err)
+	now := time.Now()
+	token, err := jwt.Signed(signer).
+		Claims(jwt.Claims{
+			Issuer:   s.issuer(realm),
+			Subject:  "user1",
+			Audience: jwt.Audience{audience},
+			IssuedAt: jwt.NewNumericDate(now),
+			Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+		}).
+		Claims(claims).
+		CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func TestOIDCAuthenticator_ValidateToken(t *testing.T) {
+	server := newOIDCServer(t, "a", "b", "c")
+	sut, err := auth.NewOIDCAuthenticator(context.Background(), auth.OIDCConfig{
+		Providers: []auth.OIDCProvider{
+			{Issuer: server.issuer("a"), Audiences: []string{"graphql"}},
+			{Issuer: server.issuer("b"), Audiences: []string{"graphql", "account"}, Algorithms: []string{"RS256", "RS512"}},
+		},
+		ScopeMapping: map[string][]string{
+			"member": {auth.ScopeReadTasks, auth.ScopeWriteTasks},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		token      string
+		wantScope  string
+		wantDenied bool
+		assertErr  assert.ErrorAssertionFunc
+	}{
+		"realm role mapped to scope": {
+			token: server.sign(t, "a", server.keys["a"], "graphql", map[string]interface{}{
+				"realm_access": map[string]interface{}{"roles": []string{"member"}},
+			}),
+			wantScope: auth.ScopeWriteTasks,
+			assertErr: assert.NoError,
+		},
+		"client role of the audience mapped to scope": {
+			token: server.sign(t, "a", server.keys["a"], "graphql", map[string]interface{}{
+				"resource_access": map[string]interface{}{"graphql": map[string]interface{}{"roles": []string{"member"}}},
+			}),
+			wantScope: auth.ScopeWriteTasks,
+			assertErr: assert.NoError,
+		},
+		"role of another client is not granted": {
+			token: server.sign(t, "a", server.keys["a"], "graphql", map[string]interface{}{
+				"resource_access": map[string]interface{}{"account": map[string]interface{}{"roles": []string{"member"}}},
+			}),
+			wantScope:  auth.ScopeWriteTasks,
+			wantDenied: true,
+			assertErr:  assert.NoError,
+		},
+		"second issuer": {
+			token: server.sign(t, "b", server.keys["b"], "account", map[string]interface{}{
+				"permissions": []string{auth.ScopeReadUser},
+			}),
+			wantScope: auth.ScopeReadUser,
+			assertErr: assert.NoError,
+		},
+		"untrusted issuer": {
+			token:     server.sign(t, "c", server.keys["c"], "graphql", nil),
+			assertErr: assert.Error,
+		},
+		"signed by the key of another issuer": {
+			token:     server.sign(t, "a", server.keys["b"], "graphql", nil),
+			assertErr: assert.Error,
+		},
+		"wrong audience": {
+			token:     server.sign(t, "a", server.keys["a"], "account", nil),
+			assertErr: assert.Error,
+		},
+		"malformed token": {
+			token:     "token",
+			assertErr: assert.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := sut.ValidateToken(context.Background(), tt.token)
+			tt.assertErr(t, err)
+			if err != nil {
+				return
+			}
+			claims := got.(*validator.ValidatedClaims)
+			assert.Equal(t, "user1", claims.RegisteredClaims.Subject)
+			assert.Equal(t, !tt.wantDenied, claims.CustomClaims.(*auth.CustomClaims).HasScope(tt.wantScope))
+		})
+	}
+}
+
+func TestNewOIDCAuthenticator(t *testing.T) {
+	server := newOIDCServer(t, "a")
+	tests := map[string]struct {
+		providers []auth.OIDCProvider
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			providers: []auth.OIDCProvider{{Issuer: server.issuer("a"), Audiences: []string{"graphql"}}},
+			assertErr: assert.NoError,
+		},
+		"provider is empty": {
+			providers: nil,
+			assertErr: assert.Error,
+		},
+		"audience is empty": {
+			providers: []auth.OIDCProvider{{Issuer: server.issuer("a")}},
+			assertErr: assert.Error,
+		},
+		"discovery document not found": {
+			providers: []auth.OIDCProvider{{Issuer: server.issuer("unknown"), Audiences: []string{"graphql"}}},
+			assertErr: assert.Error,
+		},
+		"issuer mismatch": {
+			providers: []auth.OIDCProvider{{Issuer: server.issuer("a") + "/", Audiences: []string{"graphql"}}},
+			assertErr: assert.Error,
+		},
+		"unsupported algorithm": {
+			providers: []auth.OIDCProvider{{Issuer: server.issuer("a"), Audiences: []string{"graphql"}, Algorithms: []string{"none"}}},
+			assertErr: assert.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := auth.NewOIDCAuthenticator(context.Background(), auth.OIDCConfig{Providers: tt.providers})
+			tt.assertErr(t, err)
+		})
+	}
+}
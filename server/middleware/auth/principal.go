@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+
+	jwtMiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/shota-tech/graphql/server/graph/model"
+)
+
+// Method is how a principal authenticated.
+type Method string
+
+const (
+	MethodJWT         Method = "jwt"
+	MethodAccessToken Method = "access_token"
+)
+
+type (
+	// Principal is the authenticated caller of a request.
+	Principal struct {
+		UserID string
+		// Scopes are the API scopes granted to the caller, see AllScopes.
+		Scopes  []string
+		Method  Method
+		Profile Profile
+		// Role is stored server side, it is set by ProvisionUser.
+		Role model.Role
+	}
+
+	// Profile is what the identity provider tells about the user, fields may be empty.
+	Profile struct {
+		Name    string
+		Email   string
+		Picture string
+	}
+)
+
+func (p Principal) HasScope(expectedScope string) bool {
+	for _, scope := range p.Scopes {
+		if scope == expectedScope {
+			return true
+		}
+	}
+	return false
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal returns a context carrying the principal,
+// taking precedence over the claims set by EnsureValidToken.
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the caller of the request.
+// It reports false when the request is anonymous or carries claims it doesn't know.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	if principal, ok := ctx.Value(principalContextKey{}).(Principal); ok {
+		return principal, principal.UserID != ""
+	}
+	claims, ok := ctx.Value(jwtMiddleware.ContextKey{}).(*validator.ValidatedClaims)
+	if !ok || claims == nil || claims.RegisteredClaims.Subject == "" {
+		return Principal{}, false
+	}
+	var method Method
+	var grants interface{ HasScope(string) bool }
+	var profile Profile
+	switch c := claims.CustomClaims.(type) {
+	case *CustomClaims:
+		if c == nil {
+			return Principal{}, false
+		}
+		method, grants = MethodJWT, c
+		profile = Profile{Name: c.Name, Email: c.Email, Picture: c.Picture}
+	case *AccessTokenClaims:
+		if c == nil {
+			return Principal{}, false
+		}
+		method, grants = MethodAccessToken, c
+	default:
+		return Principal{}, false
+	}
+	scopes := make([]string, 0, len(AllScopes))
+	for _, scope := range AllScopes {
+		if grants.HasScope(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return Principal{
+		UserID:  claims.RegisteredClaims.Subject,
+		Scopes:  scopes,
+		Method:  method,
+		Profile: profile,
+	}, true
+}
@@ -0,0 +1,126 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	jwtMiddleware "github.com/auth0/go-jwt-middleware/v2"
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+type unknownClaims struct{}
+
+func (unknownClaims) Validate(context.Context) error {
+	return nil
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	tests := map[string]struct {
+		ctx    context.Context
+		want   auth.Principal
+		wantOK bool
+	}{
+		"jwt": {
+			ctx: context.WithValue(context.Background(), jwtMiddleware.ContextKey{}, &validator.ValidatedClaims{
+				RegisteredClaims: validator.RegisteredClaims{Subject: "auth0|123456"},
+				CustomClaims:     &auth.CustomClaims{Scope: "openid read:tasks write:tasks"},
+			}),
+			want: auth.Principal{
+				UserID: "auth0|123456",
+				Scopes: []string{auth.ScopeReadTasks, auth.ScopeWriteTasks},
+				Method: auth.MethodJWT,
+			},
+			wantOK: true,
+		},
+		"access token": {
+			ctx: context.WithValue(context.Background(), jwtMiddleware.ContextKey{}, &validator.ValidatedClaims{
+				RegisteredClaims: validator.RegisteredClaims{Subject: "auth0|123456"},
+				CustomClaims:     &auth.AccessTokenClaims{Scopes: []string{auth.ScopeReadUser}},
+			}),
+			want: auth.Principal{
+				UserID: "auth0|123456",
+				Scopes: []string{auth.ScopeReadUser},
+				Method: auth.MethodAccessToken,
+			},
+			wantOK: true,
+		},
+		"principal in context": {
+			ctx: auth.ContextWithPrincipal(context.Background(), auth.Principal{
+				UserID: "auth0|123456",
+				Scopes: []string{auth.ScopeReadTasks},
+				Method: auth.MethodJWT,
+			}),
+			want: auth.Principal{
+				UserID: "auth0|123456",
+				Scopes: []string{auth.ScopeReadTasks},
+				Method: auth.MethodJWT,
+			},
+			wantOK: true,
+		},
+		"anonymous": {
+			ctx:    context.Background(),
+			want:   auth.Principal{},
+			wantOK: false,
+		},
+		"anonymous principal in context": {
+			ctx:    auth.ContextWithPrincipal(context.Background(), auth.Principal{}),
+			want:   auth.Principal{},
+			wantOK: false,
+		},
+		"claims of unexpected type": {
+			ctx:    context.WithValue(context.Background(), jwtMiddleware.ContextKey{}, "token"),
+			want:   auth.Principal{},
+			wantOK: false,
+		},
+		"nil claims": {
+			ctx:    context.WithValue(context.Background(), jwtMiddleware.ContextKey{}, (*validator.ValidatedClaims)(nil)),
+			want:   auth.Principal{},
+			wantOK: false,
+		},
+		"no subject": {
+			ctx: context.WithValue(context.Background(), jwtMiddleware.ContextKey{}, &validator.ValidatedClaims{
+				CustomClaims: &auth.CustomClaims{Scope: "read:tasks"},
+			}),
+			want:   auth.Principal{},
+			wantOK: false,
+		},
+		"no custom claims": {
+			ctx: context.WithValue(context.Background(), jwtMiddleware.ContextKey{}, &validator.ValidatedClaims{
+				RegisteredClaims: validator.RegisteredClaims{Subject: "auth0|123456"},
+			}),
+			want:   auth.Principal{},
+			wantOK: false,
+		},
+		"nil custom claims": {
+			ctx: context.WithValue(context.Background(), jwtMiddleware.ContextKey{}, &validator.ValidatedClaims{
+				RegisteredClaims: validator.RegisteredClaims{Subject: "auth0|123456"},
+				CustomClaims:     (*auth.CustomClaims)(nil),
+			}),
+			want:   auth.Principal{},
+			wantOK: false,
+		},
+		"unknown custom claims": {
+			ctx: context.WithValue(context.Background(), jwtMiddleware.ContextKey{}, &validator.ValidatedClaims{
+				RegisteredClaims: validator.RegisteredClaims{Subject: "auth0|123456"},
+				CustomClaims:     unknownClaims{},
+			}),
+			want:   auth.Principal{},
+			wantOK: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := auth.PrincipalFromContext(tt.ctx)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestPrincipal_HasScope(t *testing.T) {
+	sut := auth.Principal{Scopes: []string{auth.ScopeReadTasks}}
+	assert.True(t, sut.HasScope(auth.ScopeReadTasks))
+	assert.False(t, sut.HasScope(auth.ScopeWriteTasks))
+}
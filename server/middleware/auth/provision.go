@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/shota-tech/graphql/server/cache"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/logging"
+	"github.com/shota-tech/graphql/server/repository"
+)
+
+const (
+	// syncedUsersSize bounds the users remembered as synced, the least recently seen are synced again.
+	syncedUsersSize = 10000
+	// syncedUsersTTL is how long a synced profile is trusted before it is compared again.
+	syncedUsersTTL = time.Hour
+)
+
+type UserStore interface {
+	Store(context.Context, *model.User) error
+	Get(context.Context, string) (*model.User, error)
+}
+
+type ActivityStore interface {
+	Store(context.Context, *model.Activity) error
+}
+
+// ResponseCache drops the responses cached for a user.
+type ResponseCache interface {
+	Invalidate(userID string)
+}
+
+// ProvisionUser loads the user of the principal, creating it on its first authenticated request,
+// and attaches its role to the principal of the request.
+// The email and avatar are synced with the profile given by the identity provider
+// at most once per syncedUsersTTL.
+// The users listed in adminIDs are made admins when they are provisioned, which bootstraps
+// the first admin of a deployment; demoting them only lasts until they are synced again.
+// Creating the user and promoting it are recorded as activities like createUser and updateUserRole.
+// The responses cached for a user, which may be nil, are dropped once its changes are committed.
+// Users already synced are read from a replica, the others are read and written in a transaction
+// on the primary so that a user created or renamed just before is not overwritten.
+// When the user can't be loaded the principal has no role, which is denied by any policy.
+func ProvisionUser(transactor repository.ITransactor, users UserStore, activities ActivityStore, responses ResponseCache, adminIDs []string) func(next http.Handler) http.Handler {
+	p := &provisioner{
+		transactor: transactor,
+		users:      users,
+		activities: activities,
+		responses:  responses,
+		admins:     make(map[string]bool, len(adminIDs)),
+		synced:     cache.NewLRU[string, struct{}](syncedUsersSize, syncedUsersTTL),
+	}
+	for _, id := range adminIDs {
+		p.admins[id] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			user, err := p.provision(r.Context(), principal)
+			if err != nil {
+				logging.FromContext(r.Context()).Error("failed to provision user", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			principal.Role = user.Role
+			next.ServeHTTP(w, r.WithContext(ContextWithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+type provisioner struct {
+	transactor repository.ITransactor
+	users      UserStore
+	activities ActivityStore
+	responses  ResponseCache
+	admins     map[string]bool
+	synced     *cache.LRU[string, struct{}]
+}
+
+func (p *provisioner) provision(ctx context.Context, principal Principal) (*model.User, error) {
+	if _, ok := p.synced.Get(principal.UserID); ok {
+		user, err := p.users.Get(ctx, principal.UserID)
+		if err == nil {
+			return user, nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+	}
+	var user *model.User
+	var updated bool
+	err := p.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, updated, err = p.sync(ctx, principal)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updated && p.responses != nil {
+		p.responses.Invalidate(user.ID)
+	}
+	p.synced.Add(user.ID, struct{}{})
+	return user, nil
+}
+
+// sync creates the user or updates the fields owned by the identity provider,
+// telling whether an existing user was updated.
+func (p *provisioner) sync(ctx context.Context, principal Principal) (*model.User, bool, error) {
+	user, err := p.users.Get(ctx, principal.UserID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, false, err
+	}
+	profile := principal.Profile
+	if user == nil {
+		user = &model.User{
+			ID:        principal.UserID,
+			Name:      displayName(principal),
+			Email:     optional(profile.Email),
+			AvatarURL: optional(profile.Picture),
+			Role:      model.RoleMember,
+		}
+		if p.admins[user.ID] {
+			user.Role = model.RoleAdmin
+		}
+		if err := p.users.Store(ctx, user); err != nil {
+			return nil, false, fmt.Errorf("failed to create user: %w", err)
+		}
+		changes := []*model.FieldChange{{Field: "name", After: &user.Name}}
+		if err := p.record(ctx, user.ID, model.ActivityActionCreate, changes); err != nil {
+			return nil, false, err
+		}
+		return user, false, nil
+	}
+
+	// the name may have been chosen by the user, only the fields owned by the provider are synced.
+	changed := false
+	if profile.Email != "" && (user.Email == nil || *user.Email != profile.Email) {
+		user.Email = &profile.Email
+		changed = true
+	}
+	if profile.Picture != "" && (user.AvatarURL == nil || *user.AvatarURL != profile.Picture) {
+		user.AvatarURL = &profile.Picture
+		changed = true
+	}
+	var promoted *model.FieldChange
+	if p.admins[user.ID] && user.Role != model.RoleAdmin {
+		before, after := user.Role.String(), model.RoleAdmin.String()
+		promoted = &model.FieldChange{Field: "role", Before: &before, After: &after}
+		user.Role = model.RoleAdmin
+		changed = true
+	}
+	if changed {
+		if err := p.users.Store(ctx, user); err != nil {
+			return nil, false, fmt.Errorf("failed to update user: %w", err)
+		}
+	}
+	if promoted != nil {
+		if err := p.record(ctx, user.ID, model.ActivityActionUpdate, []*model.FieldChange{promoted}); err != nil {
+			return nil, false, err
+		}
+	}
+	return user, changed, nil
+}
+
+// record stores an activity of the user on itself.
+func (p *provisioner) record(ctx context.Context, userID string, action model.ActivityAction, changes []*model.FieldChange) error {
+	activity := &model.Activity{
+		ID:         xid.New().String(),
+		UserID:     userID,
+		EntityType: model.EntityTypeUser,
+		EntityID:   userID,
+		Action:     action,
+		Changes:    changes,
+	}
+	if err := p.activities.Store(ctx, activity); err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+	return nil
+}
+
+func displayName(principal Principal) string {
+	switch {
+	case principal.Profile.Name != "":
+		return principal.Profile.Name
+	case principal.Profile.Email != "":
+		return principal.Profile.Email
+	default:
+		return principal.UserID
+	}
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
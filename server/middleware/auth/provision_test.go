@@ -0,0 +1,224 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/shota-tech/graphql/server/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type userStore struct {
+	users  map[string]*model.User
+	stored int
+	err    error
+}
+
+func (s *userStore) Store(_ context.Context, user *model.User) error {
+	if s.err != nil {
+		return s.err
+	}
+	u := *user
+	s.users[user.ID] = &u
+	s.stored++
+	return nil
+}
+
+func (s *userStore) Get(_ context.Context, id string) (*model.User, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	user, ok := s.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	u := *user
+	return &u, nil
+}
+
+// transactor runs fn without a transaction, counting the calls.
+type transactor struct {
+	calls int
+}
+
+func (t *transactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.calls++
+	return fn(ctx)
+}
+
+type activityStore struct {
+	activities []*model.Activity
+}
+
+func (s *activityStore) Store(_ context.Context, activity *model.Activity) error {
+	s.activities = append(s.activities, activity)
+	return nil
+}
+
+// responseCache records the users whose responses are invalidated.
+type responseCache struct {
+	invalidated []string
+}
+
+func (c *responseCache) Invalidate(userID string) {
+	c.invalidated = append(c.invalidated, userID)
+}
+
+func TestProvisionUser(t *testing.T) {
+	email := "user1@example.com"
+	oldEmail := "old@example.com"
+	picture := "https://example.com/user1.png"
+	principal := auth.Principal{
+		UserID: "auth0|123456",
+		Method: auth.MethodJWT,
+		Profile: auth.Profile{
+			Name:    "user1",
+			Email:   email,
+			Picture: picture,
+		},
+	}
+	tests := map[string]struct {
+		users      map[string]*model.User
+		adminIDs   []string
+		principal  *auth.Principal
+		err        error
+		want       map[string]*model.User
+		wantRole   model.Role
+		wantStored int
+		// wantActions are the actions of the activities recorded
+		wantActions []model.ActivityAction
+		// wantTransactions is the number of requests which synced the user on the primary
+		wantTransactions int
+		// wantInvalidated are the users whose cached responses were dropped
+		wantInvalidated []string
+	}{
+		"first login": {
+			users:     map[string]*model.User{},
+			principal: &principal,
+			want: map[string]*model.User{
+				"auth0|123456": {ID: "auth0|123456", Name: "user1", Email: &email, AvatarURL: &picture, Role: model.RoleMember},
+			},
+			wantRole:         model.RoleMember,
+			wantStored:       1,
+			wantTransactions: 1,
+			wantActions:      []model.ActivityAction{model.ActivityActionCreate},
+		},
+		"first login without profile": {
+			users:     map[string]*model.User{},
+			principal: &auth.Principal{UserID: "auth0|123456", Method: auth.MethodJWT},
+			want: map[string]*model.User{
+				"auth0|123456": {ID: "auth0|123456", Name: "auth0|123456", Role: model.RoleMember},
+			},
+			wantRole:         model.RoleMember,
+			wantStored:       1,
+			wantTransactions: 1,
+			wantActions:      []model.ActivityAction{model.ActivityActionCreate},
+		},
+		"existing user keeps its name": {
+			users: map[string]*model.User{
+				"auth0|123456": {ID: "auth0|123456", Name: "nickname", Email: &oldEmail, Role: model.RoleAdmin},
+			},
+			principal: &principal,
+			want: map[string]*model.User{
+				"auth0|123456": {ID: "auth0|123456", Name: "nickname", Email: &email, AvatarURL: &picture, Role: model.RoleAdmin},
+			},
+			wantRole:         model.RoleAdmin,
+			wantStored:       1,
+			wantTransactions: 1,
+			wantInvalidated:  []string{"auth0|123456"},
+		},
+		"first login of an admin": {
+			users:     map[string]*model.User{},
+			adminIDs:  []string{"auth0|admin", "auth0|123456"},
+			principal: &principal,
+			want: map[string]*model.User{
+				"auth0|123456": {ID: "auth0|123456", Name: "user1", Email: &email, AvatarURL: &picture, Role: model.RoleAdmin},
+			},
+			wantRole:         model.RoleAdmin,
+			wantStored:       1,
+			wantTransactions: 1,
+			wantActions:      []model.ActivityAction{model.ActivityActionCreate},
+		},
+		"existing user promoted to admin": {
+			users: map[string]*model.User{
+				"auth0|123456": {ID: "auth0|123456", Name: "nickname", Email: &email, AvatarURL: &picture, Role: model.RoleMember},
+			},
+			adminIDs:  []string{"auth0|123456"},
+			principal: &principal,
+			want: map[string]*model.User{
+				"auth0|123456": {ID: "auth0|123456", Name: "nickname", Email: &email, AvatarURL: &picture, Role: model.RoleAdmin},
+			},
+			wantRole:         model.RoleAdmin,
+			wantStored:       1,
+			wantTransactions: 1,
+			wantActions:      []model.ActivityAction{model.ActivityActionUpdate},
+			wantInvalidated:  []string{"auth0|123456"},
+		},
+		"existing user up to date": {
+			users: map[string]*model.User{
+				"auth0|123456": {ID: "auth0|123456", Name: "nickname", Email: &email, AvatarURL: &picture, Role: model.RoleViewer},
+			},
+			principal: &principal,
+			want: map[string]*model.User{
+				"auth0|123456": {ID: "auth0|123456", Name: "nickname", Email: &email, AvatarURL: &picture, Role: model.RoleViewer},
+			},
+			wantRole:         model.RoleViewer,
+			wantStored:       0,
+			wantTransactions: 1,
+		},
+		"anonymous": {
+			users:            map[string]*model.User{},
+			principal:        nil,
+			want:             map[string]*model.User{},
+			wantStored:       0,
+			wantTransactions: 0,
+		},
+		"failed to provision": {
+			users:            map[string]*model.User{},
+			principal:        &principal,
+			err:              assert.AnError,
+			want:             map[string]*model.User{},
+			wantStored:       0,
+			wantTransactions: 2,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := &userStore{users: tt.users, err: tt.err}
+			activities := &activityStore{}
+			called := 0
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called++
+				principal, _ := auth.PrincipalFromContext(r.Context())
+				assert.Equal(t, tt.wantRole, principal.Role)
+			})
+			// test
+			tx := &transactor{}
+			responses := &responseCache{}
+			sut := auth.ProvisionUser(tx, store, activities, responses, tt.adminIDs)(next)
+			for i := 0; i < 2; i++ {
+				req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+				if tt.principal != nil {
+					req = req.WithContext(auth.ContextWithPrincipal(req.Context(), *tt.principal))
+				}
+				sut.ServeHTTP(httptest.NewRecorder(), req)
+			}
+			assert.Equal(t, 2, called)
+			assert.Equal(t, tt.want, store.users)
+			assert.Equal(t, tt.wantStored, store.stored)
+			assert.Equal(t, tt.wantTransactions, tx.calls)
+			var actions []model.ActivityAction
+			for _, activity := range activities.activities {
+				assert.Equal(t, model.EntityTypeUser, activity.EntityType)
+				assert.Equal(t, tt.principal.UserID, activity.EntityID)
+				actions = append(actions, activity.Action)
+			}
+			assert.Equal(t, tt.wantActions, actions)
+			assert.Equal(t, tt.wantInvalidated, responses.invalidated)
+		})
+	}
+}
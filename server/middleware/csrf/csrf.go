@@ -0,0 +1,55 @@
+package csrf
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// simpleContentTypes are the content types a HTML form can send, which need no CORS preflight.
+var simpleContentTypes = map[string]bool{
+	"application/x-www-form-urlencoded": true,
+	"multipart/form-data":               true,
+	"text/plain":                        true,
+}
+
+// RequirePreflight rejects the requests a browser may send cross-site without a CORS preflight,
+// i.e. GET requests and POST requests without a content type or with one a form can send,
+// unless they carry one of the headers, which a page can only set once the preflight allowed it.
+// Preflights themselves are answered by the CORS handler.
+func RequirePreflight(headers ...string) func(next http.Handler) http.Handler {
+	message := fmt.Sprintf(`{"errors":[{"message":"this request requires a CORS preflight, set one of the headers: %s"}]}`, strings.Join(headers, ", "))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !needsPreflight(r, headers) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(message))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// needsPreflight reports whether a browser had to send a preflight before the request.
+func needsPreflight(r *http.Request, headers []string) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPost:
+	default:
+		return true
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !simpleContentTypes[mediaType] {
+			return true
+		}
+	}
+	for _, header := range headers {
+		if r.Header.Get(header) != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package csrf_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shota-tech/graphql/server/middleware/csrf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirePreflight(t *testing.T) {
+	tests := map[string]struct {
+		method      string
+		contentType string
+		headers     map[string]string
+		wantStatus  int
+		wantBody    string
+	}{
+		"json post": {
+			method:      http.MethodPost,
+			contentType: "application/json; charset=utf-8",
+			wantStatus:  http.StatusOK,
+			wantBody:    "ok",
+		},
+		"form post": {
+			method:      http.MethodPost,
+			contentType: "application/x-www-form-urlencoded",
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"errors":[{"message":"this request requires a CORS preflight, set one of the headers: Authorization, X-CSRF-Token"}]}`,
+		},
+		"multipart post": {
+			method:      http.MethodPost,
+			contentType: "multipart/form-data; boundary=xyz",
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"errors":[{"message":"this request requires a CORS preflight, set one of the headers: Authorization, X-CSRF-Token"}]}`,
+		},
+		"multipart post with header": {
+			method:      http.MethodPost,
+			contentType: "multipart/form-data; boundary=xyz",
+			headers:     map[string]string{"X-CSRF-Token": "1"},
+			wantStatus:  http.StatusOK,
+			wantBody:    "ok",
+		},
+		"text post": {
+			method:      http.MethodPost,
+			contentType: "text/plain",
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"errors":[{"message":"this request requires a CORS preflight, set one of the headers: Authorization, X-CSRF-Token"}]}`,
+		},
+		"invalid content type": {
+			method:      http.MethodPost,
+			contentType: "text/plain; =",
+			wantStatus:  http.StatusOK,
+			wantBody:    "ok",
+		},
+		"get": {
+			method:     http.MethodGet,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"errors":[{"message":"this request requires a CORS preflight, set one of the headers: Authorization, X-CSRF-Token"}]}`,
+		},
+		"get with authorization": {
+			method:     http.MethodGet,
+			headers:    map[string]string{"Authorization": "Bearer token"},
+			wantStatus: http.StatusOK,
+			wantBody:   "ok",
+		},
+		"put": {
+			method:      http.MethodPut,
+			contentType: "text/plain",
+			wantStatus:  http.StatusOK,
+			wantBody:    "ok",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sut := csrf.RequirePreflight("Authorization", "X-CSRF-Token")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("ok"))
+			}))
+			// test
+			req := httptest.NewRequest(tt.method, "/graphql", strings.NewReader(""))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			sut.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package limit
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	errDepthLimit  = "DEPTH_LIMIT_EXCEEDED"
+	depthExtension = "DepthLimit"
+)
+
+// DepthLimit rejects operations whose fields are nested deeper than the limit,
+// such as the cycles between users, tasks and todos.
+// Introspection fields are not counted so that GraphQL tooling keeps working.
+// It is shaped after gqlgen's extension.ComplexityLimit
+// (github.com/99designs/gqlgen/graphql/handler/extension/complexity.go),
+// which limits the cost of an operation but not how deep it is nested.
+type DepthLimit struct {
+	// Func returns the limit of the operation, so that it can depend on the caller.
+	Func func(ctx context.Context, rc *graphql.OperationContext) int
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = &DepthLimit{}
+
+// DepthStats is attached to the stats of the operation for logging and tests.
+type DepthStats struct {
+	// Depth is the number of nested fields of the deepest branch.
+	Depth int
+	// DepthLimit is the limit Func returned for the operation.
+	DepthLimit int
+}
+
+// FixedDepthLimit limits every operation to the same depth.
+func FixedDepthLimit(limit int) *DepthLimit {
+	return &DepthLimit{
+		Func: func(context.Context, *graphql.OperationContext) int {
+			return limit
+		},
+	}
+}
+
+func (d DepthLimit) ExtensionName() string {
+	return depthExtension
+}
+
+func (d *DepthLimit) Validate(graphql.ExecutableSchema) error {
+	if d.Func == nil {
+		return errors.New("depth limit func must not be nil")
+	}
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	depth := selectionSetDepth(rc.Operation.SelectionSet)
+	limit := d.Func(ctx, rc)
+	rc.Stats.SetExtension(depthExtension, &DepthStats{Depth: depth, DepthLimit: limit})
+	if depth > limit {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, limit)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+	return nil
+}
+
+// GetDepthStats returns the stats of the operation in ctx, nil outside of an operation.
+func GetDepthStats(ctx context.Context) *DepthStats {
+	rc := graphql.GetOperationContext(ctx)
+	if rc == nil {
+		return nil
+	}
+	stats, _ := rc.Stats.GetExtension(depthExtension).(*DepthStats)
+	return stats
+}
+
+// selectionSetDepth returns the number of nested fields of the deepest branch.
+// Fragments are inlined, the validation has already rejected fragment cycles.
+func selectionSetDepth(set ast.SelectionSet) int {
+	max := 0
+	for _, selection := range set {
+		var depth int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			depth = 1 + selectionSetDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			depth = selectionSetDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				depth = selectionSetDepth(s.Definition.SelectionSet)
+			}
+		}
+		if depth > max {
+			max = depth
+		}
+	}
+	return max
+}
@@ -0,0 +1,65 @@
+package limit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/shota-tech/graphql/server/graph"
+	"github.com/shota-tech/graphql/server/middleware/limit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepthLimit(t *testing.T) {
+	tests := map[string]struct {
+		query     string
+		wantDepth int
+		wantErr   string
+	}{
+		"within the limit": {
+			query:     `{ fetchTasks { user { tasks { id } } } }`,
+			wantDepth: 4,
+		},
+		"exceeds the limit": {
+			query:   `{ fetchTasks { user { tasks { user { id } } } } }`,
+			wantErr: `[{"message":"operation has depth 5, which exceeds the limit of 4","extensions":{"code":"DEPTH_LIMIT_EXCEEDED"}}]`,
+		},
+		"fragments are inlined": {
+			query:   `{ fetchTasks { ...task } } fragment task on Task { user { ... on User { tasks { user { id } } } } }`,
+			wantErr: `[{"message":"operation has depth 5, which exceeds the limit of 4","extensions":{"code":"DEPTH_LIMIT_EXCEEDED"}}]`,
+		},
+		"introspection is not counted": {
+			query:     `{ __schema { types { fields { type { ofType { ofType { name } } } } } } }`,
+			wantDepth: 0,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+			srv.AddTransport(transport.POST{})
+			srv.Use(extension.Introspection{})
+			srv.Use(limit.FixedDepthLimit(4))
+			var depth int
+			// stop operations once the limit is checked, the resolvers have no repositories.
+			srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+				depth = limit.GetDepthStats(ctx).Depth
+				return graphql.OneShot(graphql.ErrorResponse(ctx, "stopped"))
+			})
+			// test
+			c := client.New(srv)
+			var resp interface{}
+			err := c.Post(tt.query, &resp)
+			require.Error(t, err)
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			assert.Equal(t, tt.wantDepth, depth)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package persisted
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errPersistedQueryNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+
+// Allowlist only executes operations found in the manifest.
+// Use it after AutomaticPersistedQuery so that queries resolved from the APQ cache are checked too.
+type Allowlist struct {
+	Manifest Manifest
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = Allowlist{}
+
+func (a Allowlist) ExtensionName() string {
+	return "PersistedQueryAllowlist"
+}
+
+func (a Allowlist) Validate(schema graphql.ExecutableSchema) error {
+	if a.Manifest == nil {
+		return fmt.Errorf("PersistedQueryAllowlist manifest can not be nil")
+	}
+	return nil
+}
+
+func (a Allowlist) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	if a.Manifest.Contains(rawParams.Query) {
+		return nil
+	}
+	err := gqlerror.Errorf("operation is not in the persisted query allowlist")
+	errcode.Set(err, errPersistedQueryNotAllowed)
+	return err
+}
@@ -0,0 +1,121 @@
+package persisted_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/shota-tech/graphql/server/graph"
+	"github.com/shota-tech/graphql/server/middleware/persisted"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fetchUser = `query fetchUser {
+  fetchUser {
+    id
+    name
+  }
+}
+`
+
+func TestAllowlist(t *testing.T) {
+	schema := graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}})
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fetchUser.graphql"), []byte(fetchUser), 0o644))
+	manifest, err := persisted.GenerateManifest(schema.Schema(), dir)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		query   string
+		options []client.Option
+		wantErr string
+	}{
+		"operation in the manifest": {
+			query: fetchUser,
+		},
+		"formatted differently": {
+			query: `query fetchUser { fetchUser { id, name } }`,
+		},
+		"operation not in the manifest": {
+			query:   `query fetchUser { fetchUser { id name tasks { id } } }`,
+			wantErr: `[{"message":"operation is not in the persisted query allowlist","extensions":{"code":"PERSISTED_QUERY_NOT_ALLOWED"}}]`,
+		},
+		"unknown apq hash": {
+			query:   "",
+			options: []client.Option{client.Extensions(map[string]interface{}{"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": "unknown"}})},
+			wantErr: `[{"message":"PersistedQueryNotFound","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}]`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := handler.New(schema)
+			srv.AddTransport(transport.POST{})
+			srv.Use(extension.AutomaticPersistedQuery{Cache: graphql.MapCache{}})
+			srv.Use(persisted.Allowlist{Manifest: manifest})
+			// stop operations once they are allowed, the resolvers have no repositories.
+			srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+				return graphql.OneShot(graphql.ErrorResponse(ctx, "stopped"))
+			})
+			// test
+			c := client.New(srv)
+			var resp interface{}
+			err := c.Post(tt.query, &resp, tt.options...)
+			require.Error(t, err)
+			if tt.wantErr == "" {
+				tt.wantErr = `[{"message":"stopped"}]`
+			}
+			assert.Equal(t, tt.wantErr, err.Error())
+		})
+	}
+}
+
+func TestGenerateManifest(t *testing.T) {
+	schema := graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}).Schema()
+	tests := map[string]struct {
+		query     string
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			query:     fetchUser,
+			assertErr: assert.NoError,
+		},
+		"unknown field": {
+			query:     `query fetchUser { fetchUser { password } }`,
+			assertErr: assert.Error,
+		},
+		"syntax error": {
+			query:     `query fetchUser {`,
+			assertErr: assert.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "operation.graphql")
+			require.NoError(t, os.WriteFile(path, []byte(tt.query), 0o644))
+			got, err := persisted.GenerateManifest(schema, path)
+			tt.assertErr(t, err)
+			if err != nil {
+				return
+			}
+			h, err := persisted.Hash(tt.query)
+			require.NoError(t, err)
+			assert.Contains(t, got, h)
+		})
+	}
+}
+
+func TestManifest_OperationNames(t *testing.T) {
+	manifest := persisted.Manifest{
+		"fetchUser": fetchUser,
+		"anonymous": `{ fetchUser { id } }`,
+		"invalid":   `query broken {`,
+	}
+	assert.Equal(t, []string{"fetchUser"}, manifest.OperationNames())
+}
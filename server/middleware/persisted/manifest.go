@@ -0,0 +1,132 @@
+package persisted
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Manifest maps the hash of each normalized operation document to the document.
+type Manifest map[string]string
+
+// Hash returns the sha256 of the normalized query, so that whitespace,
+// commas and the printer of the client do not change the hash of an operation.
+func Hash(query string) (string, error) {
+	normalized, err := normalize(query)
+	if err != nil {
+		return "", err
+	}
+	return hash(normalized), nil
+}
+
+// GenerateManifest adds every document of the files to the manifest,
+// validating them against the schema first. A directory stands for its *.graphql files.
+func GenerateManifest(schema *ast.Schema, paths ...string) (Manifest, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.graphql"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", path, err)
+		}
+		files = append(files, matches...)
+	}
+
+	manifest := Manifest{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if _, errs := gqlparser.LoadQuery(schema, string(data)); errs != nil {
+			return nil, fmt.Errorf("invalid operation in %s: %w", file, errs)
+		}
+		normalized, err := normalize(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid operation in %s: %w", file, err)
+		}
+		manifest[hash(normalized)] = normalized
+	}
+	return manifest, nil
+}
+
+func LoadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func (m Manifest) Write(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+func (m Manifest) Contains(query string) bool {
+	h, err := Hash(query)
+	if err != nil {
+		return false
+	}
+	_, ok := m[h]
+	return ok
+}
+
+// OperationNames returns the names of the operations of the manifest, the documents being valid when loaded.
+func (m Manifest) OperationNames() []string {
+	var names []string
+	for _, document := range m {
+		doc, err := parser.ParseQuery(&ast.Source{Input: document})
+		if err != nil {
+			continue
+		}
+		for _, op := range doc.Operations {
+			if op.Name != "" {
+				names = append(names, op.Name)
+			}
+		}
+	}
+	return names
+}
+
+func normalize(query string) (string, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf, formatter.WithIndent("  ")).FormatQueryDocument(doc)
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func hash(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
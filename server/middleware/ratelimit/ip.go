@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ByIP spends a token of the client IP address for each request, before the caller is authenticated,
+// so that requests with missing or invalid credentials are limited too. A nil limiter doesn't limit.
+// Rejected requests get the status 429, a Retry-After header and a GraphQL error.
+func ByIP(limiter Limiter) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, retryAfter := limiter.Allow(r.Context(), ipKey(r))
+			if allowed {
+				next.ServeHTTP(w, r)
+				return
+			}
+			seconds := retryAfterSeconds(retryAfter)
+			err := &gqlerror.Error{
+				Message: fmt.Sprintf("rate limit exceeded, retry in %d seconds", seconds),
+				Extensions: map[string]interface{}{
+					"code":       errRateLimited,
+					"retryAfter": seconds,
+				},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": gqlerror.List{err}, "data": nil})
+		})
+	}
+}
+
+// RealIP replaces the remote address of the requests sent by a trusted proxy
+// with the client IP address it forwards, as chi's RealIP does.
+// The headers of other requests are ignored, clients could spoof them to get a fresh budget.
+func RealIP(trusted []netip.Prefix) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(trusted) == 0 {
+			return next
+		}
+		realIP := chiMiddleware.RealIP(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if addr, err := netip.ParseAddr(host(r.RemoteAddr)); err == nil && contains(trusted, addr.Unmap()) {
+				realIP.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ParsePrefixes parses the addresses of trusted proxies such as "10.0.0.0/8" or "192.0.2.1".
+func ParsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		if !strings.Contains(v, "/") {
+			addr, err := netip.ParseAddr(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid proxy address: %s", v)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy address: %s", v)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func contains(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func ipKey(r *http.Request) string {
+	return "ip:" + host(r.RemoteAddr)
+}
+
+// host strips the port of a remote address, chi's RealIP sets it without one.
+func host(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
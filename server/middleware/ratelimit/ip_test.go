@@ -0,0 +1,154 @@
+package ratelimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/shota-tech/graphql/server/middleware/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByIP(t *testing.T) {
+	tests := map[string]struct {
+		limiter        ratelimit.Limiter
+		remoteAddrs    []string
+		wantStatus     int
+		wantRetryAfter string
+		wantBody       string
+	}{
+		"first request": {
+			limiter:     &fakeLimiter{},
+			remoteAddrs: []string{"192.0.2.1:1234"},
+			wantStatus:  http.StatusOK,
+		},
+		"limited": {
+			limiter:        &fakeLimiter{},
+			remoteAddrs:    []string{"192.0.2.1:1234", "192.0.2.1:5678"},
+			wantStatus:     http.StatusTooManyRequests,
+			wantRetryAfter: "2",
+			wantBody:       `{"errors":[{"message":"rate limit exceeded, retry in 2 seconds","extensions":{"code":"RATE_LIMITED","retryAfter":2}}],"data":null}`,
+		},
+		"addresses have their own budget": {
+			limiter:     &fakeLimiter{},
+			remoteAddrs: []string{"192.0.2.1:1234", "192.0.2.2:1234"},
+			wantStatus:  http.StatusOK,
+		},
+		"no limiter": {
+			limiter:     nil,
+			remoteAddrs: []string{"192.0.2.1:1234", "192.0.2.1:5678"},
+			wantStatus:  http.StatusOK,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			sut := ratelimit.ByIP(tt.limiter)(next)
+			// test
+			var rec *httptest.ResponseRecorder
+			for _, remoteAddr := range tt.remoteAddrs {
+				req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+				req.RemoteAddr = remoteAddr
+				rec = httptest.NewRecorder()
+				sut.ServeHTTP(rec, req)
+			}
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantRetryAfter, rec.Header().Get("Retry-After"))
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRealIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	tests := map[string]struct {
+		trusted    []netip.Prefix
+		remoteAddr string
+		header     http.Header
+		want       string
+	}{
+		"trusted proxy": {
+			trusted:    trusted,
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Real-Ip": []string{"192.0.2.1"}},
+			want:       "192.0.2.1",
+		},
+		"trusted proxy forwarding for": {
+			trusted:    trusted,
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": []string{"192.0.2.1"}},
+			want:       "192.0.2.1",
+		},
+		"untrusted client spoofing the header": {
+			trusted:    trusted,
+			remoteAddr: "192.0.2.1:1234",
+			header:     http.Header{"X-Real-Ip": []string{"192.0.2.2"}},
+			want:       "192.0.2.1:1234",
+		},
+		"no trusted proxy": {
+			trusted:    nil,
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Real-Ip": []string{"192.0.2.1"}},
+			want:       "10.0.0.1:1234",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			})
+			req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header = tt.header
+			// test
+			ratelimit.RealIP(tt.trusted)(next).ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePrefixes(t *testing.T) {
+	tests := map[string]struct {
+		values    []string
+		want      []netip.Prefix
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			values: []string{"10.1.2.3/8", "192.0.2.1", "2001:db8::/32"},
+			want: []netip.Prefix{
+				netip.MustParsePrefix("10.0.0.0/8"),
+				netip.MustParsePrefix("192.0.2.1/32"),
+				netip.MustParsePrefix("2001:db8::/32"),
+			},
+			assertErr: assert.NoError,
+		},
+		"empty": {
+			values:    nil,
+			want:      []netip.Prefix{},
+			assertErr: assert.NoError,
+		},
+		"invalid address": {
+			values:    []string{"proxy"},
+			assertErr: assert.Error,
+		},
+		"invalid prefix": {
+			values:    []string{"10.0.0.0/64"},
+			assertErr: assert.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ratelimit.ParsePrefixes(tt.values)
+			tt.assertErr(t, err)
+			if err == nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
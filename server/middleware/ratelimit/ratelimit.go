@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errRateLimited = "RATE_LIMITED"
+
+// RateLimit spends a token of the caller for each operation, queries and mutations having separate budgets.
+// Subscriptions are counted as queries. A nil limiter doesn't limit its operations.
+// The caller is identified by Middleware, which also turns the rejection into a 429 response.
+type RateLimit struct {
+	Queries   Limiter
+	Mutations Limiter
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = RateLimit{}
+
+type (
+	requestContextKey struct{}
+
+	// request is shared between Middleware and the extension.
+	request struct {
+		key        string
+		method     string
+		retryAfter time.Duration
+	}
+)
+
+func (r RateLimit) ExtensionName() string {
+	return "RateLimit"
+}
+
+func (r RateLimit) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (r RateLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	req, ok := ctx.Value(requestContextKey{}).(*request)
+	if !ok {
+		return nil
+	}
+	// the GET transport rejects anything but queries once the extensions ran, so they are not charged.
+	if req.method == http.MethodGet && rc.Operation.Operation != ast.Query {
+		return nil
+	}
+	limiter := r.Queries
+	if rc.Operation.Operation == ast.Mutation {
+		limiter = r.Mutations
+	}
+	if limiter == nil {
+		return nil
+	}
+	allowed, retryAfter := limiter.Allow(ctx, fmt.Sprintf("%s:%s", rc.Operation.Operation, req.key))
+	if allowed {
+		return nil
+	}
+	req.retryAfter = retryAfter
+	seconds := retryAfterSeconds(retryAfter)
+	return &gqlerror.Error{
+		Message: fmt.Sprintf("rate limit exceeded, retry in %d seconds", seconds),
+		Extensions: map[string]interface{}{
+			"code":       errRateLimited,
+			"retryAfter": seconds,
+		},
+	}
+}
+
+// Middleware identifies the caller by the subject of the principal, falling back to the IP address,
+// so it must run after the authentication middlewares. Unauthenticated requests are rejected before,
+// ByIP limits them.
+// Responses to rate limited operations get the status 429 and a Retry-After header.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &request{key: key(r), method: r.Method}
+		ctx := context.WithValue(r.Context(), requestContextKey{}, req)
+		// websocket connections are hijacked, their operations only get the GraphQL error.
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+		next.ServeHTTP(&responseWriter{ResponseWriter: w, req: req}, r.WithContext(ctx))
+	})
+}
+
+func key(r *http.Request) string {
+	if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+		return "user:" + principal.UserID
+	}
+	return ipKey(r)
+}
+
+func retryAfterSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	req         *request
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if w.req.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(w.req.retryAfter)))
+		statusCode = http.StatusTooManyRequests
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
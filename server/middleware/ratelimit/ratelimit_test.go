@@ -0,0 +1,142 @@
+package ratelimit_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/shota-tech/graphql/server/graph"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/shota-tech/graphql/server/middleware/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLimiter struct {
+	keys []string
+}
+
+// Allow allows the first operation of each key.
+func (l *fakeLimiter) Allow(ctx context.Context, key string) (bool, time.Duration) {
+	for _, k := range l.keys {
+		if k == key {
+			return false, 1500 * time.Millisecond
+		}
+	}
+	l.keys = append(l.keys, key)
+	return true, 0
+}
+
+func TestRateLimit(t *testing.T) {
+	const (
+		query    = `{"query":"{ fetchUser { id } }"}`
+		mutation = `{"query":"mutation { createUser(input: {name: \"user1\"}) { id } }"}`
+	)
+	type request struct {
+		// method is POST unless set, GET sends the query of the body in the url
+		method     string
+		body       string
+		userID     string
+		remoteAddr string
+	}
+	tests := map[string]struct {
+		requests       []request
+		wantStatus     int
+		wantRetryAfter string
+		wantBody       string
+	}{
+		"first operation": {
+			requests: []request{
+				{body: query, userID: "user1"},
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"errors":[{"message":"stopped"}],"data":null}`,
+		},
+		"limited": {
+			requests: []request{
+				{body: query, userID: "user1"},
+				{body: query, userID: "user1"},
+			},
+			wantStatus:     http.StatusTooManyRequests,
+			wantRetryAfter: "2",
+			wantBody:       `{"errors":[{"message":"rate limit exceeded, retry in 2 seconds","extensions":{"code":"RATE_LIMITED","retryAfter":2}}],"data":null}`,
+		},
+		"mutations have their own budget": {
+			requests: []request{
+				{body: query, userID: "user1"},
+				{body: mutation, userID: "user1"},
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"errors":[{"message":"stopped"}],"data":null}`,
+		},
+		"mutations rejected over GET are not charged": {
+			requests: []request{
+				{method: http.MethodGet, body: mutation, userID: "user1"},
+				{body: mutation, userID: "user1"},
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"errors":[{"message":"stopped"}],"data":null}`,
+		},
+		"users have their own budget": {
+			requests: []request{
+				{body: query, userID: "user1"},
+				{body: query, userID: "user2"},
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"errors":[{"message":"stopped"}],"data":null}`,
+		},
+		"anonymous callers are limited by ip": {
+			requests: []request{
+				{body: query, remoteAddr: "192.0.2.1:1234"},
+				{body: query, remoteAddr: "192.0.2.1:5678"},
+			},
+			wantStatus:     http.StatusTooManyRequests,
+			wantRetryAfter: "2",
+			wantBody:       `{"errors":[{"message":"rate limit exceeded, retry in 2 seconds","extensions":{"code":"RATE_LIMITED","retryAfter":2}}],"data":null}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+			srv.AddTransport(transport.GET{})
+			srv.AddTransport(transport.POST{})
+			srv.Use(ratelimit.RateLimit{Queries: &fakeLimiter{}, Mutations: &fakeLimiter{}})
+			// stop operations once they are allowed, the resolvers have no repositories.
+			srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+				return graphql.OneShot(graphql.ErrorResponse(ctx, "stopped"))
+			})
+			sut := ratelimit.Middleware(srv)
+			// test
+			var rec *httptest.ResponseRecorder
+			for _, r := range tt.requests {
+				req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(r.body))
+				req.Header.Set("Content-Type", "application/json")
+				if r.method == http.MethodGet {
+					var params struct{ Query string }
+					require.NoError(t, json.Unmarshal([]byte(r.body), &params))
+					req = httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(params.Query), nil)
+				}
+				if r.remoteAddr != "" {
+					req.RemoteAddr = r.remoteAddr
+				}
+				if r.userID != "" {
+					req = req.WithContext(auth.ContextWithPrincipal(req.Context(), auth.Principal{UserID: r.userID}))
+				}
+				rec = httptest.NewRecorder()
+				sut.ServeHTTP(rec, req)
+			}
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantRetryAfter, rec.Header().Get("Retry-After"))
+			require.True(t, json.Valid(rec.Body.Bytes()))
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Limiter decides whether the caller identified by the key may run one more operation,
+	// and if not, how long it should wait.
+	Limiter interface {
+		Allow(ctx context.Context, key string) (allowed bool, retryAfter time.Duration)
+	}
+
+	// Limit allows Requests per Period, all of which may be spent at once.
+	Limit struct {
+		Requests int
+		Period   time.Duration
+	}
+
+	// TokenBucket is a Limiter kept in the memory of this process.
+	// Each key gets a bucket of Requests tokens refilled evenly over the Period.
+	TokenBucket struct {
+		limit     Limit
+		now       func() time.Time
+		mu        sync.Mutex
+		buckets   map[string]*bucket
+		lastSweep time.Time
+	}
+
+	bucket struct {
+		tokens float64
+		last   time.Time
+	}
+)
+
+var _ Limiter = &TokenBucket{}
+
+// ParseLimit parses a limit such as "60/1m", a period of "s", "m" or "h" meaning one of them.
+func ParseLimit(s string) (Limit, error) {
+	requests, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit: %s", s)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit requests: %s", s)
+	}
+	if period == "s" || period == "m" || period == "h" {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit period: %s", s)
+	}
+	return Limit{Requests: n, Period: d}, nil
+}
+
+func NewTokenBucket(limit Limit) *TokenBucket {
+	return &TokenBucket{
+		limit:   limit,
+		now:     time.Now,
+		buckets: map[string]*bucket{},
+	}
+}
+
+func (tb *TokenBucket) Allow(ctx context.Context, key string) (bool, time.Duration) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	now := tb.now()
+	tb.sweep(now)
+
+	b, ok := tb.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(tb.limit.Requests), last: now}
+		tb.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(tb.limit.Requests), b.tokens+tb.refill(now.Sub(b.last)))
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	// time until the next token, rounded up so that retrying then succeeds
+	wait := time.Duration(math.Ceil((1 - b.tokens) * float64(tb.limit.Period) / float64(tb.limit.Requests)))
+	return false, wait
+}
+
+func (tb *TokenBucket) refill(elapsed time.Duration) float64 {
+	return float64(elapsed) * float64(tb.limit.Requests) / float64(tb.limit.Period)
+}
+
+// sweep forgets the buckets which have been refilled, once per period,
+// so that callers seen once don't stay in memory.
+func (tb *TokenBucket) sweep(now time.Time) {
+	if now.Sub(tb.lastSweep) < tb.limit.Period {
+		return
+	}
+	tb.lastSweep = now
+	for key, b := range tb.buckets {
+		if b.tokens+tb.refill(now.Sub(b.last)) >= float64(tb.limit.Requests) {
+			delete(tb.buckets, key)
+		}
+	}
+}
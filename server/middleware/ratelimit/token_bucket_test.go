@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_Allow(t *testing.T) {
+	type call struct {
+		key            string
+		after          time.Duration
+		wantAllowed    bool
+		wantRetryAfter time.Duration
+	}
+	tests := map[string]struct {
+		calls []call
+	}{
+		"burst up to the limit": {
+			calls: []call{
+				{key: "a", wantAllowed: true},
+				{key: "a", wantAllowed: true},
+				{key: "a", wantAllowed: false, wantRetryAfter: 30 * time.Second},
+			},
+		},
+		"refilled over the period": {
+			calls: []call{
+				{key: "a", wantAllowed: true},
+				{key: "a", wantAllowed: true},
+				{key: "a", after: 15 * time.Second, wantAllowed: false, wantRetryAfter: 15 * time.Second},
+				{key: "a", after: 15 * time.Second, wantAllowed: true},
+				{key: "a", wantAllowed: false, wantRetryAfter: 30 * time.Second},
+			},
+		},
+		"keys have their own bucket": {
+			calls: []call{
+				{key: "a", wantAllowed: true},
+				{key: "a", wantAllowed: true},
+				{key: "b", wantAllowed: true},
+				{key: "a", wantAllowed: false, wantRetryAfter: 30 * time.Second},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+			sut := NewTokenBucket(Limit{Requests: 2, Period: time.Minute})
+			sut.now = func() time.Time { return now }
+			for i, c := range tt.calls {
+				now = now.Add(c.after)
+				allowed, retryAfter := sut.Allow(context.Background(), c.key)
+				assert.Equal(t, c.wantAllowed, allowed, i)
+				assert.Equal(t, c.wantRetryAfter, retryAfter, i)
+			}
+		})
+	}
+}
+
+func TestTokenBucket_Sweep(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	sut := NewTokenBucket(Limit{Requests: 2, Period: time.Minute})
+	sut.now = func() time.Time { return now }
+	sut.Allow(context.Background(), "a")
+	now = now.Add(time.Minute)
+	sut.Allow(context.Background(), "b")
+	assert.NotContains(t, sut.buckets, "a")
+	assert.Contains(t, sut.buckets, "b")
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := map[string]struct {
+		s         string
+		want      Limit
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"per minute": {
+			s:         "60/m",
+			want:      Limit{Requests: 60, Period: time.Minute},
+			assertErr: assert.NoError,
+		},
+		"duration": {
+			s:         "10/30s",
+			want:      Limit{Requests: 10, Period: 30 * time.Second},
+			assertErr: assert.NoError,
+		},
+		"no period": {
+			s:         "60",
+			assertErr: assert.Error,
+		},
+		"zero requests": {
+			s:         "0/m",
+			assertErr: assert.Error,
+		},
+		"invalid period": {
+			s:         "60/week",
+			assertErr: assert.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseLimit(tt.s)
+			assert.Equal(t, tt.want, got)
+			tt.assertErr(t, err)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package respcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ConditionalGET lets clients revalidate the responses of GET queries with an ETag of the body.
+// Responses depend on the caller, so by default they are only cached privately and always revalidated.
+// A positive sharedMaxAge lets CDNs cache them for that long, which is only safe when the CDN
+// keys its cache by the Authorization header as Vary tells.
+// Failed operations, including persisted queries not found, are never cached.
+func ConditionalGET(sharedMaxAge time.Duration) func(next http.Handler) http.Handler {
+	cacheControl := "private, no-cache"
+	if sharedMaxAge > 0 {
+		cacheControl = fmt.Sprintf("public, max-age=0, s-maxage=%d", int(sharedMaxAge.Seconds()))
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// websocket connections are hijacked and stream their responses.
+			if r.Method != http.MethodGet || strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			rw := &responseWriter{header: http.Header{}, statusCode: http.StatusOK}
+			next.ServeHTTP(rw, r)
+
+			for key, values := range rw.header {
+				w.Header()[key] = values
+			}
+			w.Header().Add("Vary", "Authorization")
+			if rw.statusCode != http.StatusOK || hasErrors(rw.body.Bytes()) {
+				w.Header().Set("Cache-Control", "no-store")
+				w.WriteHeader(rw.statusCode)
+				w.Write(rw.body.Bytes())
+				return
+			}
+			sum := sha256.Sum256(rw.body.Bytes())
+			etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Cache-Control", cacheControl)
+			if matches(r.Header.Get("If-None-Match"), etag) {
+				w.Header().Del("Content-Type")
+				w.Header().Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write(rw.body.Bytes())
+		})
+	}
+}
+
+// hasErrors reports whether the GraphQL response has any error.
+func hasErrors(body []byte) bool {
+	var resp struct {
+		Errors []json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return true
+	}
+	return len(resp.Errors) > 0
+}
+
+// matches reports whether the If-None-Match header lists the etag, comparing weakly as RFC 9110 requires.
+func matches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// responseWriter buffers the response until its ETag is known.
+type responseWriter struct {
+	header      http.Header
+	body        bytes.Buffer
+	statusCode  int
+	wroteHeader bool
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.statusCode = statusCode
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
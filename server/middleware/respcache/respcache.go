@@ -0,0 +1,138 @@
+package respcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shota-tech/graphql/server/cache"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Cache keeps the data of query responses per user until they expire or the data of the user changes.
+// It lives in the memory of this process, so other instances of the server only see their own invalidations.
+type Cache struct {
+	lru         *cache.LRU[string, json.RawMessage]
+	mu          sync.Mutex
+	generations map[string]uint64
+}
+
+func New(size int, ttl time.Duration) *Cache {
+	return &Cache{
+		lru:         cache.NewLRU[string, json.RawMessage](size, ttl),
+		generations: map[string]uint64{},
+	}
+}
+
+// Invalidate drops the responses cached for the user.
+// They are no longer reachable and are left to the eviction of the LRU.
+func (c *Cache) Invalidate(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[userID]++
+}
+
+func (c *Cache) generation(userID string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generations[userID]
+}
+
+// key hashes the operation with everything its response depends on for the caller.
+// The generation is read before the operation runs, so a response racing an invalidation is stored unreachable.
+func (c *Cache) key(principal auth.Principal, rc *graphql.OperationContext) (string, error) {
+	variables, err := json.Marshal(rc.Variables)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, part := range []string{
+		principal.UserID,
+		strconv.FormatUint(c.generation(principal.UserID), 10),
+		string(principal.Role),
+		strings.Join(principal.Scopes, " "),
+		rc.RawQuery,
+		rc.OperationName,
+		string(variables),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Extension serves the queries of authenticated callers selecting only the fields from the cache.
+// Responses with errors are never cached.
+type Extension struct {
+	Cache  *Cache
+	Fields []string
+}
+
+var _ interface {
+	graphql.OperationInterceptor
+	graphql.HandlerExtension
+} = Extension{}
+
+func (e Extension) ExtensionName() string {
+	return "ResponseCache"
+}
+
+func (e Extension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (e Extension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	rc := graphql.GetOperationContext(ctx)
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || !e.cacheable(rc.Operation) {
+		return next(ctx)
+	}
+	key, err := e.Cache.key(principal, rc)
+	if err != nil {
+		return next(ctx)
+	}
+	if data, ok := e.Cache.lru.Get(key); ok {
+		return graphql.OneShot(&graphql.Response{Data: data})
+	}
+	responses := next(ctx)
+	return func(ctx context.Context) *graphql.Response {
+		resp := responses(ctx)
+		if resp != nil && len(resp.Errors) == 0 {
+			e.Cache.lru.Add(key, resp.Data)
+		}
+		return resp
+	}
+}
+
+// cacheable reports whether the operation is a query selecting only the fields at its root.
+func (e Extension) cacheable(op *ast.OperationDefinition) bool {
+	if op == nil || op.Operation != ast.Query || len(op.SelectionSet) == 0 {
+		return false
+	}
+	for _, selection := range op.SelectionSet {
+		field, ok := selection.(*ast.Field)
+		if !ok || !e.contains(field.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e Extension) contains(name string) bool {
+	if name == "__typename" {
+		return true
+	}
+	for _, field := range e.Fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+package respcache_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/shota-tech/graphql/server/graph"
+	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/shota-tech/graphql/server/middleware/respcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtension(t *testing.T) {
+	const (
+		fetchTasks = `{ fetchTasks { id } }`
+		fetchUser  = `{ fetchUser { id } }`
+	)
+	type request struct {
+		query      string
+		userID     string
+		invalidate string
+	}
+	tests := map[string]struct {
+		requests  []request
+		failing   bool
+		wantCalls int
+		wantBody  string
+	}{
+		"cached": {
+			requests: []request{
+				{query: fetchTasks, userID: "user1"},
+				{query: fetchTasks, userID: "user1"},
+			},
+			wantCalls: 1,
+			wantBody:  `{"data":{"call":1}}`,
+		},
+		"users are cached separately": {
+			requests: []request{
+				{query: fetchTasks, userID: "user1"},
+				{query: fetchTasks, userID: "user2"},
+			},
+			wantCalls: 2,
+			wantBody:  `{"data":{"call":2}}`,
+		},
+		"operations are cached separately": {
+			requests: []request{
+				{query: fetchTasks, userID: "user1"},
+				{query: `{ fetchTasks { id text } }`, userID: "user1"},
+			},
+			wantCalls: 2,
+			wantBody:  `{"data":{"call":2}}`,
+		},
+		"invalidated": {
+			requests: []request{
+				{query: fetchTasks, userID: "user1"},
+				{query: fetchTasks, userID: "user1", invalidate: "user1"},
+			},
+			wantCalls: 2,
+			wantBody:  `{"data":{"call":2}}`,
+		},
+		"invalidation of another user": {
+			requests: []request{
+				{query: fetchTasks, userID: "user1"},
+				{query: fetchTasks, userID: "user1", invalidate: "user2"},
+			},
+			wantCalls: 1,
+			wantBody:  `{"data":{"call":1}}`,
+		},
+		"other fields are not cached": {
+			requests: []request{
+				{query: `{ fetchTasks { id } fetchUser { id } }`, userID: "user1"},
+				{query: `{ fetchTasks { id } fetchUser { id } }`, userID: "user1"},
+			},
+			wantCalls: 2,
+			wantBody:  `{"data":{"call":2}}`,
+		},
+		"only fields at the root are checked": {
+			requests: []request{
+				{query: `{ __typename fetchTasks { user { id } } }`, userID: "user1"},
+				{query: `{ __typename fetchTasks { user { id } } }`, userID: "user1"},
+			},
+			wantCalls: 1,
+			wantBody:  `{"data":{"call":1}}`,
+		},
+		"anonymous callers are not cached": {
+			requests: []request{
+				{query: fetchTasks},
+				{query: fetchTasks},
+			},
+			wantCalls: 2,
+			wantBody:  `{"data":{"call":2}}`,
+		},
+		"errors are not cached": {
+			requests: []request{
+				{query: fetchTasks, userID: "user1"},
+				{query: fetchTasks, userID: "user1"},
+			},
+			failing:   true,
+			wantCalls: 2,
+			wantBody:  `{"errors":[{"message":"call 2 failed"}],"data":{"call":2}}`,
+		},
+		"not cached field": {
+			requests: []request{
+				{query: fetchUser, userID: "user1"},
+				{query: fetchUser, userID: "user1"},
+			},
+			wantCalls: 2,
+			wantBody:  `{"data":{"call":2}}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := respcache.New(10, 0)
+			srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+			srv.AddTransport(transport.POST{})
+			srv.Use(respcache.Extension{Cache: c, Fields: []string{"fetchTasks"}})
+			// count the executed operations, the resolvers have no repositories.
+			var calls int
+			srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+				calls++
+				resp := &graphql.Response{Data: json.RawMessage(fmt.Sprintf(`{"call":%d}`, calls))}
+				if tt.failing {
+					resp.Errors = graphql.ErrorResponse(ctx, "call %d failed", calls).Errors
+				}
+				return graphql.OneShot(resp)
+			})
+			// test
+			var rec *httptest.ResponseRecorder
+			for _, r := range tt.requests {
+				if r.invalidate != "" {
+					c.Invalidate(r.invalidate)
+				}
+				body, err := json.Marshal(map[string]string{"query": r.query})
+				require.NoError(t, err)
+				req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+				req.Header.Set("Content-Type", "application/json")
+				if r.userID != "" {
+					req = req.WithContext(auth.ContextWithPrincipal(req.Context(), auth.Principal{UserID: r.userID}))
+				}
+				rec = httptest.NewRecorder()
+				srv.ServeHTTP(rec, req)
+			}
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
+
+func TestConditionalGET(t *testing.T) {
+	const etag = `"7fb9d166d1a15bce0b9f085f3818946f"`
+	tests := map[string]struct {
+		sharedMaxAge    time.Duration
+		method          string
+		ifNoneMatch     string
+		status          int
+		body            string
+		wantStatus      int
+		wantETag        string
+		wantCacheHeader string
+		wantBody        string
+	}{
+		"happy path": {
+			method:          http.MethodGet,
+			status:          http.StatusOK,
+			wantStatus:      http.StatusOK,
+			wantETag:        etag,
+			wantCacheHeader: "private, no-cache",
+			wantBody:        `{"data":{}}`,
+		},
+		"not modified": {
+			method:          http.MethodGet,
+			ifNoneMatch:     `"other", ` + etag,
+			status:          http.StatusOK,
+			wantStatus:      http.StatusNotModified,
+			wantETag:        etag,
+			wantCacheHeader: "private, no-cache",
+			wantBody:        ``,
+		},
+		"weak validator": {
+			method:          http.MethodGet,
+			ifNoneMatch:     `W/` + etag,
+			status:          http.StatusOK,
+			wantStatus:      http.StatusNotModified,
+			wantETag:        etag,
+			wantCacheHeader: "private, no-cache",
+			wantBody:        ``,
+		},
+		"modified": {
+			method:          http.MethodGet,
+			ifNoneMatch:     `"other"`,
+			status:          http.StatusOK,
+			wantStatus:      http.StatusOK,
+			wantETag:        etag,
+			wantCacheHeader: "private, no-cache",
+			wantBody:        `{"data":{}}`,
+		},
+		"shared": {
+			sharedMaxAge:    time.Minute,
+			method:          http.MethodGet,
+			status:          http.StatusOK,
+			wantStatus:      http.StatusOK,
+			wantETag:        etag,
+			wantCacheHeader: "public, max-age=0, s-maxage=60",
+			wantBody:        `{"data":{}}`,
+		},
+		"failed": {
+			sharedMaxAge:    time.Minute,
+			method:          http.MethodGet,
+			ifNoneMatch:     etag,
+			status:          http.StatusTooManyRequests,
+			wantStatus:      http.StatusTooManyRequests,
+			wantCacheHeader: "no-store",
+			wantBody:        `{"data":{}}`,
+		},
+		"persisted query not found": {
+			sharedMaxAge:    time.Minute,
+			method:          http.MethodGet,
+			status:          http.StatusOK,
+			body:            `{"errors":[{"message":"PersistedQueryNotFound","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}],"data":null}`,
+			wantStatus:      http.StatusOK,
+			wantCacheHeader: "no-store",
+			wantBody:        `{"errors":[{"message":"PersistedQueryNotFound","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}],"data":null}`,
+		},
+		"post": {
+			method:      http.MethodPost,
+			ifNoneMatch: etag,
+			status:      http.StatusOK,
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"data":{}}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			body := tt.body
+			if body == "" {
+				body = `{"data":{}}`
+			}
+			sut := respcache.ConditionalGET(tt.sharedMaxAge)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(body))
+			}))
+			// test
+			req := httptest.NewRequest(tt.method, "/graphql", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			sut.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantETag, rec.Header().Get("ETag"))
+			assert.Equal(t, tt.wantCacheHeader, rec.Header().Get("Cache-Control"))
+			assert.Equal(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
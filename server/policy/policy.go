@@ -0,0 +1,69 @@
+package policy
+
+import (
+	"errors"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+)
+
+type (
+	// Resource is what an action is performed on.
+	Resource string
+
+	Action string
+
+	// Rules lists the actions each role is allowed to perform per resource.
+	// Anything not listed is denied.
+	Rules map[model.Role]map[Resource][]Action
+
+	Engine struct {
+		rules Rules
+	}
+)
+
+const (
+	// ResourceTask covers tasks, their todos, activity and stats.
+	ResourceTask Resource = "task"
+	// ResourceProfile is the user's own profile and access tokens.
+	ResourceProfile Resource = "profile"
+	// ResourceUsers is every user of the service.
+	ResourceUsers Resource = "users"
+)
+
+const (
+	ActionRead  Action = "read"
+	ActionWrite Action = "write"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// DefaultRules lets admins manage users, members work on their board and viewers only read it.
+var DefaultRules = Rules{
+	model.RoleAdmin: {
+		ResourceTask:    {ActionRead, ActionWrite},
+		ResourceProfile: {ActionRead, ActionWrite},
+		ResourceUsers:   {ActionRead, ActionWrite},
+	},
+	model.RoleMember: {
+		ResourceTask:    {ActionRead, ActionWrite},
+		ResourceProfile: {ActionRead, ActionWrite},
+	},
+	model.RoleViewer: {
+		ResourceTask:    {ActionRead},
+		ResourceProfile: {ActionRead},
+	},
+}
+
+func NewEngine(rules Rules) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Authorize returns ErrForbidden unless the role is allowed to perform the action on the resource.
+func (e *Engine) Authorize(role model.Role, resource Resource, action Action) error {
+	for _, allowed := range e.rules[role][resource] {
+		if allowed == action {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
@@ -0,0 +1,75 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngine_Authorize(t *testing.T) {
+	type permission struct {
+		resource policy.Resource
+		action   policy.Action
+	}
+	permissions := []permission{
+		{policy.ResourceTask, policy.ActionRead},
+		{policy.ResourceTask, policy.ActionWrite},
+		{policy.ResourceProfile, policy.ActionRead},
+		{policy.ResourceProfile, policy.ActionWrite},
+		{policy.ResourceUsers, policy.ActionRead},
+		{policy.ResourceUsers, policy.ActionWrite},
+	}
+	tests := map[string]struct {
+		role    model.Role
+		allowed []permission
+	}{
+		"admin": {
+			role:    model.RoleAdmin,
+			allowed: permissions,
+		},
+		"member": {
+			role: model.RoleMember,
+			allowed: []permission{
+				{policy.ResourceTask, policy.ActionRead},
+				{policy.ResourceTask, policy.ActionWrite},
+				{policy.ResourceProfile, policy.ActionRead},
+				{policy.ResourceProfile, policy.ActionWrite},
+			},
+		},
+		"viewer": {
+			role: model.RoleViewer,
+			allowed: []permission{
+				{policy.ResourceTask, policy.ActionRead},
+				{policy.ResourceProfile, policy.ActionRead},
+			},
+		},
+		"unknown role": {
+			role:    model.Role("OWNER"),
+			allowed: nil,
+		},
+	}
+	sut := policy.NewEngine(policy.DefaultRules)
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, p := range permissions {
+				err := sut.Authorize(tt.role, p.resource, p.action)
+				if contains(tt.allowed, p) {
+					assert.NoError(t, err, "%s %s", p.action, p.resource)
+				} else {
+					assert.ErrorIs(t, err, policy.ErrForbidden, "%s %s", p.action, p.resource)
+				}
+			}
+		})
+	}
+}
+
+func contains[T comparable](items []T, item T) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shota-tech/graphql/server/database"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository/models"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type (
+	IAccessTokenRepository interface {
+		Store(context.Context, *model.AccessToken) error
+		Get(context.Context, string) (*model.AccessToken, error)
+		GetByTokenHash(context.Context, string) (*model.AccessToken, error)
+		ListByUserID(context.Context, string) ([]*model.AccessToken, error)
+	}
+
+	AccessTokenRepository struct {
+		db      *database.DB
+		dialect *Dialect
+	}
+)
+
+func NewAccessTokenRepository(db *database.DB, dialect *Dialect) *AccessTokenRepository {
+	return &AccessTokenRepository{db: db, dialect: dialect}
+}
+
+func (r *AccessTokenRepository) Store(ctx context.Context, token *model.AccessToken) error {
+	if token == nil {
+		return errors.New("access token is required")
+	}
+	row := models.PersonalAccessToken{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		Name:      token.Name,
+		TokenHash: token.TokenHash,
+		Scopes:    strings.Join(token.Scopes, " "),
+		ExpiresAt: null.TimeFromPtr(token.ExpiresAt),
+		RevokedAt: null.TimeFromPtr(token.RevokedAt),
+		CreatedAt: token.CreatedAt,
+	}
+	if err := r.dialect.upsert(ctx, r.db.Writer(ctx), models.TableNames.PersonalAccessTokens, &row); err != nil {
+		return fmt.Errorf("failed to upsert record: %w", err)
+	}
+	token.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *AccessTokenRepository) Get(ctx context.Context, id string) (*model.AccessToken, error) {
+	return r.get(ctx, r.db.Reader(ctx), r.dialect.where(models.PersonalAccessTokenTableColumns.ID, "=", id))
+}
+
+// GetByTokenHash returns the access token whose hash matches, revoked and expired ones included.
+// It reads from the primary so that a revoked token is rejected right away.
+func (r *AccessTokenRepository) GetByTokenHash(ctx context.Context, hash string) (*model.AccessToken, error) {
+	return r.get(ctx, r.db.Primary(), r.dialect.where(models.PersonalAccessTokenTableColumns.TokenHash, "=", hash))
+}
+
+func (r *AccessTokenRepository) get(ctx context.Context, exec boil.ContextExecutor, mods ...qm.QueryMod) (*model.AccessToken, error) {
+	var row models.PersonalAccessToken
+	err := r.dialect.query(models.TableNames.PersonalAccessTokens, append(mods, qm.Limit(1))...).Bind(ctx, exec, &row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get record: %w", err)
+	}
+	return toAccessToken(&row), nil
+}
+
+func (r *AccessTokenRepository) ListByUserID(ctx context.Context, userID string) ([]*model.AccessToken, error) {
+	var rows models.PersonalAccessTokenSlice
+	err := r.dialect.query(models.TableNames.PersonalAccessTokens,
+		r.dialect.where(models.PersonalAccessTokenTableColumns.UserID, "=", userID),
+		qm.OrderBy(models.PersonalAccessTokenTableColumns.ID),
+	).Bind(ctx, r.db.Reader(ctx), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get records: %w", err)
+	}
+	tokens := make([]*model.AccessToken, len(rows))
+	for i, row := range rows {
+		tokens[i] = toAccessToken(row)
+	}
+	return tokens, nil
+}
+
+func toAccessToken(row *models.PersonalAccessToken) *model.AccessToken {
+	return &model.AccessToken{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Name:      row.Name,
+		TokenHash: row.TokenHash,
+		Scopes:    strings.Fields(row.Scopes),
+		ExpiresAt: row.ExpiresAt.Ptr(),
+		RevokedAt: row.RevokedAt.Ptr(),
+		CreatedAt: row.CreatedAt,
+	}
+}
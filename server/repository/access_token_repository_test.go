@@ -0,0 +1,306 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shota-tech/graphql/server/database"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tokenHash = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+func TestAccessTokenRepository_Store(t *testing.T) {
+	expiresAt := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	query := map[*repository.Dialect]string{
+		repository.MySQL: "INSERT INTO `personal_access_tokens` (`id`,`user_id`,`name`,`token_hash`,`scopes`,`expires_at`,`revoked_at`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?) " +
+			"ON DUPLICATE KEY UPDATE `user_id` = VALUES(`user_id`),`name` = VALUES(`name`),`token_hash` = VALUES(`token_hash`),`scopes` = VALUES(`scopes`),`expires_at` = VALUES(`expires_at`),`revoked_at` = VALUES(`revoked_at`),`updated_at` = VALUES(`updated_at`)",
+		repository.Postgres: `INSERT INTO "personal_access_tokens" ("id","user_id","name","token_hash","scopes","expires_at","revoked_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) ` +
+			`ON CONFLICT ("id") DO UPDATE SET "user_id" = EXCLUDED."user_id","name" = EXCLUDED."name","token_hash" = EXCLUDED."token_hash","scopes" = EXCLUDED."scopes","expires_at" = EXCLUDED."expires_at","revoked_at" = EXCLUDED."revoked_at","updated_at" = EXCLUDED."updated_at"`,
+	}
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		token     *model.AccessToken
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				args := []driver.Value{"cgj2k1tvqc7kfo1h3uq0", "auth0|123456", "ci", tokenHash, "read:tasks write:tasks", expiresAt, nil, sqlmock.AnyArg(), sqlmock.AnyArg()}
+				mock.ExpectExec(regexp.QuoteMeta(query[d])).
+					WithArgs(args...).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			token: &model.AccessToken{
+				ID:        "cgj2k1tvqc7kfo1h3uq0",
+				UserID:    "auth0|123456",
+				Name:      "ci",
+				TokenHash: tokenHash,
+				Scopes:    []string{"read:tasks", "write:tasks"},
+				ExpiresAt: &expiresAt,
+			},
+			assertErr: assert.NoError,
+		},
+		"access token is nil": {
+			setup:     nil,
+			token:     nil,
+			assertErr: assert.Error,
+		},
+		"failed to upsert record": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				args := []driver.Value{"cgj2k1tvqc7kfo1h3uq0", "auth0|123456", "ci", tokenHash, "read:tasks write:tasks", expiresAt, nil, sqlmock.AnyArg(), sqlmock.AnyArg()}
+				mock.ExpectExec(regexp.QuoteMeta(query[d])).
+					WithArgs(args...).
+					WillReturnError(assert.AnError)
+			},
+			token: &model.AccessToken{
+				ID:        "cgj2k1tvqc7kfo1h3uq0",
+				UserID:    "auth0|123456",
+				Name:      "ci",
+				TokenHash: tokenHash,
+				Scopes:    []string{"read:tasks", "write:tasks"},
+				ExpiresAt: &expiresAt,
+			},
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewAccessTokenRepository(database.New(db), d.dialect)
+				err = sut.Store(context.Background(), tt.token)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestAccessTokenRepository_Get(t *testing.T) {
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		id        string
+		want      *model.AccessToken
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `personal_access_tokens`.* FROM `personal_access_tokens` WHERE (`personal_access_tokens`.`id` = ?) LIMIT 1;"
+				args := []driver.Value{"cgj2k1tvqc7kfo1h3uq0"}
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "token_hash", "scopes", "expires_at", "revoked_at", "created_at"}).
+					AddRow("cgj2k1tvqc7kfo1h3uq0", "auth0|123456", "ci", tokenHash, "read:tasks write:tasks", nil, nil, createdAt)
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			id: "cgj2k1tvqc7kfo1h3uq0",
+			want: &model.AccessToken{
+				ID:        "cgj2k1tvqc7kfo1h3uq0",
+				UserID:    "auth0|123456",
+				Name:      "ci",
+				TokenHash: tokenHash,
+				Scopes:    []string{"read:tasks", "write:tasks"},
+				CreatedAt: createdAt,
+			},
+			assertErr: assert.NoError,
+		},
+		"record not found": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `personal_access_tokens`.* FROM `personal_access_tokens` WHERE (`personal_access_tokens`.`id` = ?) LIMIT 1;"
+				args := []driver.Value{"cgj2k1tvqc7kfo1h3uq0"}
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnError(sql.ErrNoRows)
+			},
+			id:        "cgj2k1tvqc7kfo1h3uq0",
+			want:      nil,
+			assertErr: assert.Error,
+		},
+		"failed to get record": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `personal_access_tokens`.* FROM `personal_access_tokens` WHERE (`personal_access_tokens`.`id` = ?) LIMIT 1;"
+				args := []driver.Value{"cgj2k1tvqc7kfo1h3uq0"}
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnError(assert.AnError)
+			},
+			id:        "cgj2k1tvqc7kfo1h3uq0",
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewAccessTokenRepository(database.New(db), d.dialect)
+				got, err := sut.Get(context.Background(), tt.id)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestAccessTokenRepository_GetByTokenHash(t *testing.T) {
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	revokedAt := time.Date(2023, 3, 21, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		hash      string
+		want      *model.AccessToken
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `personal_access_tokens`.* FROM `personal_access_tokens` WHERE (`personal_access_tokens`.`token_hash` = ?) LIMIT 1;"
+				args := []driver.Value{tokenHash}
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "token_hash", "scopes", "expires_at", "revoked_at", "created_at"}).
+					AddRow("cgj2k1tvqc7kfo1h3uq0", "auth0|123456", "ci", tokenHash, "read:tasks", nil, revokedAt, createdAt)
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			hash: tokenHash,
+			want: &model.AccessToken{
+				ID:        "cgj2k1tvqc7kfo1h3uq0",
+				UserID:    "auth0|123456",
+				Name:      "ci",
+				TokenHash: tokenHash,
+				Scopes:    []string{"read:tasks"},
+				RevokedAt: &revokedAt,
+				CreatedAt: createdAt,
+			},
+			assertErr: assert.NoError,
+		},
+		"record not found": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `personal_access_tokens`.* FROM `personal_access_tokens` WHERE (`personal_access_tokens`.`token_hash` = ?) LIMIT 1;"
+				args := []driver.Value{tokenHash}
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnError(sql.ErrNoRows)
+			},
+			hash:      tokenHash,
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewAccessTokenRepository(database.New(db), d.dialect)
+				got, err := sut.GetByTokenHash(context.Background(), tt.hash)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestAccessTokenRepository_ListByUserID(t *testing.T) {
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		userID    string
+		want      []*model.AccessToken
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `personal_access_tokens`.* FROM `personal_access_tokens` WHERE (`personal_access_tokens`.`user_id` = ?) ORDER BY personal_access_tokens.id;"
+				args := []driver.Value{"auth0|123456"}
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "token_hash", "scopes", "expires_at", "revoked_at", "created_at"}).
+					AddRow("cgj2k1tvqc7kfo1h3uq0", "auth0|123456", "ci", tokenHash, "read:tasks", expiresAt, nil, createdAt).
+					AddRow("cgj2k2tvqc7kfo1h3uqg", "auth0|123456", "script", "hash2", "", nil, nil, createdAt)
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			userID: "auth0|123456",
+			want: []*model.AccessToken{
+				{ID: "cgj2k1tvqc7kfo1h3uq0", UserID: "auth0|123456", Name: "ci", TokenHash: tokenHash, Scopes: []string{"read:tasks"}, ExpiresAt: &expiresAt, CreatedAt: createdAt},
+				{ID: "cgj2k2tvqc7kfo1h3uqg", UserID: "auth0|123456", Name: "script", TokenHash: "hash2", Scopes: []string{}, CreatedAt: createdAt},
+			},
+			assertErr: assert.NoError,
+		},
+		"0 records": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `personal_access_tokens`.* FROM `personal_access_tokens` WHERE (`personal_access_tokens`.`user_id` = ?) ORDER BY personal_access_tokens.id;"
+				args := []driver.Value{"auth0|123456"}
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "token_hash", "scopes", "expires_at", "revoked_at", "created_at"})
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			userID:    "auth0|123456",
+			want:      []*model.AccessToken{},
+			assertErr: assert.NoError,
+		},
+		"failed to get records": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `personal_access_tokens`.* FROM `personal_access_tokens` WHERE (`personal_access_tokens`.`user_id` = ?) ORDER BY personal_access_tokens.id;"
+				args := []driver.Value{"auth0|123456"}
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnError(assert.AnError)
+			},
+			userID:    "auth0|123456",
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewAccessTokenRepository(database.New(db), d.dialect)
+				got, err := sut.ListByUserID(context.Background(), tt.userID)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
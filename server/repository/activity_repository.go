@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/shota-tech/graphql/server/database"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository/models"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type (
+	IActivityRepository interface {
+		Store(context.Context, *model.Activity) error
+		Get(context.Context, string) (*model.Activity, error)
+		GetLatestByEntity(context.Context, model.EntityType, string) (*model.Activity, error)
+		ListByTaskID(ctx context.Context, taskID string, limit int, after string) ([]*model.Activity, error)
+		ListByTaskUserID(ctx context.Context, userID string, limit int, after string) ([]*model.Activity, error)
+	}
+
+	ActivityRepository struct {
+		db      *database.DB
+		dialect *Dialect
+	}
+)
+
+func NewActivityRepository(db *database.DB, dialect *Dialect) *ActivityRepository {
+	return &ActivityRepository{db: db, dialect: dialect}
+}
+
+// Store appends the activity. Activities are never updated once recorded.
+func (r *ActivityRepository) Store(ctx context.Context, activity *model.Activity) error {
+	if activity == nil {
+		return errors.New("activity is required")
+	}
+	changes, err := json.Marshal(activity.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal changes: %w", err)
+	}
+	row := models.Activity{
+		ID:         activity.ID,
+		UserID:     activity.UserID,
+		EntityType: activity.EntityType.String(),
+		EntityID:   activity.EntityID,
+		TaskID:     null.StringFromPtr(activity.TaskID),
+		Action:     activity.Action.String(),
+		Changes:    changes,
+		CreatedAt:  activity.CreatedAt,
+	}
+	if err := r.dialect.insert(ctx, r.db.Writer(ctx), models.TableNames.Activities, &row); err != nil {
+		return fmt.Errorf("failed to insert record: %w", err)
+	}
+	activity.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *ActivityRepository) Get(ctx context.Context, id string) (*model.Activity, error) {
+	var row models.Activity
+	err := r.dialect.query(models.TableNames.Activities,
+		r.dialect.where(models.ActivityTableColumns.ID, "=", id),
+		qm.Limit(1),
+	).Bind(ctx, r.db.Reader(ctx), &row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get record: %w", err)
+	}
+	activities, err := toActivities(models.ActivitySlice{&row})
+	if err != nil {
+		return nil, err
+	}
+	return activities[0], nil
+}
+
+// GetLatestByEntity returns the most recent activity recorded for the entity.
+func (r *ActivityRepository) GetLatestByEntity(ctx context.Context, entityType model.EntityType, entityID string) (*model.Activity, error) {
+	var row models.Activity
+	err := r.dialect.query(models.TableNames.Activities,
+		r.dialect.where(models.ActivityTableColumns.EntityType, "=", entityType.String()),
+		r.dialect.where(models.ActivityTableColumns.EntityID, "=", entityID),
+		qm.OrderBy(models.ActivityTableColumns.ID+" DESC"),
+		qm.Limit(1),
+	).Bind(ctx, r.db.Reader(ctx), &row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get record: %w", err)
+	}
+	activities, err := toActivities(models.ActivitySlice{&row})
+	if err != nil {
+		return nil, err
+	}
+	return activities[0], nil
+}
+
+// ListByTaskID returns the activities of the task and its todos, newest first.
+// When after is given, only activities older than it are returned.
+func (r *ActivityRepository) ListByTaskID(ctx context.Context, taskID string, limit int, after string) ([]*model.Activity, error) {
+	mods := []qm.QueryMod{
+		r.dialect.where(models.ActivityTableColumns.TaskID, "=", taskID),
+	}
+	if after != "" {
+		mods = append(mods, r.dialect.where(models.ActivityTableColumns.ID, "<", after))
+	}
+	mods = append(mods,
+		qm.OrderBy(models.ActivityTableColumns.ID+" DESC"),
+		qm.Limit(limit),
+	)
+	var rows models.ActivitySlice
+	err := r.dialect.query(models.TableNames.Activities, mods...).Bind(ctx, r.db.Reader(ctx), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get records: %w", err)
+	}
+	return toActivities(rows)
+}
+
+// ListByTaskUserID returns the activities of all tasks owned by the user, newest first.
+// When after is given, only activities older than it are returned.
+func (r *ActivityRepository) ListByTaskUserID(ctx context.Context, userID string, limit int, after string) ([]*model.Activity, error) {
+	mods := []qm.QueryMod{
+		qm.InnerJoin(models.TableNames.Tasks + " ON " + models.TaskTableColumns.ID + " = " + models.ActivityTableColumns.TaskID),
+		r.dialect.where(models.TaskTableColumns.UserID, "=", userID),
+	}
+	if after != "" {
+		mods = append(mods, r.dialect.where(models.ActivityTableColumns.ID, "<", after))
+	}
+	mods = append(mods,
+		qm.OrderBy(models.ActivityTableColumns.ID+" DESC"),
+		qm.Limit(limit),
+	)
+	var rows models.ActivitySlice
+	err := r.dialect.query(models.TableNames.Activities, mods...).Bind(ctx, r.db.Reader(ctx), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get records: %w", err)
+	}
+	return toActivities(rows)
+}
+
+func toActivities(rows models.ActivitySlice) ([]*model.Activity, error) {
+	activities := make([]*model.Activity, len(rows))
+	for i, row := range rows {
+		var changes []*model.FieldChange
+		if err := json.Unmarshal(row.Changes, &changes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal changes: %w", err)
+		}
+		activities[i] = &model.Activity{
+			ID:         row.ID,
+			UserID:     row.UserID,
+			EntityType: model.EntityType(row.EntityType),
+			EntityID:   row.EntityID,
+			TaskID:     row.TaskID.Ptr(),
+			Action:     model.ActivityAction(row.Action),
+			Changes:    changes,
+			CreatedAt:  row.CreatedAt,
+		}
+	}
+	return activities, nil
+}
@@ -0,0 +1,446 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shota-tech/graphql/server/database"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityRepository_Store(t *testing.T) {
+	taskID := "cg1m0bd1nm6u7kpjp15g"
+	after := "DONE"
+	before := "IN_PROGRESS"
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		activity  *model.Activity
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "INSERT INTO `activities` (`id`,`user_id`,`entity_type`,`entity_id`,`task_id`,`action`,`changes`,`created_at`) VALUES (?,?,?,?,?,?,?,?)"
+				args := []driver.Value{"cgh1q5dvqc7j7g5i0qsg", "auth0|123456", "TASK", taskID, taskID, "UPDATE",
+					[]byte(`[{"field":"status","before":"IN_PROGRESS","after":"DONE"}]`), sqlmock.AnyArg()}
+				mock.ExpectExec(expect(d, query)).
+					WithArgs(args...).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			activity: &model.Activity{
+				ID:         "cgh1q5dvqc7j7g5i0qsg",
+				UserID:     "auth0|123456",
+				EntityType: model.EntityTypeTask,
+				EntityID:   taskID,
+				TaskID:     &taskID,
+				Action:     model.ActivityActionUpdate,
+				Changes:    []*model.FieldChange{{Field: "status", Before: &before, After: &after}},
+			},
+			assertErr: assert.NoError,
+		},
+		"activity is nil": {
+			setup:     nil,
+			activity:  nil,
+			assertErr: assert.Error,
+		},
+		"failed to insert record": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "INSERT INTO `activities` (`id`,`user_id`,`entity_type`,`entity_id`,`task_id`,`action`,`changes`,`created_at`) VALUES (?,?,?,?,?,?,?,?)"
+				args := []driver.Value{"cgh1q5dvqc7j7g5i0qsg", "auth0|123456", "TASK", taskID, taskID, "UPDATE",
+					[]byte(`[{"field":"status","before":"IN_PROGRESS","after":"DONE"}]`), sqlmock.AnyArg()}
+				mock.ExpectExec(expect(d, query)).
+					WithArgs(args...).
+					WillReturnError(assert.AnError)
+			},
+			activity: &model.Activity{
+				ID:         "cgh1q5dvqc7j7g5i0qsg",
+				UserID:     "auth0|123456",
+				EntityType: model.EntityTypeTask,
+				EntityID:   taskID,
+				TaskID:     &taskID,
+				Action:     model.ActivityActionUpdate,
+				Changes:    []*model.FieldChange{{Field: "status", Before: &before, After: &after}},
+			},
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewActivityRepository(database.New(db), d.dialect)
+				err = sut.Store(context.Background(), tt.activity)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestActivityRepository_Get(t *testing.T) {
+	taskID := "cg1m0bd1nm6u7kpjp15g"
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	before := "TODO"
+	after := "IN_PROGRESS"
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		id        string
+		want      *model.Activity
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` WHERE (`activities`.`id` = ?) LIMIT 1;"
+				row := sqlmock.NewRows([]string{"id", "user_id", "entity_type", "entity_id", "task_id", "action", "changes", "created_at"}).
+					AddRow("cgh1q5dvqc7j7g5i0qsg", "auth0|123456", "TASK", taskID, taskID, "UPDATE", []byte(`[{"field":"status","before":"TODO","after":"IN_PROGRESS"}]`), createdAt)
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("cgh1q5dvqc7j7g5i0qsg").
+					WillReturnRows(row)
+			},
+			id: "cgh1q5dvqc7j7g5i0qsg",
+			want: &model.Activity{
+				ID:         "cgh1q5dvqc7j7g5i0qsg",
+				UserID:     "auth0|123456",
+				EntityType: model.EntityTypeTask,
+				EntityID:   taskID,
+				TaskID:     &taskID,
+				Action:     model.ActivityActionUpdate,
+				Changes:    []*model.FieldChange{{Field: "status", Before: &before, After: &after}},
+				CreatedAt:  createdAt,
+			},
+			assertErr: assert.NoError,
+		},
+		"record not found": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` WHERE (`activities`.`id` = ?) LIMIT 1;"
+				row := sqlmock.NewRows([]string{"id", "user_id", "entity_type", "entity_id", "task_id", "action", "changes", "created_at"})
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("cgh1q5dvqc7j7g5i0qsg").
+					WillReturnRows(row)
+			},
+			id:        "cgh1q5dvqc7j7g5i0qsg",
+			want:      nil,
+			assertErr: assert.Error,
+		},
+		"failed to get record": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` WHERE (`activities`.`id` = ?) LIMIT 1;"
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("cgh1q5dvqc7j7g5i0qsg").
+					WillReturnError(assert.AnError)
+			},
+			id:        "cgh1q5dvqc7j7g5i0qsg",
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewActivityRepository(database.New(db), d.dialect)
+				got, err := sut.Get(context.Background(), tt.id)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestActivityRepository_GetLatestByEntity(t *testing.T) {
+	taskID := "cg1m0bd1nm6u7kpjp15g"
+	todoID := "cgf90odvqc7hkkh47tg0"
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	before := "false"
+	after := "true"
+	tests := map[string]struct {
+		setup      func(sqlmock.Sqlmock, *repository.Dialect)
+		entityType model.EntityType
+		entityID   string
+		want       *model.Activity
+		assertErr  assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` WHERE (`activities`.`entity_type` = ?) AND (`activities`.`entity_id` = ?) ORDER BY activities.id DESC LIMIT 1;"
+				args := []driver.Value{"TODO", todoID}
+				row := sqlmock.NewRows([]string{"id", "user_id", "entity_type", "entity_id", "task_id", "action", "changes", "created_at"}).
+					AddRow("cgh1q5dvqc7j7g5i0qsg", "auth0|123456", "TODO", todoID, taskID, "UPDATE", []byte(`[{"field":"done","before":"false","after":"true"}]`), createdAt)
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(row)
+			},
+			entityType: model.EntityTypeTodo,
+			entityID:   todoID,
+			want: &model.Activity{
+				ID:         "cgh1q5dvqc7j7g5i0qsg",
+				UserID:     "auth0|123456",
+				EntityType: model.EntityTypeTodo,
+				EntityID:   todoID,
+				TaskID:     &taskID,
+				Action:     model.ActivityActionUpdate,
+				Changes:    []*model.FieldChange{{Field: "done", Before: &before, After: &after}},
+				CreatedAt:  createdAt,
+			},
+			assertErr: assert.NoError,
+		},
+		"record not found": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` WHERE (`activities`.`entity_type` = ?) AND (`activities`.`entity_id` = ?) ORDER BY activities.id DESC LIMIT 1;"
+				args := []driver.Value{"TODO", todoID}
+				row := sqlmock.NewRows([]string{"id", "user_id", "entity_type", "entity_id", "task_id", "action", "changes", "created_at"})
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(row)
+			},
+			entityType: model.EntityTypeTodo,
+			entityID:   todoID,
+			want:       nil,
+			assertErr:  assert.Error,
+		},
+		"failed to get record": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` WHERE (`activities`.`entity_type` = ?) AND (`activities`.`entity_id` = ?) ORDER BY activities.id DESC LIMIT 1;"
+				args := []driver.Value{"TODO", todoID}
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnError(assert.AnError)
+			},
+			entityType: model.EntityTypeTodo,
+			entityID:   todoID,
+			want:       nil,
+			assertErr:  assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewActivityRepository(database.New(db), d.dialect)
+				got, err := sut.GetLatestByEntity(context.Background(), tt.entityType, tt.entityID)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestActivityRepository_ListByTaskID(t *testing.T) {
+	taskID := "cg1m0bd1nm6u7kpjp15g"
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	after := "task1"
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		taskID    string
+		limit     int
+		after     string
+		want      []*model.Activity
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` WHERE (`activities`.`task_id` = ?) ORDER BY activities.id DESC LIMIT 2;"
+				rows := sqlmock.NewRows([]string{"id", "user_id", "entity_type", "entity_id", "task_id", "action", "changes", "created_at"}).
+					AddRow("cgh1q5dvqc7j7g5i0qsg", "auth0|123456", "TASK", taskID, taskID, "CREATE", []byte(`[{"field":"text","before":null,"after":"task1"}]`), createdAt)
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(taskID).
+					WillReturnRows(rows)
+			},
+			taskID: taskID,
+			limit:  2,
+			want: []*model.Activity{
+				{
+					ID:         "cgh1q5dvqc7j7g5i0qsg",
+					UserID:     "auth0|123456",
+					EntityType: model.EntityTypeTask,
+					EntityID:   taskID,
+					TaskID:     &taskID,
+					Action:     model.ActivityActionCreate,
+					Changes:    []*model.FieldChange{{Field: "text", After: &after}},
+					CreatedAt:  createdAt,
+				},
+			},
+			assertErr: assert.NoError,
+		},
+		"with cursor": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` WHERE (`activities`.`task_id` = ?) AND (`activities`.`id` < ?) ORDER BY activities.id DESC LIMIT 2;"
+				args := []driver.Value{taskID, "cgh1q5dvqc7j7g5i0qsg"}
+				rows := sqlmock.NewRows([]string{"id", "user_id", "entity_type", "entity_id", "task_id", "action", "changes", "created_at"})
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			taskID:    taskID,
+			limit:     2,
+			after:     "cgh1q5dvqc7j7g5i0qsg",
+			want:      []*model.Activity{},
+			assertErr: assert.NoError,
+		},
+		"failed to get records": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` WHERE (`activities`.`task_id` = ?) ORDER BY activities.id DESC LIMIT 2;"
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(taskID).
+					WillReturnError(assert.AnError)
+			},
+			taskID:    taskID,
+			limit:     2,
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewActivityRepository(database.New(db), d.dialect)
+				got, err := sut.ListByTaskID(context.Background(), tt.taskID, tt.limit, tt.after)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestActivityRepository_ListByTaskUserID(t *testing.T) {
+	taskID := "cg1m0bd1nm6u7kpjp15g"
+	todoID := "cgf90odvqc7hkkh47tg0"
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	before := "false"
+	after := "true"
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		userID    string
+		limit     int
+		after     string
+		want      []*model.Activity
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` INNER JOIN tasks ON tasks.id = activities.task_id " +
+					"WHERE (`tasks`.`user_id` = ?) ORDER BY activities.id DESC LIMIT 21;"
+				rows := sqlmock.NewRows([]string{"id", "user_id", "entity_type", "entity_id", "task_id", "action", "changes", "created_at"}).
+					AddRow("cgh1q5dvqc7j7g5i0qsg", "auth0|567890", "TODO", todoID, taskID, "UPDATE", []byte(`[{"field":"done","before":"false","after":"true"}]`), createdAt)
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("auth0|123456").
+					WillReturnRows(rows)
+			},
+			userID: "auth0|123456",
+			limit:  21,
+			want: []*model.Activity{
+				{
+					ID:         "cgh1q5dvqc7j7g5i0qsg",
+					UserID:     "auth0|567890",
+					EntityType: model.EntityTypeTodo,
+					EntityID:   todoID,
+					TaskID:     &taskID,
+					Action:     model.ActivityActionUpdate,
+					Changes:    []*model.FieldChange{{Field: "done", Before: &before, After: &after}},
+					CreatedAt:  createdAt,
+				},
+			},
+			assertErr: assert.NoError,
+		},
+		"with cursor": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` INNER JOIN tasks ON tasks.id = activities.task_id " +
+					"WHERE (`tasks`.`user_id` = ?) AND (`activities`.`id` < ?) ORDER BY activities.id DESC LIMIT 21;"
+				args := []driver.Value{"auth0|123456", "cgh1q5dvqc7j7g5i0qsg"}
+				rows := sqlmock.NewRows([]string{"id", "user_id", "entity_type", "entity_id", "task_id", "action", "changes", "created_at"})
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			userID:    "auth0|123456",
+			limit:     21,
+			after:     "cgh1q5dvqc7j7g5i0qsg",
+			want:      []*model.Activity{},
+			assertErr: assert.NoError,
+		},
+		"failed to unmarshal changes": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` INNER JOIN tasks ON tasks.id = activities.task_id " +
+					"WHERE (`tasks`.`user_id` = ?) ORDER BY activities.id DESC LIMIT 21;"
+				rows := sqlmock.NewRows([]string{"id", "user_id", "entity_type", "entity_id", "task_id", "action", "changes", "created_at"}).
+					AddRow("cgh1q5dvqc7j7g5i0qsg", "auth0|567890", "TODO", todoID, taskID, "UPDATE", []byte(`{`), createdAt)
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("auth0|123456").
+					WillReturnRows(rows)
+			},
+			userID:    "auth0|123456",
+			limit:     21,
+			want:      nil,
+			assertErr: assert.Error,
+		},
+		"failed to get records": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `activities`.* FROM `activities` INNER JOIN tasks ON tasks.id = activities.task_id " +
+					"WHERE (`tasks`.`user_id` = ?) ORDER BY activities.id DESC LIMIT 21;"
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("auth0|123456").
+					WillReturnError(assert.AnError)
+			},
+			userID:    "auth0|123456",
+			limit:     21,
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewActivityRepository(database.New(db), d.dialect)
+				got, err := sut.ListByTaskUserID(context.Background(), tt.userID, tt.limit, tt.after)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+)
+
+// columnsOf returns the columns of the model in the order of its fields along with their values.
+// CreatedAt is set when zero and UpdatedAt to the current time, as sqlboiler does.
+func columnsOf(row interface{}) ([]string, []interface{}) {
+	v := reflect.ValueOf(row).Elem()
+	now := time.Now()
+	if f := v.FieldByName("CreatedAt"); f.IsValid() {
+		setTime(f, now, false)
+	}
+	if f := v.FieldByName("UpdatedAt"); f.IsValid() {
+		setTime(f, now, true)
+	}
+	var columns []string
+	var values []interface{}
+	for i := 0; i < v.NumField(); i++ {
+		column, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("boil"), ",")
+		if column == "" || column == "-" {
+			continue
+		}
+		columns = append(columns, column)
+		values = append(values, v.Field(i).Interface())
+	}
+	return columns, values
+}
+
+// setTime sets the timestamp field to t, unless it is already set and overwrite is false.
+// Fields of other types than time.Time and null.Time are left as they are.
+func setTime(field reflect.Value, t time.Time, overwrite bool) {
+	switch f := field.Addr().Interface().(type) {
+	case *time.Time:
+		if overwrite || f.IsZero() {
+			*f = t
+		}
+	case *null.Time:
+		if overwrite || !f.Valid || f.Time.IsZero() {
+			*f = null.TimeFrom(t)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null/v8"
+)
+
+func TestColumnsOf(t *testing.T) {
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		row          interface{}
+		wantColumns  []string
+		assertValues func(t *testing.T, values []interface{})
+	}{
+		"time.Time": {
+			row: &struct {
+				ID        string    `boil:"id"`
+				CreatedAt time.Time `boil:"created_at"`
+				UpdatedAt time.Time `boil:"updated_at"`
+				R         *struct{} `boil:"-"`
+			}{ID: "id1", CreatedAt: createdAt},
+			wantColumns: []string{"id", "created_at", "updated_at"},
+			assertValues: func(t *testing.T, values []interface{}) {
+				assert.Equal(t, createdAt, values[1])
+				assert.False(t, values[2].(time.Time).IsZero())
+			},
+		},
+		"null.Time": {
+			row: &struct {
+				ID        string    `boil:"id,bind"`
+				CreatedAt null.Time `boil:"created_at"`
+				UpdatedAt null.Time `boil:"updated_at"`
+			}{ID: "id1"},
+			wantColumns: []string{"id", "created_at", "updated_at"},
+			assertValues: func(t *testing.T, values []interface{}) {
+				assert.True(t, values[1].(null.Time).Valid)
+				assert.True(t, values[2].(null.Time).Valid)
+			},
+		},
+		"other types are left as they are": {
+			row: &struct {
+				ID        string `boil:"id"`
+				CreatedAt string `boil:"created_at"`
+			}{ID: "id1", CreatedAt: "yesterday"},
+			wantColumns: []string{"id", "created_at"},
+			assertValues: func(t *testing.T, values []interface{}) {
+				assert.Equal(t, "yesterday", values[1])
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			columns, values := columnsOf(tt.row)
+			assert.Equal(t, tt.wantColumns, columns)
+			tt.assertValues(t, values)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	"github.com/rs/xid"
+	"github.com/shota-tech/graphql/server/database"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepositories_Database runs the repositories against real databases, which the tests with sqlmock can't tell
+// whether they accept the queries. The databases are given by TEST_MYSQL_DSN, which needs multiStatements=true and
+// parseTime=true, and TEST_POSTGRES_DSN, and are created from the scripts under db. Unset ones are skipped.
+func TestRepositories_Database(t *testing.T) {
+	databases := []struct {
+		name    string
+		driver  string
+		env     string
+		schema  string
+		dialect *repository.Dialect
+	}{
+		{name: "mysql", driver: "mysql", env: "TEST_MYSQL_DSN", schema: "../../db/init/initialize_tables.sql", dialect: repository.MySQL},
+		{name: "postgres", driver: "postgres", env: "TEST_POSTGRES_DSN", schema: "../../db/init-postgres/initialize_tables.sql", dialect: repository.Postgres},
+	}
+	for _, d := range databases {
+		t.Run(d.name, func(t *testing.T) {
+			dsn := os.Getenv(d.env)
+			if dsn == "" {
+				t.Skipf("%s is not set", d.env)
+			}
+			conn, err := sql.Open(d.driver, dsn)
+			require.NoError(t, err)
+			defer conn.Close()
+			schema, err := os.ReadFile(d.schema)
+			require.NoError(t, err)
+			_, err = conn.Exec(string(schema))
+			require.NoError(t, err)
+			testRepositories(t, database.New(conn), d.dialect)
+		})
+	}
+}
+
+// testRepositories writes and reads back the records of a new user through every repository.
+func testRepositories(t *testing.T, db *database.DB, dialect *repository.Dialect) {
+	ctx := context.Background()
+	users := repository.NewUserRepository(db, dialect)
+	tasks := repository.NewTaskRepository(db, dialect)
+	todos := repository.NewTodoRepository(db, dialect)
+	activities := repository.NewActivityRepository(db, dialect)
+	transitions := repository.NewStatusTransitionRepository(db, dialect)
+	tokens := repository.NewAccessTokenRepository(db, dialect)
+
+	// users
+	name := xid.New().String()
+	user := &model.User{ID: "auth0|" + name, Name: name, Role: model.RoleMember}
+	require.NoError(t, users.Store(ctx, user))
+	require.NoError(t, users.UpdateName(ctx, user.ID, "Renamed "+name))
+	require.NoError(t, users.UpdateRole(ctx, user.ID, model.RoleAdmin))
+	gotUser, err := users.Get(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &model.User{ID: user.ID, Name: "Renamed " + name, Role: model.RoleAdmin}, gotUser)
+	query := "renamed " + name
+	found, err := users.Search(ctx, &model.UserFilter{Role: ptr(model.RoleAdmin), Query: &query})
+	require.NoError(t, err)
+	assert.Equal(t, []*model.User{gotUser}, found)
+	_, err = users.Get(ctx, "auth0|"+xid.New().String())
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	// tasks, updated by storing them again
+	task := &model.Task{ID: xid.New().String(), Text: "task", Status: model.StatusTodo, UserID: user.ID}
+	require.NoError(t, tasks.Store(ctx, task))
+	created, err := tasks.Get(ctx, task.ID)
+	require.NoError(t, err)
+	task.Status = model.StatusDone
+	require.NoError(t, tasks.Store(ctx, task))
+	err = db.Transaction(ctx, func(ctx context.Context) error {
+		got, err := tasks.GetForUpdate(ctx, task.ID)
+		if err == nil {
+			assert.Equal(t, model.StatusDone, got.Status)
+			assert.Equal(t, created.CreatedAt, got.CreatedAt)
+		}
+		return err
+	})
+	require.NoError(t, err)
+	gotTasks, err := tasks.ListByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, gotTasks, 1)
+	assert.Equal(t, task.ID, gotTasks[0].ID)
+
+	// todos
+	todo1 := &model.Todo{ID: xid.New().String(), Text: "todo1", Done: true, TaskID: task.ID, Position: 1}
+	todo2 := &model.Todo{ID: xid.New().String(), Text: "todo2", TaskID: task.ID, Position: 0}
+	todo3 := &model.Todo{ID: xid.New().String(), Text: "todo3", TaskID: task.ID, ParentID: &todo1.ID, Position: 0}
+	for _, todo := range []*model.Todo{todo1, todo2, todo3} {
+		require.NoError(t, todos.Store(ctx, todo))
+	}
+	gotTodos, err := todos.ListByParentIDs(ctx, []string{todo1.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Todo{todo3}, gotTodos)
+	progress, err := todos.CountByTaskIDs(ctx, []string{task.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Progress{{TaskID: task.ID, Done: 1, Total: 3}}, progress)
+
+	// activities
+	activity := &model.Activity{
+		ID:         xid.New().String(),
+		UserID:     user.ID,
+		EntityType: model.EntityTypeTask,
+		EntityID:   task.ID,
+		TaskID:     &task.ID,
+		Action:     model.ActivityActionUpdate,
+		Changes:    []*model.FieldChange{{Field: "status", Before: ptr("TODO"), After: ptr("DONE")}},
+	}
+	require.NoError(t, activities.Store(ctx, activity))
+	latest, err := activities.GetLatestByEntity(ctx, model.EntityTypeTask, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, activity.Changes, latest.Changes)
+	gotActivities, err := activities.ListByTaskUserID(ctx, user.ID, 10, "")
+	require.NoError(t, err)
+	require.Len(t, gotActivities, 1)
+	assert.Equal(t, activity.ID, gotActivities[0].ID)
+
+	// status transitions
+	transition := &model.StatusTransition{ID: xid.New().String(), TaskID: task.ID, From: ptr(model.StatusTodo), To: model.StatusDone}
+	require.NoError(t, transitions.Store(ctx, transition))
+	gotTransitions, err := transitions.ListByTaskUserID(ctx, user.ID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, gotTransitions, 1)
+	assert.Equal(t, transition.From, gotTransitions[0].From)
+
+	// access tokens, revoked by storing them again
+	token := &model.AccessToken{ID: xid.New().String(), UserID: user.ID, Name: "ci", TokenHash: strings.Repeat("0", 44) + xid.New().String(), Scopes: []string{"read:tasks"}}
+	require.NoError(t, tokens.Store(ctx, token))
+	revokedAt := time.Now()
+	token.RevokedAt = &revokedAt
+	require.NoError(t, tokens.Store(ctx, token))
+	gotToken, err := tokens.GetByTokenHash(ctx, token.TokenHash)
+	require.NoError(t, err)
+	require.NotNil(t, gotToken.RevokedAt)
+	assert.Equal(t, token.Scopes, gotToken.Scopes)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
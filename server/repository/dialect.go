@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/drivers"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/strmangle"
+)
+
+// Dialect holds what differs between the SQL of the databases the repositories run on.
+// The repositories only use the generated models for their rows and column names,
+// the queries generated along with them are bound to the dialect they were generated for.
+// The models are therefore generated from MySQL only, the PostgreSQL schema having the same tables and columns.
+// TestRepositories_Database runs the repositories against both databases.
+type Dialect struct {
+	drivers.Dialect
+	// onConflict returns the clause updating the columns of a row whose primary key already exists.
+	onConflict func(d *Dialect, columns []string) string
+	// countTrue returns an expression counting the rows whose boolean column is true.
+	countTrue func(column string) string
+	// iLike is the operator matching a pattern case-insensitively.
+	iLike string
+}
+
+// primaryKey is the column every table is keyed by.
+const primaryKey = "id"
+
+var (
+	MySQL = &Dialect{
+		Dialect: drivers.Dialect{
+			LQ:              '`',
+			RQ:              '`',
+			UseLastInsertID: true,
+		},
+		onConflict: func(d *Dialect, columns []string) string {
+			sets := make([]string, len(columns))
+			for i, column := range columns {
+				sets[i] = fmt.Sprintf("%s = VALUES(%[1]s)", d.quote(column))
+			}
+			return " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ",")
+		},
+		// done is a TINYINT(1), which can be summed
+		countTrue: func(column string) string {
+			return "COALESCE(SUM(" + column + "), 0)"
+		},
+		// the default collation is case-insensitive
+		iLike: "LIKE",
+	}
+
+	Postgres = &Dialect{
+		Dialect: drivers.Dialect{
+			LQ:                   '"',
+			RQ:                   '"',
+			UseIndexPlaceholders: true,
+			UseDefaultKeyword:    true,
+		},
+		onConflict: func(d *Dialect, columns []string) string {
+			sets := make([]string, len(columns))
+			for i, column := range columns {
+				sets[i] = fmt.Sprintf("%s = EXCLUDED.%[1]s", d.quote(column))
+			}
+			return fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", d.quote(primaryKey), strings.Join(sets, ","))
+		},
+		countTrue: func(column string) string {
+			return "COUNT(CASE WHEN " + column + " THEN 1 END)"
+		},
+		iLike: "ILIKE",
+	}
+)
+
+func (d *Dialect) quote(identifier string) string {
+	return strmangle.IdentQuote(d.LQ, d.RQ, identifier)
+}
+
+// query selects the rows of the table, all of their columns unless mods select others.
+func (d *Dialect) query(table string, mods ...qm.QueryMod) *queries.Query {
+	q := &queries.Query{}
+	queries.SetDialect(q, &d.Dialect)
+	queries.SetFrom(q, d.quote(table))
+	qm.Apply(q, mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{d.quote(table) + ".*"})
+	}
+	return q
+}
+
+// where compares the column to the value with the operator such as "=" or "<".
+func (d *Dialect) where(column, operator string, value interface{}) qm.QueryMod {
+	return qm.Where(d.quote(column)+" "+operator+" ?", value)
+}
+
+func (d *Dialect) whereIn(column string, values []string) qm.QueryMod {
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+	return qm.WhereIn(d.quote(column)+" IN ?", args...)
+}
+
+// insert stores row, a pointer to a model, setting its timestamps as the generated Insert does.
+func (d *Dialect) insert(ctx context.Context, exec boil.ContextExecutor, table string, row interface{}) error {
+	columns, values := columnsOf(row)
+	_, err := queries.Raw(d.insertQuery(table, columns), values...).ExecContext(ctx, exec)
+	return err
+}
+
+// upsert inserts row, a pointer to a model, or updates it when its primary key already exists.
+// The creation time of an existing row is kept.
+func (d *Dialect) upsert(ctx context.Context, exec boil.ContextExecutor, table string, row interface{}) error {
+	columns, values := columnsOf(row)
+	var updated []string
+	for _, column := range columns {
+		if column != primaryKey && column != "created_at" {
+			updated = append(updated, column)
+		}
+	}
+	query := d.insertQuery(table, columns) + d.onConflict(d, updated)
+	_, err := queries.Raw(query, values...).ExecContext(ctx, exec)
+	return err
+}
+
+// update sets the columns of the row with the id to the values, and its update time to the current time.
+func (d *Dialect) update(ctx context.Context, exec boil.ContextExecutor, table, id string, values map[string]interface{}) error {
+	set := map[string]interface{}{"updated_at": time.Now()}
+	for column, value := range values {
+		set[column] = value
+	}
+	q := d.query(table, d.where(table+"."+primaryKey, "=", id))
+	queries.SetUpdate(q, set)
+	_, err := q.ExecContext(ctx, exec)
+	return err
+}
+
+func (d *Dialect) insertQuery(table string, columns []string) string {
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		d.quote(table),
+		strings.Join(strmangle.IdentQuoteSlice(d.LQ, d.RQ, columns), ","),
+		strmangle.Placeholders(d.UseIndexPlaceholders, len(columns), 1, 1),
+	)
+}
@@ -0,0 +1,31 @@
+package repository_test
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shota-tech/graphql/server/repository"
+)
+
+// dialects are the dialects every repository test runs against.
+var dialects = []struct {
+	name    string
+	dialect *repository.Dialect
+}{
+	{name: "mysql", dialect: repository.MySQL},
+	{name: "postgres", dialect: repository.Postgres},
+}
+
+// expect returns the pattern matching the query, written for MySQL, as the dialect spells it.
+func expect(d *repository.Dialect, query string) string {
+	if d == repository.Postgres {
+		n := 0
+		query = strings.ReplaceAll(query, "`", `"`)
+		query = regexp.MustCompile(`\?`).ReplaceAllStringFunc(query, func(string) string {
+			n++
+			return "$" + strconv.Itoa(n)
+		})
+	}
+	return regexp.QuoteMeta(query)
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+)
+
+type AccessTokenRepository struct {
+	db *DB
+}
+
+var _ repository.IAccessTokenRepository = &AccessTokenRepository{}
+
+func NewAccessTokenRepository(db *DB) *AccessTokenRepository {
+	return &AccessTokenRepository{db: db}
+}
+
+func (r *AccessTokenRepository) Store(_ context.Context, token *model.AccessToken) error {
+	if token == nil {
+		return errors.New("access token is required")
+	}
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	for _, stored := range r.db.accessTokens {
+		if stored.ID != token.ID && stored.TokenHash == token.TokenHash {
+			return errors.New("failed to upsert record: duplicate token hash")
+		}
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	r.db.accessTokens[token.ID] = *token
+	return nil
+}
+
+func (r *AccessTokenRepository) Get(_ context.Context, id string) (*model.AccessToken, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	token, ok := r.db.accessTokens[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &token, nil
+}
+
+// GetByTokenHash returns the access token whose hash matches, revoked and expired ones included.
+func (r *AccessTokenRepository) GetByTokenHash(_ context.Context, hash string) (*model.AccessToken, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	for _, token := range r.db.accessTokens {
+		if token.TokenHash == hash {
+			return &token, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+func (r *AccessTokenRepository) ListByUserID(_ context.Context, userID string) ([]*model.AccessToken, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	tokens := make([]*model.AccessToken, 0)
+	for _, token := range r.db.accessTokens {
+		if token.UserID == userID {
+			token := token
+			tokens = append(tokens, &token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].ID < tokens[j].ID
+	})
+	return tokens, nil
+}
@@ -0,0 +1,46 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessTokenRepository(t *testing.T) {
+	ctx := context.Background()
+	sut := memory.NewAccessTokenRepository(memory.NewDB())
+	tokens := []*model.AccessToken{
+		{ID: "cgj2k1tvqc7kfo1h3uqg", UserID: "auth0|123456", Name: "deploy", TokenHash: "hash2", Scopes: []string{"read:tasks"}},
+		{ID: "cgj2k1tvqc7kfo1h3uq0", UserID: "auth0|123456", Name: "ci", TokenHash: "hash1", Scopes: []string{"read:tasks", "write:tasks"}},
+	}
+	for _, token := range tokens {
+		require.NoError(t, sut.Store(ctx, token))
+		assert.False(t, token.CreatedAt.IsZero())
+	}
+	assert.Error(t, sut.Store(ctx, &model.AccessToken{ID: "cgj2k1tvqc7kfo1h3ur0", TokenHash: "hash1"}), "token hashes are unique")
+	assert.Error(t, sut.Store(ctx, nil))
+
+	t.Run("Get", func(t *testing.T) {
+		got, err := sut.Get(ctx, "cgj2k1tvqc7kfo1h3uq0")
+		require.NoError(t, err)
+		assert.Equal(t, tokens[1], got)
+		_, err = sut.Get(ctx, "unknown")
+		assert.Error(t, err)
+	})
+	t.Run("GetByTokenHash", func(t *testing.T) {
+		got, err := sut.GetByTokenHash(ctx, "hash2")
+		require.NoError(t, err)
+		assert.Equal(t, tokens[0], got)
+		_, err = sut.GetByTokenHash(ctx, "unknown")
+		assert.Error(t, err)
+	})
+	t.Run("ListByUserID", func(t *testing.T) {
+		got, err := sut.ListByUserID(ctx, "auth0|123456")
+		require.NoError(t, err)
+		assert.Equal(t, []*model.AccessToken{tokens[1], tokens[0]}, got)
+	})
+}
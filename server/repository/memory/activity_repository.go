@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+)
+
+type ActivityRepository struct {
+	db *DB
+}
+
+var _ repository.IActivityRepository = &ActivityRepository{}
+
+func NewActivityRepository(db *DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// Store appends the activity. Activities are never updated once recorded.
+func (r *ActivityRepository) Store(_ context.Context, activity *model.Activity) error {
+	if activity == nil {
+		return errors.New("activity is required")
+	}
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	if _, ok := r.db.activities[activity.ID]; ok {
+		return fmt.Errorf("failed to insert record: duplicate id %s", activity.ID)
+	}
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now()
+	}
+	r.db.activities[activity.ID] = *activity
+	return nil
+}
+
+func (r *ActivityRepository) Get(_ context.Context, id string) (*model.Activity, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	activity, ok := r.db.activities[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &activity, nil
+}
+
+// GetLatestByEntity returns the most recent activity recorded for the entity.
+func (r *ActivityRepository) GetLatestByEntity(_ context.Context, entityType model.EntityType, entityID string) (*model.Activity, error) {
+	activities := r.filter(func(activity model.Activity) bool {
+		return activity.EntityType == entityType && activity.EntityID == entityID
+	}, "", 1)
+	if len(activities) == 0 {
+		return nil, errNotFound
+	}
+	return activities[0], nil
+}
+
+// ListByTaskID returns the activities of the task and its todos, newest first.
+// When after is given, only activities older than it are returned.
+func (r *ActivityRepository) ListByTaskID(_ context.Context, taskID string, limit int, after string) ([]*model.Activity, error) {
+	return r.filter(func(activity model.Activity) bool {
+		return activity.TaskID != nil && *activity.TaskID == taskID
+	}, after, limit), nil
+}
+
+// ListByTaskUserID returns the activities of all tasks owned by the user, newest first.
+// When after is given, only activities older than it are returned.
+func (r *ActivityRepository) ListByTaskUserID(_ context.Context, userID string, limit int, after string) ([]*model.Activity, error) {
+	return r.filter(func(activity model.Activity) bool {
+		if activity.TaskID == nil {
+			return false
+		}
+		task, ok := r.db.tasks[*activity.TaskID]
+		return ok && task.UserID == userID
+	}, after, limit), nil
+}
+
+// filter returns up to limit activities matching f, newest first, which are older than after if given.
+// Like xids, the ids of activities sort by creation time.
+func (r *ActivityRepository) filter(f func(model.Activity) bool, after string, limit int) []*model.Activity {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	activities := make([]*model.Activity, 0)
+	for _, activity := range r.db.activities {
+		if (after == "" || activity.ID < after) && f(activity) {
+			activity := activity
+			activities = append(activities, &activity)
+		}
+	}
+	sort.Slice(activities, func(i, j int) bool {
+		return activities[i].ID > activities[j].ID
+	})
+	if len(activities) > limit {
+		activities = activities[:limit]
+	}
+	return activities
+}
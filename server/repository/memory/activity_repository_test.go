@@ -0,0 +1,62 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityRepository(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	require.NoError(t, memory.NewTaskRepository(db).Store(ctx, &model.Task{ID: "cg1m0bd1nm6u7kpjp15g", UserID: "auth0|123456"}))
+	sut := memory.NewActivityRepository(db)
+	taskID := "cg1m0bd1nm6u7kpjp15g"
+	activities := []*model.Activity{
+		{ID: "cgh1q5dvqc7j7g5i0qs0", UserID: "auth0|123456", EntityType: model.EntityTypeTask, EntityID: taskID, TaskID: &taskID, Action: model.ActivityActionCreate},
+		{ID: "cgh1q5dvqc7j7g5i0qsg", UserID: "auth0|123456", EntityType: model.EntityTypeTask, EntityID: taskID, TaskID: &taskID, Action: model.ActivityActionUpdate},
+		{ID: "cgh1q5dvqc7j7g5i0qt0", UserID: "auth0|123456", EntityType: model.EntityTypeTask, EntityID: "other", Action: model.ActivityActionCreate},
+	}
+	for _, activity := range activities {
+		require.NoError(t, sut.Store(ctx, activity))
+		assert.WithinDuration(t, time.Now(), activity.CreatedAt, time.Second)
+	}
+	assert.Error(t, sut.Store(ctx, activities[0]), "activities are never updated")
+	assert.Error(t, sut.Store(ctx, nil))
+
+	t.Run("Get", func(t *testing.T) {
+		got, err := sut.Get(ctx, "cgh1q5dvqc7j7g5i0qsg")
+		require.NoError(t, err)
+		assert.Equal(t, activities[1], got)
+		_, err = sut.Get(ctx, "unknown")
+		assert.Error(t, err)
+	})
+	t.Run("GetLatestByEntity", func(t *testing.T) {
+		got, err := sut.GetLatestByEntity(ctx, model.EntityTypeTask, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, activities[1], got)
+		_, err = sut.GetLatestByEntity(ctx, model.EntityTypeTodo, taskID)
+		assert.Error(t, err)
+	})
+	t.Run("ListByTaskID", func(t *testing.T) {
+		got, err := sut.ListByTaskID(ctx, taskID, 1, "")
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Activity{activities[1]}, got)
+		got, err = sut.ListByTaskID(ctx, taskID, 1, got[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Activity{activities[0]}, got)
+	})
+	t.Run("ListByTaskUserID", func(t *testing.T) {
+		got, err := sut.ListByTaskUserID(ctx, "auth0|123456", 10, "")
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Activity{activities[1], activities[0]}, got)
+		got, err = sut.ListByTaskUserID(ctx, "auth0|567890", 10, "")
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
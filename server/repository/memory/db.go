@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+)
+
+var errNotFound = repository.ErrNotFound
+
+// DB keeps the records of the repositories in memory, so that the server runs without a database.
+// The repositories store and return copies of the models, not the models passed to them.
+// Records are lost on restart and foreign keys are not checked.
+type DB struct {
+	// txMu serializes the transactions, mu guards the records.
+	txMu              sync.Mutex
+	mu                sync.RWMutex
+	users             map[string]model.User
+	tasks             map[string]model.Task
+	todos             map[string]model.Todo
+	activities        map[string]model.Activity
+	statusTransitions map[string]model.StatusTransition
+	accessTokens      map[string]model.AccessToken
+}
+
+func NewDB() *DB {
+	return &DB{
+		users:             make(map[string]model.User),
+		tasks:             make(map[string]model.Task),
+		todos:             make(map[string]model.Todo),
+		activities:        make(map[string]model.Activity),
+		statusTransitions: make(map[string]model.StatusTransition),
+		accessTokens:      make(map[string]model.AccessToken),
+	}
+}
+
+type txContextKey struct{}
+
+// Transaction runs fn, restoring the records as they were before it when it fails.
+// Transactions are serialized with each other but not isolated from the writes made outside of them.
+func (db *DB) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txContextKey{}) != nil {
+		return fn(ctx)
+	}
+	db.txMu.Lock()
+	defer db.txMu.Unlock()
+	snapshot := db.snapshot()
+	if err := fn(context.WithValue(ctx, txContextKey{}, struct{}{})); err != nil {
+		db.mu.Lock()
+		defer db.mu.Unlock()
+		db.users = snapshot.users
+		db.tasks = snapshot.tasks
+		db.todos = snapshot.todos
+		db.activities = snapshot.activities
+		db.statusTransitions = snapshot.statusTransitions
+		db.accessTokens = snapshot.accessTokens
+		return err
+	}
+	return nil
+}
+
+// snapshot copies the records, which are stored by value.
+func (db *DB) snapshot() *DB {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return &DB{
+		users:             maps.Clone(db.users),
+		tasks:             maps.Clone(db.tasks),
+		todos:             maps.Clone(db.todos),
+		activities:        maps.Clone(db.activities),
+		statusTransitions: maps.Clone(db.statusTransitions),
+		accessTokens:      maps.Clone(db.accessTokens),
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_Transaction(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	todos := memory.NewTodoRepository(db)
+	todo := &model.Todo{ID: "cg1m3ll1nm6u7kpjp1a0", Text: "todo1", TaskID: "cg1m0bd1nm6u7kpjp15g", Position: 0}
+	require.NoError(t, todos.Store(ctx, todo))
+
+	t.Run("rolled back", func(t *testing.T) {
+		err := db.Transaction(ctx, func(ctx context.Context) error {
+			require.NoError(t, todos.Store(ctx, &model.Todo{ID: "cg1m3ll1nm6u7kpjp1a0", Text: "edited", TaskID: "cg1m0bd1nm6u7kpjp15g", Position: 1}))
+			return db.Transaction(ctx, func(ctx context.Context) error {
+				return assert.AnError
+			})
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+		got, err := todos.Get(ctx, todo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, todo, got)
+	})
+	t.Run("committed", func(t *testing.T) {
+		edited := &model.Todo{ID: "cg1m3ll1nm6u7kpjp1a0", Text: "edited", TaskID: "cg1m0bd1nm6u7kpjp15g", Position: 1}
+		err := db.Transaction(ctx, func(ctx context.Context) error {
+			return todos.Store(ctx, edited)
+		})
+		require.NoError(t, err)
+		got, err := todos.Get(ctx, todo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, edited, got)
+	})
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+)
+
+type StatusTransitionRepository struct {
+	db *DB
+}
+
+var _ repository.IStatusTransitionRepository = &StatusTransitionRepository{}
+
+func NewStatusTransitionRepository(db *DB) *StatusTransitionRepository {
+	return &StatusTransitionRepository{db: db}
+}
+
+func (r *StatusTransitionRepository) Store(_ context.Context, transition *model.StatusTransition) error {
+	if transition == nil {
+		return errors.New("status transition is required")
+	}
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	if _, ok := r.db.statusTransitions[transition.ID]; ok {
+		return fmt.Errorf("failed to insert record: duplicate id %s", transition.ID)
+	}
+	if transition.CreatedAt.IsZero() {
+		transition.CreatedAt = time.Now()
+	}
+	r.db.statusTransitions[transition.ID] = *transition
+	return nil
+}
+
+// ListByTaskUserID returns the transitions of all tasks owned by the user
+// made until the given time, oldest first.
+func (r *StatusTransitionRepository) ListByTaskUserID(_ context.Context, userID string, until time.Time) ([]*model.StatusTransition, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	transitions := make([]*model.StatusTransition, 0)
+	for _, transition := range r.db.statusTransitions {
+		task, ok := r.db.tasks[transition.TaskID]
+		if ok && task.UserID == userID && !transition.CreatedAt.After(until) {
+			transition := transition
+			transitions = append(transitions, &transition)
+		}
+	}
+	sort.Slice(transitions, func(i, j int) bool {
+		if !transitions[i].CreatedAt.Equal(transitions[j].CreatedAt) {
+			return transitions[i].CreatedAt.Before(transitions[j].CreatedAt)
+		}
+		return transitions[i].ID < transitions[j].ID
+	})
+	return transitions, nil
+}
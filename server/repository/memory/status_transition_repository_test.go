@@ -0,0 +1,38 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusTransitionRepository(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	require.NoError(t, memory.NewTaskRepository(db).Store(ctx, &model.Task{ID: "cg1m0bd1nm6u7kpjp15g", UserID: "auth0|123456"}))
+	sut := memory.NewStatusTransitionRepository(db)
+	todo := model.StatusTodo
+	createdAt := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	transitions := []*model.StatusTransition{
+		{ID: "cgk3p2tvqc7kfo1h3uq0", TaskID: "cg1m0bd1nm6u7kpjp15g", From: &todo, To: model.StatusInProgress, CreatedAt: createdAt.Add(time.Hour)},
+		{ID: "cgk3p2tvqc7kfo1h3uqg", TaskID: "cg1m0bd1nm6u7kpjp15g", To: model.StatusTodo, CreatedAt: createdAt},
+		{ID: "cgk3p2tvqc7kfo1h3ur0", TaskID: "cg1m0bd1nm6u7kpjp15g", From: &todo, To: model.StatusDone, CreatedAt: createdAt.Add(2 * time.Hour)},
+	}
+	for _, transition := range transitions {
+		require.NoError(t, sut.Store(ctx, transition))
+	}
+	assert.Error(t, sut.Store(ctx, transitions[0]))
+	assert.Error(t, sut.Store(ctx, nil))
+
+	got, err := sut.ListByTaskUserID(ctx, "auth0|123456", createdAt.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []*model.StatusTransition{transitions[1], transitions[0]}, got)
+	got, err = sut.ListByTaskUserID(ctx, "auth0|567890", createdAt.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+)
+
+type TaskRepository struct {
+	db *DB
+}
+
+var _ repository.ITaskRepository = &TaskRepository{}
+
+func NewTaskRepository(db *DB) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+func (r *TaskRepository) Store(_ context.Context, task *model.Task) error {
+	if task == nil {
+		return errors.New("task is required")
+	}
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	// the creation time is kept on updates like the sql repositories do
+	if stored, ok := r.db.tasks[task.ID]; ok {
+		task.CreatedAt = stored.CreatedAt
+	} else if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+	r.db.tasks[task.ID] = *task
+	return nil
+}
+
+func (r *TaskRepository) Get(_ context.Context, id string) (*model.Task, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	task, ok := r.db.tasks[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &task, nil
+}
+
+// GetForUpdate is Get, the transactions being serialized.
+func (r *TaskRepository) GetForUpdate(ctx context.Context, id string) (*model.Task, error) {
+	return r.Get(ctx, id)
+}
+
+func (r *TaskRepository) List(_ context.Context, ids []string) ([]*model.Task, error) {
+	return r.filter(func(task model.Task) bool {
+		return contains(ids, task.ID)
+	}), nil
+}
+
+func (r *TaskRepository) ListByUserID(_ context.Context, userID string) ([]*model.Task, error) {
+	return r.filter(func(task model.Task) bool {
+		return task.UserID == userID
+	}), nil
+}
+
+func (r *TaskRepository) ListByUserIDs(_ context.Context, userIDs []string) ([]*model.Task, error) {
+	return r.filter(func(task model.Task) bool {
+		return contains(userIDs, task.UserID)
+	}), nil
+}
+
+// filter returns the tasks matching f ordered by id, like the primary key order of the database.
+func (r *TaskRepository) filter(f func(model.Task) bool) []*model.Task {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	tasks := make([]*model.Task, 0)
+	for _, task := range r.db.tasks {
+		if f(task) {
+			task := task
+			tasks = append(tasks, &task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks
+}
@@ -0,0 +1,61 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository(t *testing.T) {
+	ctx := context.Background()
+	sut := memory.NewTaskRepository(memory.NewDB())
+	tasks := []*model.Task{
+		{ID: "cg1m0bd1nm6u7kpjp16g", Text: "task2", Status: model.StatusInProgress, UserID: "auth0|123456"},
+		{ID: "cg1m0bd1nm6u7kpjp15g", Text: "task1", Status: model.StatusTodo, UserID: "auth0|123456"},
+		{ID: "cg1m0bd1nm6u7kpjp17g", Text: "task3", Status: model.StatusDone, UserID: "auth0|567890"},
+	}
+	for _, task := range tasks {
+		require.NoError(t, sut.Store(ctx, task))
+	}
+	assert.Error(t, sut.Store(ctx, nil))
+
+	t.Run("Get", func(t *testing.T) {
+		got, err := sut.Get(ctx, "cg1m0bd1nm6u7kpjp15g")
+		require.NoError(t, err)
+		assert.Equal(t, tasks[1], got)
+		_, err = sut.Get(ctx, "unknown")
+		assert.Error(t, err)
+	})
+	t.Run("List", func(t *testing.T) {
+		got, err := sut.List(ctx, []string{"cg1m0bd1nm6u7kpjp16g", "cg1m0bd1nm6u7kpjp15g", "unknown"})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Task{tasks[1], tasks[0]}, got)
+	})
+	t.Run("ListByUserID", func(t *testing.T) {
+		got, err := sut.ListByUserID(ctx, "auth0|123456")
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Task{tasks[1], tasks[0]}, got)
+	})
+	t.Run("ListByUserIDs", func(t *testing.T) {
+		got, err := sut.ListByUserIDs(ctx, []string{"auth0|567890", "unknown"})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Task{tasks[2]}, got)
+	})
+	t.Run("Store updates the record", func(t *testing.T) {
+		task := *tasks[0]
+		task.Status = model.StatusDone
+		require.NoError(t, sut.Store(ctx, &task))
+		got, err := sut.Get(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusDone, got.Status)
+		// the stored record is a copy
+		got.Text = "changed"
+		got, err = sut.Get(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "task2", got.Text)
+	})
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+)
+
+type TodoRepository struct {
+	db *DB
+}
+
+var _ repository.ITodoRepository = &TodoRepository{}
+
+func NewTodoRepository(db *DB) *TodoRepository {
+	return &TodoRepository{db: db}
+}
+
+func (r *TodoRepository) Store(_ context.Context, todo *model.Todo) error {
+	if todo == nil {
+		return errors.New("todo is required")
+	}
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	r.db.todos[todo.ID] = *todo
+	return nil
+}
+
+func (r *TodoRepository) Get(_ context.Context, id string) (*model.Todo, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	todo, ok := r.db.todos[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &todo, nil
+}
+
+// GetForUpdate is Get, the transactions being serialized.
+func (r *TodoRepository) GetForUpdate(ctx context.Context, id string) (*model.Todo, error) {
+	return r.Get(ctx, id)
+}
+
+func (r *TodoRepository) List(_ context.Context, ids []string) ([]*model.Todo, error) {
+	return r.filter(func(todo model.Todo) bool {
+		return contains(ids, todo.ID)
+	}, byID), nil
+}
+
+func (r *TodoRepository) ListByTaskIDs(_ context.Context, taskIDs []string) ([]*model.Todo, error) {
+	return r.filter(func(todo model.Todo) bool {
+		return contains(taskIDs, todo.TaskID)
+	}, byPosition), nil
+}
+
+func (r *TodoRepository) ListByParentIDs(_ context.Context, parentIDs []string) ([]*model.Todo, error) {
+	return r.filter(func(todo model.Todo) bool {
+		return todo.ParentID != nil && contains(parentIDs, *todo.ParentID)
+	}, byPosition), nil
+}
+
+// CountByTaskIDs counts the todos of each task. Tasks without any todo are omitted.
+func (r *TodoRepository) CountByTaskIDs(_ context.Context, taskIDs []string) ([]*model.Progress, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	counts := make(map[string]*model.Progress)
+	for _, todo := range r.db.todos {
+		if !contains(taskIDs, todo.TaskID) {
+			continue
+		}
+		progress, ok := counts[todo.TaskID]
+		if !ok {
+			progress = &model.Progress{TaskID: todo.TaskID}
+			counts[todo.TaskID] = progress
+		}
+		progress.Total++
+		if todo.Done {
+			progress.Done++
+		}
+	}
+	progresses := make([]*model.Progress, 0, len(counts))
+	for _, progress := range counts {
+		progresses = append(progresses, progress)
+	}
+	sort.Slice(progresses, func(i, j int) bool {
+		return progresses[i].TaskID < progresses[j].TaskID
+	})
+	return progresses, nil
+}
+
+func byID(a, b *model.Todo) bool {
+	return a.ID < b.ID
+}
+
+// byPosition breaks ties by id to keep the order stable.
+func byPosition(a, b *model.Todo) bool {
+	if a.Position != b.Position {
+		return a.Position < b.Position
+	}
+	return a.ID < b.ID
+}
+
+func (r *TodoRepository) filter(f func(model.Todo) bool, less func(a, b *model.Todo) bool) []*model.Todo {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	todos := make([]*model.Todo, 0)
+	for _, todo := range r.db.todos {
+		if f(todo) {
+			todo := todo
+			todos = append(todos, &todo)
+		}
+	}
+	sort.Slice(todos, func(i, j int) bool {
+		return less(todos[i], todos[j])
+	})
+	return todos
+}
@@ -0,0 +1,58 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTodoRepository(t *testing.T) {
+	ctx := context.Background()
+	sut := memory.NewTodoRepository(memory.NewDB())
+	parentID := "cg1m3ll1nm6u7kpjp1a0"
+	todos := []*model.Todo{
+		{ID: "cg1m3ll1nm6u7kpjp1a0", Text: "todo1", Done: true, TaskID: "cg1m0bd1nm6u7kpjp15g", Position: 1},
+		{ID: "cg1m3ll1nm6u7kpjp1ag", Text: "todo2", Done: false, TaskID: "cg1m0bd1nm6u7kpjp15g", Position: 0},
+		{ID: "cg1m3ll1nm6u7kpjp1b0", Text: "todo3", Done: false, TaskID: "cg1m0bd1nm6u7kpjp15g", ParentID: &parentID, Position: 0},
+		{ID: "cg1m3ll1nm6u7kpjp1bg", Text: "todo4", Done: true, TaskID: "cg1m0bd1nm6u7kpjp16g", Position: 0},
+	}
+	for _, todo := range todos {
+		require.NoError(t, sut.Store(ctx, todo))
+	}
+	assert.Error(t, sut.Store(ctx, nil))
+
+	t.Run("Get", func(t *testing.T) {
+		got, err := sut.Get(ctx, "cg1m3ll1nm6u7kpjp1b0")
+		require.NoError(t, err)
+		assert.Equal(t, todos[2], got)
+		_, err = sut.Get(ctx, "unknown")
+		assert.Error(t, err)
+	})
+	t.Run("List", func(t *testing.T) {
+		got, err := sut.List(ctx, []string{"cg1m3ll1nm6u7kpjp1bg", "cg1m3ll1nm6u7kpjp1a0"})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Todo{todos[0], todos[3]}, got)
+	})
+	t.Run("ListByTaskIDs is ordered by position", func(t *testing.T) {
+		got, err := sut.ListByTaskIDs(ctx, []string{"cg1m0bd1nm6u7kpjp15g"})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Todo{todos[1], todos[2], todos[0]}, got)
+	})
+	t.Run("ListByParentIDs", func(t *testing.T) {
+		got, err := sut.ListByParentIDs(ctx, []string{parentID})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Todo{todos[2]}, got)
+	})
+	t.Run("CountByTaskIDs", func(t *testing.T) {
+		got, err := sut.CountByTaskIDs(ctx, []string{"cg1m0bd1nm6u7kpjp15g", "cg1m0bd1nm6u7kpjp16g", "cg1m0bd1nm6u7kpjp17g"})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Progress{
+			{TaskID: "cg1m0bd1nm6u7kpjp15g", Done: 1, Total: 3},
+			{TaskID: "cg1m0bd1nm6u7kpjp16g", Done: 1, Total: 1},
+		}, got)
+	})
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+)
+
+type UserRepository struct {
+	db *DB
+}
+
+var _ repository.IUserRepository = &UserRepository{}
+
+func NewUserRepository(db *DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) Store(_ context.Context, user *model.User) error {
+	if user == nil {
+		return errors.New("user is required")
+	}
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	r.db.users[user.ID] = *user
+	return nil
+}
+
+func (r *UserRepository) Get(_ context.Context, id string) (*model.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	user, ok := r.db.users[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) UpdateName(_ context.Context, id, name string) error {
+	return r.update(id, func(user *model.User) { user.Name = name })
+}
+
+func (r *UserRepository) UpdateRole(_ context.Context, id string, role model.Role) error {
+	return r.update(id, func(user *model.User) { user.Role = role })
+}
+
+// update changes the user with the id, if any, as an UPDATE statement does.
+func (r *UserRepository) update(id string, f func(*model.User)) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	if user, ok := r.db.users[id]; ok {
+		f(&user)
+		r.db.users[id] = user
+	}
+	return nil
+}
+
+func (r *UserRepository) List(_ context.Context, ids []string) ([]*model.User, error) {
+	return r.filter(func(user model.User) bool {
+		return contains(ids, user.ID)
+	}), nil
+}
+
+// Search returns the users matching every condition of the filter, ordered by id.
+// The query is matched case-insensitively against the name and the email.
+func (r *UserRepository) Search(_ context.Context, filter *model.UserFilter) ([]*model.User, error) {
+	return r.filter(func(user model.User) bool {
+		if filter == nil {
+			return true
+		}
+		if filter.Role != nil && user.Role != *filter.Role {
+			return false
+		}
+		if filter.Query != nil && *filter.Query != "" {
+			query := strings.ToLower(*filter.Query)
+			return strings.Contains(strings.ToLower(user.Name), query) ||
+				user.Email != nil && strings.Contains(strings.ToLower(*user.Email), query)
+		}
+		return true
+	}), nil
+}
+
+func (r *UserRepository) filter(f func(model.User) bool) []*model.User {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+	users := make([]*model.User, 0)
+	for _, user := range r.db.users {
+		if f(user) {
+			user := user
+			users = append(users, &user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+	return users
+}
@@ -0,0 +1,78 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+	"github.com/shota-tech/graphql/server/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository(t *testing.T) {
+	ctx := context.Background()
+	sut := memory.NewUserRepository(memory.NewDB())
+	email := "Alice@example.com"
+	users := []*model.User{
+		{ID: "auth0|567890", Name: "bob", Role: model.RoleMember},
+		{ID: "auth0|123456", Name: "alice", Email: &email, Role: model.RoleAdmin},
+	}
+	for _, user := range users {
+		require.NoError(t, sut.Store(ctx, user))
+	}
+	assert.Error(t, sut.Store(ctx, nil))
+
+	t.Run("Get", func(t *testing.T) {
+		got, err := sut.Get(ctx, "auth0|123456")
+		require.NoError(t, err)
+		assert.Equal(t, users[1], got)
+		_, err = sut.Get(ctx, "unknown")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		got, err := sut.List(ctx, []string{"auth0|567890", "auth0|123456", "unknown"})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.User{users[1], users[0]}, got)
+	})
+
+	role := model.RoleAdmin
+	query := "EXAMPLE"
+	tests := map[string]struct {
+		filter *model.UserFilter
+		want   []*model.User
+	}{
+		"no filter": {
+			filter: nil,
+			want:   []*model.User{users[1], users[0]},
+		},
+		"role": {
+			filter: &model.UserFilter{Role: &role},
+			want:   []*model.User{users[1]},
+		},
+		"query matches the email case-insensitively": {
+			filter: &model.UserFilter{Query: &query},
+			want:   []*model.User{users[1]},
+		},
+	}
+	for name, tt := range tests {
+		t.Run("Search "+name, func(t *testing.T) {
+			got, err := sut.Search(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("Update", func(t *testing.T) {
+		require.NoError(t, sut.UpdateName(ctx, "auth0|567890", "robert"))
+		require.NoError(t, sut.UpdateRole(ctx, "auth0|567890", model.RoleViewer))
+		got, err := sut.Get(ctx, "auth0|567890")
+		require.NoError(t, err)
+		assert.Equal(t, &model.User{ID: "auth0|567890", Name: "robert", Role: model.RoleViewer}, got)
+		require.NoError(t, sut.UpdateName(ctx, "unknown", "nobody"))
+		_, err = sut.Get(ctx, "unknown")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
@@ -4,11 +4,17 @@
 package models
 
 var TableNames = struct {
-	Tasks string
-	Todos string
-	Users string
+	Activities           string
+	PersonalAccessTokens string
+	StatusTransitions    string
+	Tasks                string
+	Todos                string
+	Users                string
 }{
-	Tasks: "tasks",
-	Todos: "todos",
-	Users: "users",
+	Activities:           "activities",
+	PersonalAccessTokens: "personal_access_tokens",
+	StatusTransitions:    "status_transitions",
+	Tasks:                "tasks",
+	Todos:                "todos",
+	Users:                "users",
 }
@@ -0,0 +1,1221 @@
+// Code generated by SQLBoiler 4.14.2 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// PersonalAccessToken is an object representing the database table.
+type PersonalAccessToken struct {
+	ID        string    `boil:"id" json:"id" toml:"id" yaml:"id"`
+	UserID    string    `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
+	Name      string    `boil:"name" json:"name" toml:"name" yaml:"name"`
+	TokenHash string    `boil:"token_hash" json:"token_hash" toml:"token_hash" yaml:"token_hash"`
+	Scopes    string    `boil:"scopes" json:"scopes" toml:"scopes" yaml:"scopes"`
+	ExpiresAt null.Time `boil:"expires_at" json:"expires_at,omitempty" toml:"expires_at" yaml:"expires_at,omitempty"`
+	RevokedAt null.Time `boil:"revoked_at" json:"revoked_at,omitempty" toml:"revoked_at" yaml:"revoked_at,omitempty"`
+	CreatedAt time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt time.Time `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+
+	R *personalAccessTokenR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L personalAccessTokenL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var PersonalAccessTokenColumns = struct {
+	ID        string
+	UserID    string
+	Name      string
+	TokenHash string
+	Scopes    string
+	ExpiresAt string
+	RevokedAt string
+	CreatedAt string
+	UpdatedAt string
+}{
+	ID:        "id",
+	UserID:    "user_id",
+	Name:      "name",
+	TokenHash: "token_hash",
+	Scopes:    "scopes",
+	ExpiresAt: "expires_at",
+	RevokedAt: "revoked_at",
+	CreatedAt: "created_at",
+	UpdatedAt: "updated_at",
+}
+
+var PersonalAccessTokenTableColumns = struct {
+	ID        string
+	UserID    string
+	Name      string
+	TokenHash string
+	Scopes    string
+	ExpiresAt string
+	RevokedAt string
+	CreatedAt string
+	UpdatedAt string
+}{
+	ID:        "personal_access_tokens.id",
+	UserID:    "personal_access_tokens.user_id",
+	Name:      "personal_access_tokens.name",
+	TokenHash: "personal_access_tokens.token_hash",
+	Scopes:    "personal_access_tokens.scopes",
+	ExpiresAt: "personal_access_tokens.expires_at",
+	RevokedAt: "personal_access_tokens.revoked_at",
+	CreatedAt: "personal_access_tokens.created_at",
+	UpdatedAt: "personal_access_tokens.updated_at",
+}
+
+// Generated where
+
+type whereHelpernull_Time struct{ field string }
+
+func (w whereHelpernull_Time) EQ(x null.Time) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_Time) NEQ(x null.Time) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_Time) LT(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_Time) LTE(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_Time) GT(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_Time) GTE(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+func (w whereHelpernull_Time) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_Time) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
+var PersonalAccessTokenWhere = struct {
+	ID        whereHelperstring
+	UserID    whereHelperstring
+	Name      whereHelperstring
+	TokenHash whereHelperstring
+	Scopes    whereHelperstring
+	ExpiresAt whereHelpernull_Time
+	RevokedAt whereHelpernull_Time
+	CreatedAt whereHelpertime_Time
+	UpdatedAt whereHelpertime_Time
+}{
+	ID:        whereHelperstring{field: "`personal_access_tokens`.`id`"},
+	UserID:    whereHelperstring{field: "`personal_access_tokens`.`user_id`"},
+	Name:      whereHelperstring{field: "`personal_access_tokens`.`name`"},
+	TokenHash: whereHelperstring{field: "`personal_access_tokens`.`token_hash`"},
+	Scopes:    whereHelperstring{field: "`personal_access_tokens`.`scopes`"},
+	ExpiresAt: whereHelpernull_Time{field: "`personal_access_tokens`.`expires_at`"},
+	RevokedAt: whereHelpernull_Time{field: "`personal_access_tokens`.`revoked_at`"},
+	CreatedAt: whereHelpertime_Time{field: "`personal_access_tokens`.`created_at`"},
+	UpdatedAt: whereHelpertime_Time{field: "`personal_access_tokens`.`updated_at`"},
+}
+
+// PersonalAccessTokenRels is where relationship names are stored.
+var PersonalAccessTokenRels = struct {
+	User string
+}{
+	User: "User",
+}
+
+// personalAccessTokenR is where relationships are stored.
+type personalAccessTokenR struct {
+	User *User `boil:"User" json:"User" toml:"User" yaml:"User"`
+}
+
+// NewStruct creates a new relationship struct
+func (*personalAccessTokenR) NewStruct() *personalAccessTokenR {
+	return &personalAccessTokenR{}
+}
+
+func (r *personalAccessTokenR) GetUser() *User {
+	if r == nil {
+		return nil
+	}
+	return r.User
+}
+
+// personalAccessTokenL is where Load methods for each relationship are stored.
+type personalAccessTokenL struct{}
+
+var (
+	personalAccessTokenAllColumns            = []string{"id", "user_id", "name", "token_hash", "scopes", "expires_at", "revoked_at", "created_at", "updated_at"}
+	personalAccessTokenColumnsWithoutDefault = []string{"id", "user_id", "name", "token_hash", "scopes", "expires_at", "revoked_at"}
+	personalAccessTokenColumnsWithDefault    = []string{"created_at", "updated_at"}
+	personalAccessTokenPrimaryKeyColumns     = []string{"id"}
+	personalAccessTokenGeneratedColumns      = []string{}
+)
+
+type (
+	// PersonalAccessTokenSlice is an alias for a slice of pointers to PersonalAccessToken.
+	// This should almost always be used instead of []PersonalAccessToken.
+	PersonalAccessTokenSlice []*PersonalAccessToken
+	// PersonalAccessTokenHook is the signature for custom PersonalAccessToken hook methods
+	PersonalAccessTokenHook func(context.Context, boil.ContextExecutor, *PersonalAccessToken) error
+
+	personalAccessTokenQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	personalAccessTokenType                 = reflect.TypeOf(&PersonalAccessToken{})
+	personalAccessTokenMapping              = queries.MakeStructMapping(personalAccessTokenType)
+	personalAccessTokenPrimaryKeyMapping, _ = queries.BindMapping(personalAccessTokenType, personalAccessTokenMapping, personalAccessTokenPrimaryKeyColumns)
+	personalAccessTokenInsertCacheMut       sync.RWMutex
+	personalAccessTokenInsertCache          = make(map[string]insertCache)
+	personalAccessTokenUpdateCacheMut       sync.RWMutex
+	personalAccessTokenUpdateCache          = make(map[string]updateCache)
+	personalAccessTokenUpsertCacheMut       sync.RWMutex
+	personalAccessTokenUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var personalAccessTokenAfterSelectHooks []PersonalAccessTokenHook
+
+var personalAccessTokenBeforeInsertHooks []PersonalAccessTokenHook
+var personalAccessTokenAfterInsertHooks []PersonalAccessTokenHook
+
+var personalAccessTokenBeforeUpdateHooks []PersonalAccessTokenHook
+var personalAccessTokenAfterUpdateHooks []PersonalAccessTokenHook
+
+var personalAccessTokenBeforeDeleteHooks []PersonalAccessTokenHook
+var personalAccessTokenAfterDeleteHooks []PersonalAccessTokenHook
+
+var personalAccessTokenBeforeUpsertHooks []PersonalAccessTokenHook
+var personalAccessTokenAfterUpsertHooks []PersonalAccessTokenHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *PersonalAccessToken) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range personalAccessTokenAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *PersonalAccessToken) doBeforeInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range personalAccessTokenBeforeInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *PersonalAccessToken) doAfterInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range personalAccessTokenAfterInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *PersonalAccessToken) doBeforeUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range personalAccessTokenBeforeUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *PersonalAccessToken) doAfterUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range personalAccessTokenAfterUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *PersonalAccessToken) doBeforeDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range personalAccessTokenBeforeDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *PersonalAccessToken) doAfterDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range personalAccessTokenAfterDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *PersonalAccessToken) doBeforeUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range personalAccessTokenBeforeUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *PersonalAccessToken) doAfterUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range personalAccessTokenAfterUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddPersonalAccessTokenHook registers your hook function for all future operations.
+func AddPersonalAccessTokenHook(hookPoint boil.HookPoint, personalAccessTokenHook PersonalAccessTokenHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		personalAccessTokenAfterSelectHooks = append(personalAccessTokenAfterSelectHooks, personalAccessTokenHook)
+	case boil.BeforeInsertHook:
+		personalAccessTokenBeforeInsertHooks = append(personalAccessTokenBeforeInsertHooks, personalAccessTokenHook)
+	case boil.AfterInsertHook:
+		personalAccessTokenAfterInsertHooks = append(personalAccessTokenAfterInsertHooks, personalAccessTokenHook)
+	case boil.BeforeUpdateHook:
+		personalAccessTokenBeforeUpdateHooks = append(personalAccessTokenBeforeUpdateHooks, personalAccessTokenHook)
+	case boil.AfterUpdateHook:
+		personalAccessTokenAfterUpdateHooks = append(personalAccessTokenAfterUpdateHooks, personalAccessTokenHook)
+	case boil.BeforeDeleteHook:
+		personalAccessTokenBeforeDeleteHooks = append(personalAccessTokenBeforeDeleteHooks, personalAccessTokenHook)
+	case boil.AfterDeleteHook:
+		personalAccessTokenAfterDeleteHooks = append(personalAccessTokenAfterDeleteHooks, personalAccessTokenHook)
+	case boil.BeforeUpsertHook:
+		personalAccessTokenBeforeUpsertHooks = append(personalAccessTokenBeforeUpsertHooks, personalAccessTokenHook)
+	case boil.AfterUpsertHook:
+		personalAccessTokenAfterUpsertHooks = append(personalAccessTokenAfterUpsertHooks, personalAccessTokenHook)
+	}
+}
+
+// One returns a single personalAccessToken record from the query.
+func (q personalAccessTokenQuery) One(ctx context.Context, exec boil.ContextExecutor) (*PersonalAccessToken, error) {
+	o := &PersonalAccessToken{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for personal_access_tokens")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all PersonalAccessToken records from the query.
+func (q personalAccessTokenQuery) All(ctx context.Context, exec boil.ContextExecutor) (PersonalAccessTokenSlice, error) {
+	var o []*PersonalAccessToken
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to PersonalAccessToken slice")
+	}
+
+	if len(personalAccessTokenAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all PersonalAccessToken records in the query.
+func (q personalAccessTokenQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count personal_access_tokens rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q personalAccessTokenQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if personal_access_tokens exists")
+	}
+
+	return count > 0, nil
+}
+
+// User pointed to by the foreign key.
+func (o *PersonalAccessToken) User(mods ...qm.QueryMod) userQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("`id` = ?", o.UserID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return Users(queryMods...)
+}
+
+// LoadUser allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (personalAccessTokenL) LoadUser(ctx context.Context, e boil.ContextExecutor, singular bool, maybePersonalAccessToken interface{}, mods queries.Applicator) error {
+	var slice []*PersonalAccessToken
+	var object *PersonalAccessToken
+
+	if singular {
+		var ok bool
+		object, ok = maybePersonalAccessToken.(*PersonalAccessToken)
+		if !ok {
+			object = new(PersonalAccessToken)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybePersonalAccessToken)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybePersonalAccessToken))
+			}
+		}
+	} else {
+		s, ok := maybePersonalAccessToken.(*[]*PersonalAccessToken)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybePersonalAccessToken)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybePersonalAccessToken))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &personalAccessTokenR{}
+		}
+		args = append(args, object.UserID)
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &personalAccessTokenR{}
+			}
+
+			for _, a := range args {
+				if a == obj.UserID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.UserID)
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`users`),
+		qm.WhereIn(`users.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load User")
+	}
+
+	var resultSlice []*User
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice User")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for users")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for users")
+	}
+
+	if len(userAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.User = foreign
+		if foreign.R == nil {
+			foreign.R = &userR{}
+		}
+		foreign.R.PersonalAccessTokens = append(foreign.R.PersonalAccessTokens, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.UserID == foreign.ID {
+				local.R.User = foreign
+				if foreign.R == nil {
+					foreign.R = &userR{}
+				}
+				foreign.R.PersonalAccessTokens = append(foreign.R.PersonalAccessTokens, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetUser of the personalAccessToken to the related item.
+// Sets o.R.User to related.
+// Adds o to related.R.PersonalAccessTokens.
+func (o *PersonalAccessToken) SetUser(ctx context.Context, exec boil.ContextExecutor, insert bool, related *User) error {
+	var err error
+	if insert {
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE `personal_access_tokens` SET %s WHERE %s",
+		strmangle.SetParamNames("`", "`", 0, []string{"user_id"}),
+		strmangle.WhereClause("`", "`", 0, personalAccessTokenPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, updateQuery)
+		fmt.Fprintln(writer, values)
+	}
+	if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.UserID = related.ID
+	if o.R == nil {
+		o.R = &personalAccessTokenR{
+			User: related,
+		}
+	} else {
+		o.R.User = related
+	}
+
+	if related.R == nil {
+		related.R = &userR{
+			PersonalAccessTokens: PersonalAccessTokenSlice{o},
+		}
+	} else {
+		related.R.PersonalAccessTokens = append(related.R.PersonalAccessTokens, o)
+	}
+
+	return nil
+}
+
+// PersonalAccessTokens retrieves all the records using an executor.
+func PersonalAccessTokens(mods ...qm.QueryMod) personalAccessTokenQuery {
+	mods = append(mods, qm.From("`personal_access_tokens`"))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"`personal_access_tokens`.*"})
+	}
+
+	return personalAccessTokenQuery{q}
+}
+
+// FindPersonalAccessToken retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindPersonalAccessToken(ctx context.Context, exec boil.ContextExecutor, iD string, selectCols ...string) (*PersonalAccessToken, error) {
+	personalAccessTokenObj := &PersonalAccessToken{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from `personal_access_tokens` where `id`=?", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(ctx, exec, personalAccessTokenObj)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: unable to select from personal_access_tokens")
+	}
+
+	if err = personalAccessTokenObj.doAfterSelectHooks(ctx, exec); err != nil {
+		return personalAccessTokenObj, err
+	}
+
+	return personalAccessTokenObj, nil
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *PersonalAccessToken) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no personal_access_tokens provided for insertion")
+	}
+
+	var err error
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+		if o.UpdatedAt.IsZero() {
+			o.UpdatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(personalAccessTokenColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	personalAccessTokenInsertCacheMut.RLock()
+	cache, cached := personalAccessTokenInsertCache[key]
+	personalAccessTokenInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			personalAccessTokenAllColumns,
+			personalAccessTokenColumnsWithDefault,
+			personalAccessTokenColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(personalAccessTokenType, personalAccessTokenMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(personalAccessTokenType, personalAccessTokenMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO `personal_access_tokens` (`%s`) %%sVALUES (%s)%%s", strings.Join(wl, "`,`"), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO `personal_access_tokens` () VALUES ()%s%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			cache.retQuery = fmt.Sprintf("SELECT `%s` FROM `personal_access_tokens` WHERE %s", strings.Join(returnColumns, "`,`"), strmangle.WhereClause("`", "`", 0, personalAccessTokenPrimaryKeyColumns))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	_, err = exec.ExecContext(ctx, cache.query, vals...)
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to insert into personal_access_tokens")
+	}
+
+	var identifierCols []interface{}
+
+	if len(cache.retMapping) == 0 {
+		goto CacheNoHooks
+	}
+
+	identifierCols = []interface{}{
+		o.ID,
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.retQuery)
+		fmt.Fprintln(writer, identifierCols...)
+	}
+	err = exec.QueryRowContext(ctx, cache.retQuery, identifierCols...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to populate default values for personal_access_tokens")
+	}
+
+CacheNoHooks:
+	if !cached {
+		personalAccessTokenInsertCacheMut.Lock()
+		personalAccessTokenInsertCache[key] = cache
+		personalAccessTokenInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(ctx, exec)
+}
+
+// Update uses an executor to update the PersonalAccessToken.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *PersonalAccessToken) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		o.UpdatedAt = currTime
+	}
+
+	var err error
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	personalAccessTokenUpdateCacheMut.RLock()
+	cache, cached := personalAccessTokenUpdateCache[key]
+	personalAccessTokenUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			personalAccessTokenAllColumns,
+			personalAccessTokenPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("models: unable to update personal_access_tokens, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE `personal_access_tokens` SET %s WHERE %s",
+			strmangle.SetParamNames("`", "`", 0, wl),
+			strmangle.WhereClause("`", "`", 0, personalAccessTokenPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(personalAccessTokenType, personalAccessTokenMapping, append(wl, personalAccessTokenPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, values)
+	}
+	var result sql.Result
+	result, err = exec.ExecContext(ctx, cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update personal_access_tokens row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by update for personal_access_tokens")
+	}
+
+	if !cached {
+		personalAccessTokenUpdateCacheMut.Lock()
+		personalAccessTokenUpdateCache[key] = cache
+		personalAccessTokenUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(ctx, exec)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q personalAccessTokenQuery) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all for personal_access_tokens")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected for personal_access_tokens")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o PersonalAccessTokenSlice) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("models: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), personalAccessTokenPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE `personal_access_tokens` SET %s WHERE %s",
+		strmangle.SetParamNames("`", "`", 0, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 0, personalAccessTokenPrimaryKeyColumns, len(o)))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all in personalAccessToken slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected all in update all personalAccessToken")
+	}
+	return rowsAff, nil
+}
+
+var mySQLPersonalAccessTokenUniqueColumns = []string{
+	"id",
+	"token_hash",
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *PersonalAccessToken) Upsert(ctx context.Context, exec boil.ContextExecutor, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no personal_access_tokens provided for upsert")
+	}
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+		o.UpdatedAt = currTime
+	}
+
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(personalAccessTokenColumnsWithDefault, o)
+	nzUniques := queries.NonZeroDefaultSet(mySQLPersonalAccessTokenUniqueColumns, o)
+
+	if len(nzUniques) == 0 {
+		return errors.New("cannot upsert with a table that cannot conflict on a unique column")
+	}
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzUniques {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	personalAccessTokenUpsertCacheMut.RLock()
+	cache, cached := personalAccessTokenUpsertCache[key]
+	personalAccessTokenUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			personalAccessTokenAllColumns,
+			personalAccessTokenColumnsWithDefault,
+			personalAccessTokenColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		update := updateColumns.UpdateColumnSet(
+			personalAccessTokenAllColumns,
+			personalAccessTokenPrimaryKeyColumns,
+		)
+
+		if !updateColumns.IsNone() && len(update) == 0 {
+			return errors.New("models: unable to upsert personal_access_tokens, could not build update column list")
+		}
+
+		ret = strmangle.SetComplement(ret, nzUniques)
+		cache.query = buildUpsertQueryMySQL(dialect, "`personal_access_tokens`", update, insert)
+		cache.retQuery = fmt.Sprintf(
+			"SELECT %s FROM `personal_access_tokens` WHERE %s",
+			strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, ret), ","),
+			strmangle.WhereClause("`", "`", 0, nzUniques),
+		)
+
+		cache.valueMapping, err = queries.BindMapping(personalAccessTokenType, personalAccessTokenMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(personalAccessTokenType, personalAccessTokenMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	_, err = exec.ExecContext(ctx, cache.query, vals...)
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to upsert for personal_access_tokens")
+	}
+
+	var uniqueMap []uint64
+	var nzUniqueCols []interface{}
+
+	if len(cache.retMapping) == 0 {
+		goto CacheNoHooks
+	}
+
+	uniqueMap, err = queries.BindMapping(personalAccessTokenType, personalAccessTokenMapping, nzUniques)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to retrieve unique values for personal_access_tokens")
+	}
+	nzUniqueCols = queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), uniqueMap)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.retQuery)
+		fmt.Fprintln(writer, nzUniqueCols...)
+	}
+	err = exec.QueryRowContext(ctx, cache.retQuery, nzUniqueCols...).Scan(returns...)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to populate default values for personal_access_tokens")
+	}
+
+CacheNoHooks:
+	if !cached {
+		personalAccessTokenUpsertCacheMut.Lock()
+		personalAccessTokenUpsertCache[key] = cache
+		personalAccessTokenUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(ctx, exec)
+}
+
+// Delete deletes a single PersonalAccessToken record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *PersonalAccessToken) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("models: no PersonalAccessToken provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), personalAccessTokenPrimaryKeyMapping)
+	sql := "DELETE FROM `personal_access_tokens` WHERE `id`=?"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete from personal_access_tokens")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by delete for personal_access_tokens")
+	}
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all matching rows.
+func (q personalAccessTokenQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("models: no personalAccessTokenQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from personal_access_tokens")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for personal_access_tokens")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o PersonalAccessTokenSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(personalAccessTokenBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), personalAccessTokenPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM `personal_access_tokens` WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 0, personalAccessTokenPrimaryKeyColumns, len(o))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from personalAccessToken slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for personal_access_tokens")
+	}
+
+	if len(personalAccessTokenAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *PersonalAccessToken) Reload(ctx context.Context, exec boil.ContextExecutor) error {
+	ret, err := FindPersonalAccessToken(ctx, exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *PersonalAccessTokenSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := PersonalAccessTokenSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), personalAccessTokenPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT `personal_access_tokens`.* FROM `personal_access_tokens` WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 0, personalAccessTokenPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(ctx, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to reload all in PersonalAccessTokenSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// PersonalAccessTokenExists checks if the PersonalAccessToken row exists.
+func PersonalAccessTokenExists(ctx context.Context, exec boil.ContextExecutor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from `personal_access_tokens` where `id`=? limit 1)"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, iD)
+	}
+	row := exec.QueryRowContext(ctx, sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "models: unable to check if personal_access_tokens exists")
+	}
+
+	return exists, nil
+}
+
+// Exists checks if the PersonalAccessToken row exists.
+func (o *PersonalAccessToken) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	return PersonalAccessTokenExists(ctx, exec, o.ID)
+}
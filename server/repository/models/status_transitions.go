@@ -0,0 +1,1158 @@
+// Code generated by SQLBoiler 4.14.2 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// StatusTransition is an object representing the database table.
+type StatusTransition struct {
+	ID         string      `boil:"id" json:"id" toml:"id" yaml:"id"`
+	TaskID     string      `boil:"task_id" json:"task_id" toml:"task_id" yaml:"task_id"`
+	FromStatus null.String `boil:"from_status" json:"from_status,omitempty" toml:"from_status" yaml:"from_status,omitempty"`
+	ToStatus   string      `boil:"to_status" json:"to_status" toml:"to_status" yaml:"to_status"`
+	CreatedAt  time.Time   `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *statusTransitionR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L statusTransitionL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var StatusTransitionColumns = struct {
+	ID         string
+	TaskID     string
+	FromStatus string
+	ToStatus   string
+	CreatedAt  string
+}{
+	ID:         "id",
+	TaskID:     "task_id",
+	FromStatus: "from_status",
+	ToStatus:   "to_status",
+	CreatedAt:  "created_at",
+}
+
+var StatusTransitionTableColumns = struct {
+	ID         string
+	TaskID     string
+	FromStatus string
+	ToStatus   string
+	CreatedAt  string
+}{
+	ID:         "status_transitions.id",
+	TaskID:     "status_transitions.task_id",
+	FromStatus: "status_transitions.from_status",
+	ToStatus:   "status_transitions.to_status",
+	CreatedAt:  "status_transitions.created_at",
+}
+
+// Generated where
+
+var StatusTransitionWhere = struct {
+	ID         whereHelperstring
+	TaskID     whereHelperstring
+	FromStatus whereHelpernull_String
+	ToStatus   whereHelperstring
+	CreatedAt  whereHelpertime_Time
+}{
+	ID:         whereHelperstring{field: "`status_transitions`.`id`"},
+	TaskID:     whereHelperstring{field: "`status_transitions`.`task_id`"},
+	FromStatus: whereHelpernull_String{field: "`status_transitions`.`from_status`"},
+	ToStatus:   whereHelperstring{field: "`status_transitions`.`to_status`"},
+	CreatedAt:  whereHelpertime_Time{field: "`status_transitions`.`created_at`"},
+}
+
+// StatusTransitionRels is where relationship names are stored.
+var StatusTransitionRels = struct {
+	Task string
+}{
+	Task: "Task",
+}
+
+// statusTransitionR is where relationships are stored.
+type statusTransitionR struct {
+	Task *Task `boil:"Task" json:"Task" toml:"Task" yaml:"Task"`
+}
+
+// NewStruct creates a new relationship struct
+func (*statusTransitionR) NewStruct() *statusTransitionR {
+	return &statusTransitionR{}
+}
+
+func (r *statusTransitionR) GetTask() *Task {
+	if r == nil {
+		return nil
+	}
+	return r.Task
+}
+
+// statusTransitionL is where Load methods for each relationship are stored.
+type statusTransitionL struct{}
+
+var (
+	statusTransitionAllColumns            = []string{"id", "task_id", "from_status", "to_status", "created_at"}
+	statusTransitionColumnsWithoutDefault = []string{"id", "task_id", "from_status", "to_status"}
+	statusTransitionColumnsWithDefault    = []string{"created_at"}
+	statusTransitionPrimaryKeyColumns     = []string{"id"}
+	statusTransitionGeneratedColumns      = []string{}
+)
+
+type (
+	// StatusTransitionSlice is an alias for a slice of pointers to StatusTransition.
+	// This should almost always be used instead of []StatusTransition.
+	StatusTransitionSlice []*StatusTransition
+	// StatusTransitionHook is the signature for custom StatusTransition hook methods
+	StatusTransitionHook func(context.Context, boil.ContextExecutor, *StatusTransition) error
+
+	statusTransitionQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	statusTransitionType                 = reflect.TypeOf(&StatusTransition{})
+	statusTransitionMapping              = queries.MakeStructMapping(statusTransitionType)
+	statusTransitionPrimaryKeyMapping, _ = queries.BindMapping(statusTransitionType, statusTransitionMapping, statusTransitionPrimaryKeyColumns)
+	statusTransitionInsertCacheMut       sync.RWMutex
+	statusTransitionInsertCache          = make(map[string]insertCache)
+	statusTransitionUpdateCacheMut       sync.RWMutex
+	statusTransitionUpdateCache          = make(map[string]updateCache)
+	statusTransitionUpsertCacheMut       sync.RWMutex
+	statusTransitionUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var statusTransitionAfterSelectHooks []StatusTransitionHook
+
+var statusTransitionBeforeInsertHooks []StatusTransitionHook
+var statusTransitionAfterInsertHooks []StatusTransitionHook
+
+var statusTransitionBeforeUpdateHooks []StatusTransitionHook
+var statusTransitionAfterUpdateHooks []StatusTransitionHook
+
+var statusTransitionBeforeDeleteHooks []StatusTransitionHook
+var statusTransitionAfterDeleteHooks []StatusTransitionHook
+
+var statusTransitionBeforeUpsertHooks []StatusTransitionHook
+var statusTransitionAfterUpsertHooks []StatusTransitionHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *StatusTransition) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range statusTransitionAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *StatusTransition) doBeforeInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range statusTransitionBeforeInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *StatusTransition) doAfterInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range statusTransitionAfterInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *StatusTransition) doBeforeUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range statusTransitionBeforeUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *StatusTransition) doAfterUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range statusTransitionAfterUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *StatusTransition) doBeforeDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range statusTransitionBeforeDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *StatusTransition) doAfterDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range statusTransitionAfterDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *StatusTransition) doBeforeUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range statusTransitionBeforeUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *StatusTransition) doAfterUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range statusTransitionAfterUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddStatusTransitionHook registers your hook function for all future operations.
+func AddStatusTransitionHook(hookPoint boil.HookPoint, statusTransitionHook StatusTransitionHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		statusTransitionAfterSelectHooks = append(statusTransitionAfterSelectHooks, statusTransitionHook)
+	case boil.BeforeInsertHook:
+		statusTransitionBeforeInsertHooks = append(statusTransitionBeforeInsertHooks, statusTransitionHook)
+	case boil.AfterInsertHook:
+		statusTransitionAfterInsertHooks = append(statusTransitionAfterInsertHooks, statusTransitionHook)
+	case boil.BeforeUpdateHook:
+		statusTransitionBeforeUpdateHooks = append(statusTransitionBeforeUpdateHooks, statusTransitionHook)
+	case boil.AfterUpdateHook:
+		statusTransitionAfterUpdateHooks = append(statusTransitionAfterUpdateHooks, statusTransitionHook)
+	case boil.BeforeDeleteHook:
+		statusTransitionBeforeDeleteHooks = append(statusTransitionBeforeDeleteHooks, statusTransitionHook)
+	case boil.AfterDeleteHook:
+		statusTransitionAfterDeleteHooks = append(statusTransitionAfterDeleteHooks, statusTransitionHook)
+	case boil.BeforeUpsertHook:
+		statusTransitionBeforeUpsertHooks = append(statusTransitionBeforeUpsertHooks, statusTransitionHook)
+	case boil.AfterUpsertHook:
+		statusTransitionAfterUpsertHooks = append(statusTransitionAfterUpsertHooks, statusTransitionHook)
+	}
+}
+
+// One returns a single statusTransition record from the query.
+func (q statusTransitionQuery) One(ctx context.Context, exec boil.ContextExecutor) (*StatusTransition, error) {
+	o := &StatusTransition{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for status_transitions")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all StatusTransition records from the query.
+func (q statusTransitionQuery) All(ctx context.Context, exec boil.ContextExecutor) (StatusTransitionSlice, error) {
+	var o []*StatusTransition
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to StatusTransition slice")
+	}
+
+	if len(statusTransitionAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all StatusTransition records in the query.
+func (q statusTransitionQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count status_transitions rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q statusTransitionQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if status_transitions exists")
+	}
+
+	return count > 0, nil
+}
+
+// Task pointed to by the foreign key.
+func (o *StatusTransition) Task(mods ...qm.QueryMod) taskQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("`id` = ?", o.TaskID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return Tasks(queryMods...)
+}
+
+// LoadTask allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (statusTransitionL) LoadTask(ctx context.Context, e boil.ContextExecutor, singular bool, maybeStatusTransition interface{}, mods queries.Applicator) error {
+	var slice []*StatusTransition
+	var object *StatusTransition
+
+	if singular {
+		var ok bool
+		object, ok = maybeStatusTransition.(*StatusTransition)
+		if !ok {
+			object = new(StatusTransition)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeStatusTransition)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeStatusTransition))
+			}
+		}
+	} else {
+		s, ok := maybeStatusTransition.(*[]*StatusTransition)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeStatusTransition)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeStatusTransition))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &statusTransitionR{}
+		}
+		args = append(args, object.TaskID)
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &statusTransitionR{}
+			}
+
+			for _, a := range args {
+				if a == obj.TaskID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.TaskID)
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`tasks`),
+		qm.WhereIn(`tasks.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Task")
+	}
+
+	var resultSlice []*Task
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Task")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for tasks")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for tasks")
+	}
+
+	if len(taskAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Task = foreign
+		if foreign.R == nil {
+			foreign.R = &taskR{}
+		}
+		foreign.R.StatusTransitions = append(foreign.R.StatusTransitions, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.TaskID == foreign.ID {
+				local.R.Task = foreign
+				if foreign.R == nil {
+					foreign.R = &taskR{}
+				}
+				foreign.R.StatusTransitions = append(foreign.R.StatusTransitions, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetTask of the statusTransition to the related item.
+// Sets o.R.Task to related.
+// Adds o to related.R.StatusTransitions.
+func (o *StatusTransition) SetTask(ctx context.Context, exec boil.ContextExecutor, insert bool, related *Task) error {
+	var err error
+	if insert {
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE `status_transitions` SET %s WHERE %s",
+		strmangle.SetParamNames("`", "`", 0, []string{"task_id"}),
+		strmangle.WhereClause("`", "`", 0, statusTransitionPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, updateQuery)
+		fmt.Fprintln(writer, values)
+	}
+	if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.TaskID = related.ID
+	if o.R == nil {
+		o.R = &statusTransitionR{
+			Task: related,
+		}
+	} else {
+		o.R.Task = related
+	}
+
+	if related.R == nil {
+		related.R = &taskR{
+			StatusTransitions: StatusTransitionSlice{o},
+		}
+	} else {
+		related.R.StatusTransitions = append(related.R.StatusTransitions, o)
+	}
+
+	return nil
+}
+
+// StatusTransitions retrieves all the records using an executor.
+func StatusTransitions(mods ...qm.QueryMod) statusTransitionQuery {
+	mods = append(mods, qm.From("`status_transitions`"))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"`status_transitions`.*"})
+	}
+
+	return statusTransitionQuery{q}
+}
+
+// FindStatusTransition retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindStatusTransition(ctx context.Context, exec boil.ContextExecutor, iD string, selectCols ...string) (*StatusTransition, error) {
+	statusTransitionObj := &StatusTransition{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from `status_transitions` where `id`=?", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(ctx, exec, statusTransitionObj)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: unable to select from status_transitions")
+	}
+
+	if err = statusTransitionObj.doAfterSelectHooks(ctx, exec); err != nil {
+		return statusTransitionObj, err
+	}
+
+	return statusTransitionObj, nil
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *StatusTransition) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no status_transitions provided for insertion")
+	}
+
+	var err error
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(statusTransitionColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	statusTransitionInsertCacheMut.RLock()
+	cache, cached := statusTransitionInsertCache[key]
+	statusTransitionInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			statusTransitionAllColumns,
+			statusTransitionColumnsWithDefault,
+			statusTransitionColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(statusTransitionType, statusTransitionMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(statusTransitionType, statusTransitionMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO `status_transitions` (`%s`) %%sVALUES (%s)%%s", strings.Join(wl, "`,`"), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO `status_transitions` () VALUES ()%s%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			cache.retQuery = fmt.Sprintf("SELECT `%s` FROM `status_transitions` WHERE %s", strings.Join(returnColumns, "`,`"), strmangle.WhereClause("`", "`", 0, statusTransitionPrimaryKeyColumns))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	_, err = exec.ExecContext(ctx, cache.query, vals...)
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to insert into status_transitions")
+	}
+
+	var identifierCols []interface{}
+
+	if len(cache.retMapping) == 0 {
+		goto CacheNoHooks
+	}
+
+	identifierCols = []interface{}{
+		o.ID,
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.retQuery)
+		fmt.Fprintln(writer, identifierCols...)
+	}
+	err = exec.QueryRowContext(ctx, cache.retQuery, identifierCols...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to populate default values for status_transitions")
+	}
+
+CacheNoHooks:
+	if !cached {
+		statusTransitionInsertCacheMut.Lock()
+		statusTransitionInsertCache[key] = cache
+		statusTransitionInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(ctx, exec)
+}
+
+// Update uses an executor to update the StatusTransition.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *StatusTransition) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	statusTransitionUpdateCacheMut.RLock()
+	cache, cached := statusTransitionUpdateCache[key]
+	statusTransitionUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			statusTransitionAllColumns,
+			statusTransitionPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("models: unable to update status_transitions, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE `status_transitions` SET %s WHERE %s",
+			strmangle.SetParamNames("`", "`", 0, wl),
+			strmangle.WhereClause("`", "`", 0, statusTransitionPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(statusTransitionType, statusTransitionMapping, append(wl, statusTransitionPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, values)
+	}
+	var result sql.Result
+	result, err = exec.ExecContext(ctx, cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update status_transitions row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by update for status_transitions")
+	}
+
+	if !cached {
+		statusTransitionUpdateCacheMut.Lock()
+		statusTransitionUpdateCache[key] = cache
+		statusTransitionUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(ctx, exec)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q statusTransitionQuery) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all for status_transitions")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected for status_transitions")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o StatusTransitionSlice) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("models: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), statusTransitionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE `status_transitions` SET %s WHERE %s",
+		strmangle.SetParamNames("`", "`", 0, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 0, statusTransitionPrimaryKeyColumns, len(o)))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all in statusTransition slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected all in update all statusTransition")
+	}
+	return rowsAff, nil
+}
+
+var mySQLStatusTransitionUniqueColumns = []string{
+	"id",
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *StatusTransition) Upsert(ctx context.Context, exec boil.ContextExecutor, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no status_transitions provided for upsert")
+	}
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(statusTransitionColumnsWithDefault, o)
+	nzUniques := queries.NonZeroDefaultSet(mySQLStatusTransitionUniqueColumns, o)
+
+	if len(nzUniques) == 0 {
+		return errors.New("cannot upsert with a table that cannot conflict on a unique column")
+	}
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzUniques {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	statusTransitionUpsertCacheMut.RLock()
+	cache, cached := statusTransitionUpsertCache[key]
+	statusTransitionUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			statusTransitionAllColumns,
+			statusTransitionColumnsWithDefault,
+			statusTransitionColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		update := updateColumns.UpdateColumnSet(
+			statusTransitionAllColumns,
+			statusTransitionPrimaryKeyColumns,
+		)
+
+		if !updateColumns.IsNone() && len(update) == 0 {
+			return errors.New("models: unable to upsert status_transitions, could not build update column list")
+		}
+
+		ret = strmangle.SetComplement(ret, nzUniques)
+		cache.query = buildUpsertQueryMySQL(dialect, "`status_transitions`", update, insert)
+		cache.retQuery = fmt.Sprintf(
+			"SELECT %s FROM `status_transitions` WHERE %s",
+			strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, ret), ","),
+			strmangle.WhereClause("`", "`", 0, nzUniques),
+		)
+
+		cache.valueMapping, err = queries.BindMapping(statusTransitionType, statusTransitionMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(statusTransitionType, statusTransitionMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	_, err = exec.ExecContext(ctx, cache.query, vals...)
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to upsert for status_transitions")
+	}
+
+	var uniqueMap []uint64
+	var nzUniqueCols []interface{}
+
+	if len(cache.retMapping) == 0 {
+		goto CacheNoHooks
+	}
+
+	uniqueMap, err = queries.BindMapping(statusTransitionType, statusTransitionMapping, nzUniques)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to retrieve unique values for status_transitions")
+	}
+	nzUniqueCols = queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), uniqueMap)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.retQuery)
+		fmt.Fprintln(writer, nzUniqueCols...)
+	}
+	err = exec.QueryRowContext(ctx, cache.retQuery, nzUniqueCols...).Scan(returns...)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to populate default values for status_transitions")
+	}
+
+CacheNoHooks:
+	if !cached {
+		statusTransitionUpsertCacheMut.Lock()
+		statusTransitionUpsertCache[key] = cache
+		statusTransitionUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(ctx, exec)
+}
+
+// Delete deletes a single StatusTransition record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *StatusTransition) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("models: no StatusTransition provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), statusTransitionPrimaryKeyMapping)
+	sql := "DELETE FROM `status_transitions` WHERE `id`=?"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete from status_transitions")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by delete for status_transitions")
+	}
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all matching rows.
+func (q statusTransitionQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("models: no statusTransitionQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from status_transitions")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for status_transitions")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o StatusTransitionSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(statusTransitionBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), statusTransitionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM `status_transitions` WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 0, statusTransitionPrimaryKeyColumns, len(o))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from statusTransition slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for status_transitions")
+	}
+
+	if len(statusTransitionAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *StatusTransition) Reload(ctx context.Context, exec boil.ContextExecutor) error {
+	ret, err := FindStatusTransition(ctx, exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *StatusTransitionSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := StatusTransitionSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), statusTransitionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT `status_transitions`.* FROM `status_transitions` WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 0, statusTransitionPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(ctx, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to reload all in StatusTransitionSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// StatusTransitionExists checks if the StatusTransition row exists.
+func StatusTransitionExists(ctx context.Context, exec boil.ContextExecutor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from `status_transitions` where `id`=? limit 1)"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, iD)
+	}
+	row := exec.QueryRowContext(ctx, sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "models: unable to check if status_transitions exists")
+	}
+
+	return exists, nil
+}
+
+// Exists checks if the StatusTransition row exists.
+func (o *StatusTransition) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	return StatusTransitionExists(ctx, exec, o.ID)
+}
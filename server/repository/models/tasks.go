@@ -68,50 +68,6 @@ var TaskTableColumns = struct {
 
 // Generated where
 
-type whereHelperstring struct{ field string }
-
-func (w whereHelperstring) EQ(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
-func (w whereHelperstring) NEQ(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
-func (w whereHelperstring) LT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
-func (w whereHelperstring) LTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
-func (w whereHelperstring) GT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
-func (w whereHelperstring) GTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
-func (w whereHelperstring) IN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelperstring) NIN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
-}
-
-type whereHelpertime_Time struct{ field string }
-
-func (w whereHelpertime_Time) EQ(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.EQ, x)
-}
-func (w whereHelpertime_Time) NEQ(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.NEQ, x)
-}
-func (w whereHelpertime_Time) LT(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LT, x)
-}
-func (w whereHelpertime_Time) LTE(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LTE, x)
-}
-func (w whereHelpertime_Time) GT(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GT, x)
-}
-func (w whereHelpertime_Time) GTE(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GTE, x)
-}
-
 var TaskWhere = struct {
 	ID        whereHelperstring
 	Text      whereHelperstring
@@ -130,17 +86,23 @@ var TaskWhere = struct {
 
 // TaskRels is where relationship names are stored.
 var TaskRels = struct {
-	User  string
-	Todos string
+	User              string
+	Activities        string
+	StatusTransitions string
+	Todos             string
 }{
-	User:  "User",
-	Todos: "Todos",
+	User:              "User",
+	Activities:        "Activities",
+	StatusTransitions: "StatusTransitions",
+	Todos:             "Todos",
 }
 
 // taskR is where relationships are stored.
 type taskR struct {
-	User  *User     `boil:"User" json:"User" toml:"User" yaml:"User"`
-	Todos TodoSlice `boil:"Todos" json:"Todos" toml:"Todos" yaml:"Todos"`
+	User              *User                 `boil:"User" json:"User" toml:"User" yaml:"User"`
+	Activities        ActivitySlice         `boil:"Activities" json:"Activities" toml:"Activities" yaml:"Activities"`
+	StatusTransitions StatusTransitionSlice `boil:"StatusTransitions" json:"StatusTransitions" toml:"StatusTransitions" yaml:"StatusTransitions"`
+	Todos             TodoSlice             `boil:"Todos" json:"Todos" toml:"Todos" yaml:"Todos"`
 }
 
 // NewStruct creates a new relationship struct
@@ -155,6 +117,20 @@ func (r *taskR) GetUser() *User {
 	return r.User
 }
 
+func (r *taskR) GetActivities() ActivitySlice {
+	if r == nil {
+		return nil
+	}
+	return r.Activities
+}
+
+func (r *taskR) GetStatusTransitions() StatusTransitionSlice {
+	if r == nil {
+		return nil
+	}
+	return r.StatusTransitions
+}
+
 func (r *taskR) GetTodos() TodoSlice {
 	if r == nil {
 		return nil
@@ -462,6 +438,34 @@ func (o *Task) User(mods ...qm.QueryMod) userQuery {
 	return Users(queryMods...)
 }
 
+// Activities retrieves all the activity's Activities with an executor.
+func (o *Task) Activities(mods ...qm.QueryMod) activityQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("`activities`.`task_id`=?", o.ID),
+	)
+
+	return Activities(queryMods...)
+}
+
+// StatusTransitions retrieves all the status_transition's StatusTransitions with an executor.
+func (o *Task) StatusTransitions(mods ...qm.QueryMod) statusTransitionQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("`status_transitions`.`task_id`=?", o.ID),
+	)
+
+	return StatusTransitions(queryMods...)
+}
+
 // Todos retrieves all the todo's Todos with an executor.
 func (o *Task) Todos(mods ...qm.QueryMod) todoQuery {
 	var queryMods []qm.QueryMod
@@ -596,6 +600,234 @@ func (taskL) LoadUser(ctx context.Context, e boil.ContextExecutor, singular bool
 	return nil
 }
 
+// LoadActivities allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (taskL) LoadActivities(ctx context.Context, e boil.ContextExecutor, singular bool, maybeTask interface{}, mods queries.Applicator) error {
+	var slice []*Task
+	var object *Task
+
+	if singular {
+		var ok bool
+		object, ok = maybeTask.(*Task)
+		if !ok {
+			object = new(Task)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeTask)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeTask))
+			}
+		}
+	} else {
+		s, ok := maybeTask.(*[]*Task)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeTask)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeTask))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &taskR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &taskR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ID) {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`activities`),
+		qm.WhereIn(`activities.task_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load activities")
+	}
+
+	var resultSlice []*Activity
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice activities")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on activities")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for activities")
+	}
+
+	if len(activityAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.Activities = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &activityR{}
+			}
+			foreign.R.Task = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if queries.Equal(local.ID, foreign.TaskID) {
+				local.R.Activities = append(local.R.Activities, foreign)
+				if foreign.R == nil {
+					foreign.R = &activityR{}
+				}
+				foreign.R.Task = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadStatusTransitions allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (taskL) LoadStatusTransitions(ctx context.Context, e boil.ContextExecutor, singular bool, maybeTask interface{}, mods queries.Applicator) error {
+	var slice []*Task
+	var object *Task
+
+	if singular {
+		var ok bool
+		object, ok = maybeTask.(*Task)
+		if !ok {
+			object = new(Task)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeTask)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeTask))
+			}
+		}
+	} else {
+		s, ok := maybeTask.(*[]*Task)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeTask)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeTask))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &taskR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &taskR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`status_transitions`),
+		qm.WhereIn(`status_transitions.task_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load status_transitions")
+	}
+
+	var resultSlice []*StatusTransition
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice status_transitions")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on status_transitions")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for status_transitions")
+	}
+
+	if len(statusTransitionAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.StatusTransitions = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &statusTransitionR{}
+			}
+			foreign.R.Task = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.TaskID {
+				local.R.StatusTransitions = append(local.R.StatusTransitions, foreign)
+				if foreign.R == nil {
+					foreign.R = &statusTransitionR{}
+				}
+				foreign.R.Task = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // LoadTodos allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for a 1-M or N-M relationship.
 func (taskL) LoadTodos(ctx context.Context, e boil.ContextExecutor, singular bool, maybeTask interface{}, mods queries.Applicator) error {
@@ -757,6 +989,186 @@ func (o *Task) SetUser(ctx context.Context, exec boil.ContextExecutor, insert bo
 	return nil
 }
 
+// AddActivities adds the given related objects to the existing relationships
+// of the task, optionally inserting them as new records.
+// Appends related to o.R.Activities.
+// Sets related.R.Task appropriately.
+func (o *Task) AddActivities(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Activity) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			queries.Assign(&rel.TaskID, o.ID)
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE `activities` SET %s WHERE %s",
+				strmangle.SetParamNames("`", "`", 0, []string{"task_id"}),
+				strmangle.WhereClause("`", "`", 0, activityPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			queries.Assign(&rel.TaskID, o.ID)
+		}
+	}
+
+	if o.R == nil {
+		o.R = &taskR{
+			Activities: related,
+		}
+	} else {
+		o.R.Activities = append(o.R.Activities, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &activityR{
+				Task: o,
+			}
+		} else {
+			rel.R.Task = o
+		}
+	}
+	return nil
+}
+
+// SetActivities removes all previously related items of the
+// task replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Task's Activities accordingly.
+// Replaces o.R.Activities with related.
+// Sets related.R.Task's Activities accordingly.
+func (o *Task) SetActivities(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Activity) error {
+	query := "update `activities` set `task_id` = null where `task_id` = ?"
+	values := []interface{}{o.ID}
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, query)
+		fmt.Fprintln(writer, values)
+	}
+	_, err := exec.ExecContext(ctx, query, values...)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove relationships before set")
+	}
+
+	if o.R != nil {
+		for _, rel := range o.R.Activities {
+			queries.SetScanner(&rel.TaskID, nil)
+			if rel.R == nil {
+				continue
+			}
+
+			rel.R.Task = nil
+		}
+		o.R.Activities = nil
+	}
+
+	return o.AddActivities(ctx, exec, insert, related...)
+}
+
+// RemoveActivities relationships from objects passed in.
+// Removes related items from R.Activities (uses pointer comparison, removal does not keep order)
+// Sets related.R.Task.
+func (o *Task) RemoveActivities(ctx context.Context, exec boil.ContextExecutor, related ...*Activity) error {
+	if len(related) == 0 {
+		return nil
+	}
+
+	var err error
+	for _, rel := range related {
+		queries.SetScanner(&rel.TaskID, nil)
+		if rel.R != nil {
+			rel.R.Task = nil
+		}
+		if _, err = rel.Update(ctx, exec, boil.Whitelist("task_id")); err != nil {
+			return err
+		}
+	}
+	if o.R == nil {
+		return nil
+	}
+
+	for _, rel := range related {
+		for i, ri := range o.R.Activities {
+			if rel != ri {
+				continue
+			}
+
+			ln := len(o.R.Activities)
+			if ln > 1 && i < ln-1 {
+				o.R.Activities[i] = o.R.Activities[ln-1]
+			}
+			o.R.Activities = o.R.Activities[:ln-1]
+			break
+		}
+	}
+
+	return nil
+}
+
+// AddStatusTransitions adds the given related objects to the existing relationships
+// of the task, optionally inserting them as new records.
+// Appends related to o.R.StatusTransitions.
+// Sets related.R.Task appropriately.
+func (o *Task) AddStatusTransitions(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*StatusTransition) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.TaskID = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE `status_transitions` SET %s WHERE %s",
+				strmangle.SetParamNames("`", "`", 0, []string{"task_id"}),
+				strmangle.WhereClause("`", "`", 0, statusTransitionPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.TaskID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &taskR{
+			StatusTransitions: related,
+		}
+	} else {
+		o.R.StatusTransitions = append(o.R.StatusTransitions, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &statusTransitionR{
+				Task: o,
+			}
+		} else {
+			rel.R.Task = o
+		}
+	}
+	return nil
+}
+
 // AddTodos adds the given related objects to the existing relationships
 // of the task, optionally inserting them as new records.
 // Appends related to o.R.Todos.
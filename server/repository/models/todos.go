@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
@@ -23,12 +24,14 @@ import (
 
 // Todo is an object representing the database table.
 type Todo struct {
-	ID        string    `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Text      string    `boil:"text" json:"text" toml:"text" yaml:"text"`
-	Done      bool      `boil:"done" json:"done" toml:"done" yaml:"done"`
-	TaskID    string    `boil:"task_id" json:"task_id" toml:"task_id" yaml:"task_id"`
-	CreatedAt time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
-	UpdatedAt time.Time `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	ID        string      `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Text      string      `boil:"text" json:"text" toml:"text" yaml:"text"`
+	Done      bool        `boil:"done" json:"done" toml:"done" yaml:"done"`
+	TaskID    string      `boil:"task_id" json:"task_id" toml:"task_id" yaml:"task_id"`
+	ParentID  null.String `boil:"parent_id" json:"parent_id,omitempty" toml:"parent_id" yaml:"parent_id,omitempty"`
+	Position  int         `boil:"position" json:"position" toml:"position" yaml:"position"`
+	CreatedAt time.Time   `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt time.Time   `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
 
 	R *todoR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L todoL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -39,6 +42,8 @@ var TodoColumns = struct {
 	Text      string
 	Done      string
 	TaskID    string
+	ParentID  string
+	Position  string
 	CreatedAt string
 	UpdatedAt string
 }{
@@ -46,6 +51,8 @@ var TodoColumns = struct {
 	Text:      "text",
 	Done:      "done",
 	TaskID:    "task_id",
+	ParentID:  "parent_id",
+	Position:  "position",
 	CreatedAt: "created_at",
 	UpdatedAt: "updated_at",
 }
@@ -55,6 +62,8 @@ var TodoTableColumns = struct {
 	Text      string
 	Done      string
 	TaskID    string
+	ParentID  string
+	Position  string
 	CreatedAt string
 	UpdatedAt string
 }{
@@ -62,6 +71,8 @@ var TodoTableColumns = struct {
 	Text:      "todos.text",
 	Done:      "todos.done",
 	TaskID:    "todos.task_id",
+	ParentID:  "todos.parent_id",
+	Position:  "todos.position",
 	CreatedAt: "todos.created_at",
 	UpdatedAt: "todos.updated_at",
 }
@@ -77,11 +88,36 @@ func (w whereHelperbool) LTE(x bool) qm.QueryMod { return qmhelper.Where(w.field
 func (w whereHelperbool) GT(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
 func (w whereHelperbool) GTE(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
 
+type whereHelperint struct{ field string }
+
+func (w whereHelperint) EQ(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
+func (w whereHelperint) NEQ(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
+func (w whereHelperint) LT(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
+func (w whereHelperint) LTE(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
+func (w whereHelperint) GT(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
+func (w whereHelperint) GTE(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
+func (w whereHelperint) IN(slice []int) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperint) NIN(slice []int) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
 var TodoWhere = struct {
 	ID        whereHelperstring
 	Text      whereHelperstring
 	Done      whereHelperbool
 	TaskID    whereHelperstring
+	ParentID  whereHelpernull_String
+	Position  whereHelperint
 	CreatedAt whereHelpertime_Time
 	UpdatedAt whereHelpertime_Time
 }{
@@ -89,20 +125,28 @@ var TodoWhere = struct {
 	Text:      whereHelperstring{field: "`todos`.`text`"},
 	Done:      whereHelperbool{field: "`todos`.`done`"},
 	TaskID:    whereHelperstring{field: "`todos`.`task_id`"},
+	ParentID:  whereHelpernull_String{field: "`todos`.`parent_id`"},
+	Position:  whereHelperint{field: "`todos`.`position`"},
 	CreatedAt: whereHelpertime_Time{field: "`todos`.`created_at`"},
 	UpdatedAt: whereHelpertime_Time{field: "`todos`.`updated_at`"},
 }
 
 // TodoRels is where relationship names are stored.
 var TodoRels = struct {
-	Task string
+	Task        string
+	Parent      string
+	ParentTodos string
 }{
-	Task: "Task",
+	Task:        "Task",
+	Parent:      "Parent",
+	ParentTodos: "ParentTodos",
 }
 
 // todoR is where relationships are stored.
 type todoR struct {
-	Task *Task `boil:"Task" json:"Task" toml:"Task" yaml:"Task"`
+	Task        *Task     `boil:"Task" json:"Task" toml:"Task" yaml:"Task"`
+	Parent      *Todo     `boil:"Parent" json:"Parent" toml:"Parent" yaml:"Parent"`
+	ParentTodos TodoSlice `boil:"ParentTodos" json:"ParentTodos" toml:"ParentTodos" yaml:"ParentTodos"`
 }
 
 // NewStruct creates a new relationship struct
@@ -117,13 +161,27 @@ func (r *todoR) GetTask() *Task {
 	return r.Task
 }
 
+func (r *todoR) GetParent() *Todo {
+	if r == nil {
+		return nil
+	}
+	return r.Parent
+}
+
+func (r *todoR) GetParentTodos() TodoSlice {
+	if r == nil {
+		return nil
+	}
+	return r.ParentTodos
+}
+
 // todoL is where Load methods for each relationship are stored.
 type todoL struct{}
 
 var (
-	todoAllColumns            = []string{"id", "text", "done", "task_id", "created_at", "updated_at"}
-	todoColumnsWithoutDefault = []string{"id", "text", "done", "task_id"}
-	todoColumnsWithDefault    = []string{"created_at", "updated_at"}
+	todoAllColumns            = []string{"id", "text", "done", "task_id", "parent_id", "position", "created_at", "updated_at"}
+	todoColumnsWithoutDefault = []string{"id", "text", "done", "task_id", "parent_id"}
+	todoColumnsWithDefault    = []string{"position", "created_at", "updated_at"}
 	todoPrimaryKeyColumns     = []string{"id"}
 	todoGeneratedColumns      = []string{}
 )
@@ -417,6 +475,31 @@ func (o *Todo) Task(mods ...qm.QueryMod) taskQuery {
 	return Tasks(queryMods...)
 }
 
+// Parent pointed to by the foreign key.
+func (o *Todo) Parent(mods ...qm.QueryMod) todoQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("`id` = ?", o.ParentID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return Todos(queryMods...)
+}
+
+// ParentTodos retrieves all the todo's Todos with an executor via parent_id column.
+func (o *Todo) ParentTodos(mods ...qm.QueryMod) todoQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("`todos`.`parent_id`=?", o.ID),
+	)
+
+	return Todos(queryMods...)
+}
+
 // LoadTask allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for an N-1 relationship.
 func (todoL) LoadTask(ctx context.Context, e boil.ContextExecutor, singular bool, maybeTodo interface{}, mods queries.Applicator) error {
@@ -537,6 +620,244 @@ func (todoL) LoadTask(ctx context.Context, e boil.ContextExecutor, singular bool
 	return nil
 }
 
+// LoadParent allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (todoL) LoadParent(ctx context.Context, e boil.ContextExecutor, singular bool, maybeTodo interface{}, mods queries.Applicator) error {
+	var slice []*Todo
+	var object *Todo
+
+	if singular {
+		var ok bool
+		object, ok = maybeTodo.(*Todo)
+		if !ok {
+			object = new(Todo)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeTodo)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeTodo))
+			}
+		}
+	} else {
+		s, ok := maybeTodo.(*[]*Todo)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeTodo)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeTodo))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &todoR{}
+		}
+		if !queries.IsNil(object.ParentID) {
+			args = append(args, object.ParentID)
+		}
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &todoR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ParentID) {
+					continue Outer
+				}
+			}
+
+			if !queries.IsNil(obj.ParentID) {
+				args = append(args, obj.ParentID)
+			}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`todos`),
+		qm.WhereIn(`todos.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Todo")
+	}
+
+	var resultSlice []*Todo
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Todo")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for todos")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for todos")
+	}
+
+	if len(todoAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Parent = foreign
+		if foreign.R == nil {
+			foreign.R = &todoR{}
+		}
+		foreign.R.ParentTodos = append(foreign.R.ParentTodos, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if queries.Equal(local.ParentID, foreign.ID) {
+				local.R.Parent = foreign
+				if foreign.R == nil {
+					foreign.R = &todoR{}
+				}
+				foreign.R.ParentTodos = append(foreign.R.ParentTodos, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadParentTodos allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (todoL) LoadParentTodos(ctx context.Context, e boil.ContextExecutor, singular bool, maybeTodo interface{}, mods queries.Applicator) error {
+	var slice []*Todo
+	var object *Todo
+
+	if singular {
+		var ok bool
+		object, ok = maybeTodo.(*Todo)
+		if !ok {
+			object = new(Todo)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeTodo)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeTodo))
+			}
+		}
+	} else {
+		s, ok := maybeTodo.(*[]*Todo)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeTodo)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeTodo))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &todoR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &todoR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ID) {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`todos`),
+		qm.WhereIn(`todos.parent_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load todos")
+	}
+
+	var resultSlice []*Todo
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice todos")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on todos")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for todos")
+	}
+
+	if len(todoAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.ParentTodos = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &todoR{}
+			}
+			foreign.R.Parent = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if queries.Equal(local.ID, foreign.ParentID) {
+				local.R.ParentTodos = append(local.R.ParentTodos, foreign)
+				if foreign.R == nil {
+					foreign.R = &todoR{}
+				}
+				foreign.R.Parent = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // SetTask of the todo to the related item.
 // Sets o.R.Task to related.
 // Adds o to related.R.Todos.
@@ -584,6 +905,213 @@ func (o *Todo) SetTask(ctx context.Context, exec boil.ContextExecutor, insert bo
 	return nil
 }
 
+// SetParent of the todo to the related item.
+// Sets o.R.Parent to related.
+// Adds o to related.R.ParentTodos.
+func (o *Todo) SetParent(ctx context.Context, exec boil.ContextExecutor, insert bool, related *Todo) error {
+	var err error
+	if insert {
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE `todos` SET %s WHERE %s",
+		strmangle.SetParamNames("`", "`", 0, []string{"parent_id"}),
+		strmangle.WhereClause("`", "`", 0, todoPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, updateQuery)
+		fmt.Fprintln(writer, values)
+	}
+	if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	queries.Assign(&o.ParentID, related.ID)
+	if o.R == nil {
+		o.R = &todoR{
+			Parent: related,
+		}
+	} else {
+		o.R.Parent = related
+	}
+
+	if related.R == nil {
+		related.R = &todoR{
+			ParentTodos: TodoSlice{o},
+		}
+	} else {
+		related.R.ParentTodos = append(related.R.ParentTodos, o)
+	}
+
+	return nil
+}
+
+// RemoveParent relationship.
+// Sets o.R.Parent to nil.
+// Removes o from all passed in related items' relationships struct.
+func (o *Todo) RemoveParent(ctx context.Context, exec boil.ContextExecutor, related *Todo) error {
+	var err error
+
+	queries.SetScanner(&o.ParentID, nil)
+	if _, err = o.Update(ctx, exec, boil.Whitelist("parent_id")); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	if o.R != nil {
+		o.R.Parent = nil
+	}
+	if related == nil || related.R == nil {
+		return nil
+	}
+
+	for i, ri := range related.R.ParentTodos {
+		if queries.Equal(o.ParentID, ri.ParentID) {
+			continue
+		}
+
+		ln := len(related.R.ParentTodos)
+		if ln > 1 && i < ln-1 {
+			related.R.ParentTodos[i] = related.R.ParentTodos[ln-1]
+		}
+		related.R.ParentTodos = related.R.ParentTodos[:ln-1]
+		break
+	}
+	return nil
+}
+
+// AddParentTodos adds the given related objects to the existing relationships
+// of the todo, optionally inserting them as new records.
+// Appends related to o.R.ParentTodos.
+// Sets related.R.Parent appropriately.
+func (o *Todo) AddParentTodos(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Todo) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			queries.Assign(&rel.ParentID, o.ID)
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE `todos` SET %s WHERE %s",
+				strmangle.SetParamNames("`", "`", 0, []string{"parent_id"}),
+				strmangle.WhereClause("`", "`", 0, todoPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			queries.Assign(&rel.ParentID, o.ID)
+		}
+	}
+
+	if o.R == nil {
+		o.R = &todoR{
+			ParentTodos: related,
+		}
+	} else {
+		o.R.ParentTodos = append(o.R.ParentTodos, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &todoR{
+				Parent: o,
+			}
+		} else {
+			rel.R.Parent = o
+		}
+	}
+	return nil
+}
+
+// SetParentTodos removes all previously related items of the
+// todo replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Parent's ParentTodos accordingly.
+// Replaces o.R.ParentTodos with related.
+// Sets related.R.Parent's ParentTodos accordingly.
+func (o *Todo) SetParentTodos(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Todo) error {
+	query := "update `todos` set `parent_id` = null where `parent_id` = ?"
+	values := []interface{}{o.ID}
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, query)
+		fmt.Fprintln(writer, values)
+	}
+	_, err := exec.ExecContext(ctx, query, values...)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove relationships before set")
+	}
+
+	if o.R != nil {
+		for _, rel := range o.R.ParentTodos {
+			queries.SetScanner(&rel.ParentID, nil)
+			if rel.R == nil {
+				continue
+			}
+
+			rel.R.Parent = nil
+		}
+		o.R.ParentTodos = nil
+	}
+
+	return o.AddParentTodos(ctx, exec, insert, related...)
+}
+
+// RemoveParentTodos relationships from objects passed in.
+// Removes related items from R.ParentTodos (uses pointer comparison, removal does not keep order)
+// Sets related.R.Parent.
+func (o *Todo) RemoveParentTodos(ctx context.Context, exec boil.ContextExecutor, related ...*Todo) error {
+	if len(related) == 0 {
+		return nil
+	}
+
+	var err error
+	for _, rel := range related {
+		queries.SetScanner(&rel.ParentID, nil)
+		if rel.R != nil {
+			rel.R.Parent = nil
+		}
+		if _, err = rel.Update(ctx, exec, boil.Whitelist("parent_id")); err != nil {
+			return err
+		}
+	}
+	if o.R == nil {
+		return nil
+	}
+
+	for _, rel := range related {
+		for i, ri := range o.R.ParentTodos {
+			if rel != ri {
+				continue
+			}
+
+			ln := len(o.R.ParentTodos)
+			if ln > 1 && i < ln-1 {
+				o.R.ParentTodos[i] = o.R.ParentTodos[ln-1]
+			}
+			o.R.ParentTodos = o.R.ParentTodos[:ln-1]
+			break
+		}
+	}
+
+	return nil
+}
+
 // Todos retrieves all the records using an executor.
 func Todos(mods ...qm.QueryMod) todoQuery {
 	mods = append(mods, qm.From("`todos`"))
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
@@ -23,10 +24,13 @@ import (
 
 // User is an object representing the database table.
 type User struct {
-	ID        string    `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Name      string    `boil:"name" json:"name" toml:"name" yaml:"name"`
-	CreatedAt time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
-	UpdatedAt time.Time `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	ID        string      `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Name      string      `boil:"name" json:"name" toml:"name" yaml:"name"`
+	Email     null.String `boil:"email" json:"email,omitempty" toml:"email" yaml:"email,omitempty"`
+	AvatarURL null.String `boil:"avatar_url" json:"avatar_url,omitempty" toml:"avatar_url" yaml:"avatar_url,omitempty"`
+	Role      string      `boil:"role" json:"role" toml:"role" yaml:"role"`
+	CreatedAt time.Time   `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt time.Time   `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
 
 	R *userR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L userL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -35,11 +39,17 @@ type User struct {
 var UserColumns = struct {
 	ID        string
 	Name      string
+	Email     string
+	AvatarURL string
+	Role      string
 	CreatedAt string
 	UpdatedAt string
 }{
 	ID:        "id",
 	Name:      "name",
+	Email:     "email",
+	AvatarURL: "avatar_url",
+	Role:      "role",
 	CreatedAt: "created_at",
 	UpdatedAt: "updated_at",
 }
@@ -47,11 +57,17 @@ var UserColumns = struct {
 var UserTableColumns = struct {
 	ID        string
 	Name      string
+	Email     string
+	AvatarURL string
+	Role      string
 	CreatedAt string
 	UpdatedAt string
 }{
 	ID:        "users.id",
 	Name:      "users.name",
+	Email:     "users.email",
+	AvatarURL: "users.avatar_url",
+	Role:      "users.role",
 	CreatedAt: "users.created_at",
 	UpdatedAt: "users.updated_at",
 }
@@ -61,25 +77,37 @@ var UserTableColumns = struct {
 var UserWhere = struct {
 	ID        whereHelperstring
 	Name      whereHelperstring
+	Email     whereHelpernull_String
+	AvatarURL whereHelpernull_String
+	Role      whereHelperstring
 	CreatedAt whereHelpertime_Time
 	UpdatedAt whereHelpertime_Time
 }{
 	ID:        whereHelperstring{field: "`users`.`id`"},
 	Name:      whereHelperstring{field: "`users`.`name`"},
+	Email:     whereHelpernull_String{field: "`users`.`email`"},
+	AvatarURL: whereHelpernull_String{field: "`users`.`avatar_url`"},
+	Role:      whereHelperstring{field: "`users`.`role`"},
 	CreatedAt: whereHelpertime_Time{field: "`users`.`created_at`"},
 	UpdatedAt: whereHelpertime_Time{field: "`users`.`updated_at`"},
 }
 
 // UserRels is where relationship names are stored.
 var UserRels = struct {
-	Tasks string
+	Activities           string
+	PersonalAccessTokens string
+	Tasks                string
 }{
-	Tasks: "Tasks",
+	Activities:           "Activities",
+	PersonalAccessTokens: "PersonalAccessTokens",
+	Tasks:                "Tasks",
 }
 
 // userR is where relationships are stored.
 type userR struct {
-	Tasks TaskSlice `boil:"Tasks" json:"Tasks" toml:"Tasks" yaml:"Tasks"`
+	Activities           ActivitySlice            `boil:"Activities" json:"Activities" toml:"Activities" yaml:"Activities"`
+	PersonalAccessTokens PersonalAccessTokenSlice `boil:"PersonalAccessTokens" json:"PersonalAccessTokens" toml:"PersonalAccessTokens" yaml:"PersonalAccessTokens"`
+	Tasks                TaskSlice                `boil:"Tasks" json:"Tasks" toml:"Tasks" yaml:"Tasks"`
 }
 
 // NewStruct creates a new relationship struct
@@ -87,6 +115,20 @@ func (*userR) NewStruct() *userR {
 	return &userR{}
 }
 
+func (r *userR) GetActivities() ActivitySlice {
+	if r == nil {
+		return nil
+	}
+	return r.Activities
+}
+
+func (r *userR) GetPersonalAccessTokens() PersonalAccessTokenSlice {
+	if r == nil {
+		return nil
+	}
+	return r.PersonalAccessTokens
+}
+
 func (r *userR) GetTasks() TaskSlice {
 	if r == nil {
 		return nil
@@ -98,9 +140,9 @@ func (r *userR) GetTasks() TaskSlice {
 type userL struct{}
 
 var (
-	userAllColumns            = []string{"id", "name", "created_at", "updated_at"}
-	userColumnsWithoutDefault = []string{"id", "name"}
-	userColumnsWithDefault    = []string{"created_at", "updated_at"}
+	userAllColumns            = []string{"id", "name", "email", "avatar_url", "role", "created_at", "updated_at"}
+	userColumnsWithoutDefault = []string{"id", "name", "email", "avatar_url"}
+	userColumnsWithDefault    = []string{"role", "created_at", "updated_at"}
 	userPrimaryKeyColumns     = []string{"id"}
 	userGeneratedColumns      = []string{}
 )
@@ -383,6 +425,34 @@ func (q userQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool,
 	return count > 0, nil
 }
 
+// Activities retrieves all the activity's Activities with an executor.
+func (o *User) Activities(mods ...qm.QueryMod) activityQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("`activities`.`user_id`=?", o.ID),
+	)
+
+	return Activities(queryMods...)
+}
+
+// PersonalAccessTokens retrieves all the personal_access_token's PersonalAccessTokens with an executor.
+func (o *User) PersonalAccessTokens(mods ...qm.QueryMod) personalAccessTokenQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("`personal_access_tokens`.`user_id`=?", o.ID),
+	)
+
+	return PersonalAccessTokens(queryMods...)
+}
+
 // Tasks retrieves all the task's Tasks with an executor.
 func (o *User) Tasks(mods ...qm.QueryMod) taskQuery {
 	var queryMods []qm.QueryMod
@@ -397,6 +467,234 @@ func (o *User) Tasks(mods ...qm.QueryMod) taskQuery {
 	return Tasks(queryMods...)
 }
 
+// LoadActivities allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (userL) LoadActivities(ctx context.Context, e boil.ContextExecutor, singular bool, maybeUser interface{}, mods queries.Applicator) error {
+	var slice []*User
+	var object *User
+
+	if singular {
+		var ok bool
+		object, ok = maybeUser.(*User)
+		if !ok {
+			object = new(User)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeUser)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeUser))
+			}
+		}
+	} else {
+		s, ok := maybeUser.(*[]*User)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeUser)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeUser))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &userR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &userR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`activities`),
+		qm.WhereIn(`activities.user_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load activities")
+	}
+
+	var resultSlice []*Activity
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice activities")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on activities")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for activities")
+	}
+
+	if len(activityAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.Activities = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &activityR{}
+			}
+			foreign.R.User = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.UserID {
+				local.R.Activities = append(local.R.Activities, foreign)
+				if foreign.R == nil {
+					foreign.R = &activityR{}
+				}
+				foreign.R.User = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadPersonalAccessTokens allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (userL) LoadPersonalAccessTokens(ctx context.Context, e boil.ContextExecutor, singular bool, maybeUser interface{}, mods queries.Applicator) error {
+	var slice []*User
+	var object *User
+
+	if singular {
+		var ok bool
+		object, ok = maybeUser.(*User)
+		if !ok {
+			object = new(User)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeUser)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeUser))
+			}
+		}
+	} else {
+		s, ok := maybeUser.(*[]*User)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeUser)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeUser))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &userR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &userR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`personal_access_tokens`),
+		qm.WhereIn(`personal_access_tokens.user_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load personal_access_tokens")
+	}
+
+	var resultSlice []*PersonalAccessToken
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice personal_access_tokens")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on personal_access_tokens")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for personal_access_tokens")
+	}
+
+	if len(personalAccessTokenAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.PersonalAccessTokens = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &personalAccessTokenR{}
+			}
+			foreign.R.User = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.UserID {
+				local.R.PersonalAccessTokens = append(local.R.PersonalAccessTokens, foreign)
+				if foreign.R == nil {
+					foreign.R = &personalAccessTokenR{}
+				}
+				foreign.R.User = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // LoadTasks allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for a 1-M or N-M relationship.
 func (userL) LoadTasks(ctx context.Context, e boil.ContextExecutor, singular bool, maybeUser interface{}, mods queries.Applicator) error {
@@ -511,6 +809,112 @@ func (userL) LoadTasks(ctx context.Context, e boil.ContextExecutor, singular boo
 	return nil
 }
 
+// AddActivities adds the given related objects to the existing relationships
+// of the user, optionally inserting them as new records.
+// Appends related to o.R.Activities.
+// Sets related.R.User appropriately.
+func (o *User) AddActivities(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Activity) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.UserID = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE `activities` SET %s WHERE %s",
+				strmangle.SetParamNames("`", "`", 0, []string{"user_id"}),
+				strmangle.WhereClause("`", "`", 0, activityPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.UserID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &userR{
+			Activities: related,
+		}
+	} else {
+		o.R.Activities = append(o.R.Activities, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &activityR{
+				User: o,
+			}
+		} else {
+			rel.R.User = o
+		}
+	}
+	return nil
+}
+
+// AddPersonalAccessTokens adds the given related objects to the existing relationships
+// of the user, optionally inserting them as new records.
+// Appends related to o.R.PersonalAccessTokens.
+// Sets related.R.User appropriately.
+func (o *User) AddPersonalAccessTokens(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*PersonalAccessToken) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.UserID = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE `personal_access_tokens` SET %s WHERE %s",
+				strmangle.SetParamNames("`", "`", 0, []string{"user_id"}),
+				strmangle.WhereClause("`", "`", 0, personalAccessTokenPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.UserID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &userR{
+			PersonalAccessTokens: related,
+		}
+	} else {
+		o.R.PersonalAccessTokens = append(o.R.PersonalAccessTokens, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &personalAccessTokenR{
+				User: o,
+			}
+		} else {
+			rel.R.User = o
+		}
+	}
+	return nil
+}
+
 // AddTasks adds the given related objects to the existing relationships
 // of the user, optionally inserting them as new records.
 // Appends related to o.R.Tasks.
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shota-tech/graphql/server/database"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository/models"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type (
+	IStatusTransitionRepository interface {
+		Store(context.Context, *model.StatusTransition) error
+		ListByTaskUserID(ctx context.Context, userID string, until time.Time) ([]*model.StatusTransition, error)
+	}
+
+	StatusTransitionRepository struct {
+		db      *database.DB
+		dialect *Dialect
+	}
+)
+
+func NewStatusTransitionRepository(db *database.DB, dialect *Dialect) *StatusTransitionRepository {
+	return &StatusTransitionRepository{db: db, dialect: dialect}
+}
+
+func (r *StatusTransitionRepository) Store(ctx context.Context, transition *model.StatusTransition) error {
+	if transition == nil {
+		return errors.New("status transition is required")
+	}
+	var from null.String
+	if transition.From != nil {
+		from = null.StringFrom(transition.From.String())
+	}
+	row := models.StatusTransition{
+		ID:         transition.ID,
+		TaskID:     transition.TaskID,
+		FromStatus: from,
+		ToStatus:   transition.To.String(),
+		CreatedAt:  transition.CreatedAt,
+	}
+	if err := r.dialect.insert(ctx, r.db.Writer(ctx), models.TableNames.StatusTransitions, &row); err != nil {
+		return fmt.Errorf("failed to insert record: %w", err)
+	}
+	transition.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// ListByTaskUserID returns the transitions of all tasks owned by the user
+// made until the given time, oldest first.
+func (r *StatusTransitionRepository) ListByTaskUserID(ctx context.Context, userID string, until time.Time) ([]*model.StatusTransition, error) {
+	var rows models.StatusTransitionSlice
+	err := r.dialect.query(models.TableNames.StatusTransitions,
+		qm.InnerJoin(models.TableNames.Tasks+" ON "+models.TaskTableColumns.ID+" = "+models.StatusTransitionTableColumns.TaskID),
+		r.dialect.where(models.TaskTableColumns.UserID, "=", userID),
+		r.dialect.where(models.StatusTransitionTableColumns.CreatedAt, "<=", until),
+		qm.OrderBy(models.StatusTransitionTableColumns.CreatedAt+", "+models.StatusTransitionTableColumns.ID),
+	).Bind(ctx, r.db.Reader(ctx), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get records: %w", err)
+	}
+	transitions := make([]*model.StatusTransition, len(rows))
+	for i, row := range rows {
+		var from *model.Status
+		if row.FromStatus.Valid {
+			status := model.Status(row.FromStatus.String)
+			from = &status
+		}
+		transitions[i] = &model.StatusTransition{
+			ID:        row.ID,
+			TaskID:    row.TaskID,
+			From:      from,
+			To:        model.Status(row.ToStatus),
+			CreatedAt: row.CreatedAt,
+		}
+	}
+	return transitions, nil
+}
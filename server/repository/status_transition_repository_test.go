@@ -0,0 +1,168 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shota-tech/graphql/server/database"
+	"github.com/shota-tech/graphql/server/graph/model"
+	"github.com/shota-tech/graphql/server/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusTransitionRepository_Store(t *testing.T) {
+	from := model.StatusTodo
+	tests := map[string]struct {
+		setup      func(sqlmock.Sqlmock, *repository.Dialect)
+		transition *model.StatusTransition
+		assertErr  assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "INSERT INTO `status_transitions` (`id`,`task_id`,`from_status`,`to_status`,`created_at`) VALUES (?,?,?,?,?)"
+				args := []driver.Value{"cgh1q5dvqc7j7g5i0qsg", "cg1m0bd1nm6u7kpjp15g", "TODO", "IN_PROGRESS", sqlmock.AnyArg()}
+				mock.ExpectExec(expect(d, query)).
+					WithArgs(args...).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			transition: &model.StatusTransition{
+				ID:     "cgh1q5dvqc7j7g5i0qsg",
+				TaskID: "cg1m0bd1nm6u7kpjp15g",
+				From:   &from,
+				To:     model.StatusInProgress,
+			},
+			assertErr: assert.NoError,
+		},
+		"status transition is nil": {
+			setup:      nil,
+			transition: nil,
+			assertErr:  assert.Error,
+		},
+		"failed to insert record": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "INSERT INTO `status_transitions` (`id`,`task_id`,`from_status`,`to_status`,`created_at`) VALUES (?,?,?,?,?)"
+				args := []driver.Value{"cgh1q5dvqc7j7g5i0qsg", "cg1m0bd1nm6u7kpjp15g", "TODO", "IN_PROGRESS", sqlmock.AnyArg()}
+				mock.ExpectExec(expect(d, query)).
+					WithArgs(args...).
+					WillReturnError(assert.AnError)
+			},
+			transition: &model.StatusTransition{
+				ID:     "cgh1q5dvqc7j7g5i0qsg",
+				TaskID: "cg1m0bd1nm6u7kpjp15g",
+				From:   &from,
+				To:     model.StatusInProgress,
+			},
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewStatusTransitionRepository(database.New(db), d.dialect)
+				err = sut.Store(context.Background(), tt.transition)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestStatusTransitionRepository_ListByTaskUserID(t *testing.T) {
+	until := time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	from := model.StatusTodo
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		userID    string
+		until     time.Time
+		want      []*model.StatusTransition
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `status_transitions`.* FROM `status_transitions` " +
+					"INNER JOIN tasks ON tasks.id = status_transitions.task_id " +
+					"WHERE (`tasks`.`user_id` = ?) AND (`status_transitions`.`created_at` <= ?) " +
+					"ORDER BY status_transitions.created_at, status_transitions.id;"
+				args := []driver.Value{"auth0|123456", until}
+				rows := sqlmock.NewRows([]string{"id", "task_id", "from_status", "to_status", "created_at"}).
+					AddRow("cgh1q5dvqc7j7g5i0qsg", "cg1m0bd1nm6u7kpjp15g", nil, "TODO", createdAt).
+					AddRow("cgh1q6lvqc7j7g5i0qt0", "cg1m0bd1nm6u7kpjp15g", "TODO", "IN_PROGRESS", createdAt)
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			userID: "auth0|123456",
+			until:  until,
+			want: []*model.StatusTransition{
+				{ID: "cgh1q5dvqc7j7g5i0qsg", TaskID: "cg1m0bd1nm6u7kpjp15g", From: nil, To: model.StatusTodo, CreatedAt: createdAt},
+				{ID: "cgh1q6lvqc7j7g5i0qt0", TaskID: "cg1m0bd1nm6u7kpjp15g", From: &from, To: model.StatusInProgress, CreatedAt: createdAt},
+			},
+			assertErr: assert.NoError,
+		},
+		"0 records": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `status_transitions`.* FROM `status_transitions` " +
+					"INNER JOIN tasks ON tasks.id = status_transitions.task_id " +
+					"WHERE (`tasks`.`user_id` = ?) AND (`status_transitions`.`created_at` <= ?) " +
+					"ORDER BY status_transitions.created_at, status_transitions.id;"
+				args := []driver.Value{"auth0|123456", until}
+				rows := sqlmock.NewRows([]string{"id", "task_id", "from_status", "to_status", "created_at"})
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			userID:    "auth0|123456",
+			until:     until,
+			want:      []*model.StatusTransition{},
+			assertErr: assert.NoError,
+		},
+		"failed to get records": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `status_transitions`.* FROM `status_transitions` " +
+					"INNER JOIN tasks ON tasks.id = status_transitions.task_id " +
+					"WHERE (`tasks`.`user_id` = ?) AND (`status_transitions`.`created_at` <= ?) " +
+					"ORDER BY status_transitions.created_at, status_transitions.id;"
+				args := []driver.Value{"auth0|123456", until}
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnError(assert.AnError)
+			},
+			userID:    "auth0|123456",
+			until:     until,
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewStatusTransitionRepository(database.New(db), d.dialect)
+				got, err := sut.ListByTaskUserID(context.Background(), tt.userID, tt.until)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
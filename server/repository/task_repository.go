@@ -6,27 +6,31 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/shota-tech/graphql/server/database"
 	"github.com/shota-tech/graphql/server/graph/model"
 	"github.com/shota-tech/graphql/server/repository/models"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 )
 
 type (
 	ITaskRepository interface {
 		Store(context.Context, *model.Task) error
 		Get(context.Context, string) (*model.Task, error)
+		GetForUpdate(context.Context, string) (*model.Task, error)
 		List(context.Context, []string) ([]*model.Task, error)
 		ListByUserID(context.Context, string) ([]*model.Task, error)
 		ListByUserIDs(context.Context, []string) ([]*model.Task, error)
 	}
 
 	TaskRepository struct {
-		db *sql.DB
+		db      *database.DB
+		dialect *Dialect
 	}
 )
 
-func NewTaskRepository(db *sql.DB) *TaskRepository {
-	return &TaskRepository{db: db}
+func NewTaskRepository(db *database.DB, dialect *Dialect) *TaskRepository {
+	return &TaskRepository{db: db, dialect: dialect}
 }
 
 func (r *TaskRepository) Store(ctx context.Context, task *model.Task) error {
@@ -34,79 +38,99 @@ func (r *TaskRepository) Store(ctx context.Context, task *model.Task) error {
 		return errors.New("task is required")
 	}
 	row := models.Task{
-		ID:     task.ID,
-		Text:   task.Text,
-		Status: task.Status.String(),
-		UserID: task.UserID,
+		ID:        task.ID,
+		Text:      task.Text,
+		Status:    task.Status.String(),
+		UserID:    task.UserID,
+		CreatedAt: task.CreatedAt,
 	}
-	if err := row.Upsert(ctx, r.db, boil.Infer(), boil.Infer()); err != nil {
+	if err := r.dialect.upsert(ctx, r.db.Writer(ctx), models.TableNames.Tasks, &row); err != nil {
 		return fmt.Errorf("failed to upsert record: %w", err)
 	}
+	task.CreatedAt = row.CreatedAt
 	return nil
 }
 
 func (r *TaskRepository) Get(ctx context.Context, id string) (*model.Task, error) {
-	row, err := models.Tasks(models.TaskWhere.ID.EQ(id)).One(ctx, r.db)
+	return r.get(ctx, r.db.Reader(ctx), id)
+}
+
+// GetForUpdate is Get locking the row on the primary until the transaction in ctx ends.
+func (r *TaskRepository) GetForUpdate(ctx context.Context, id string) (*model.Task, error) {
+	return r.get(ctx, r.db.Writer(ctx), id, qm.For("UPDATE"))
+}
+
+func (r *TaskRepository) get(ctx context.Context, exec boil.ContextExecutor, id string, mods ...qm.QueryMod) (*model.Task, error) {
+	var row models.Task
+	mods = append([]qm.QueryMod{r.dialect.where(models.TaskTableColumns.ID, "=", id), qm.Limit(1)}, mods...)
+	err := r.dialect.query(models.TableNames.Tasks, mods...).Bind(ctx, exec, &row)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("record not found")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
 		}
 		return nil, fmt.Errorf("failed to get record: %w", err)
 	}
 	return &model.Task{
-		ID:     row.ID,
-		Text:   row.Text,
-		Status: model.Status(row.Status),
-		UserID: row.UserID,
+		ID:        row.ID,
+		Text:      row.Text,
+		Status:    model.Status(row.Status),
+		UserID:    row.UserID,
+		CreatedAt: row.CreatedAt,
 	}, nil
 }
 
 func (r *TaskRepository) List(ctx context.Context, ids []string) ([]*model.Task, error) {
-	rows, err := models.Tasks(models.TaskWhere.ID.IN(ids)).All(ctx, r.db)
+	var rows models.TaskSlice
+	err := r.dialect.query(models.TableNames.Tasks, r.dialect.whereIn(models.TaskTableColumns.ID, ids)).Bind(ctx, r.db.Reader(ctx), &rows)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get records: %w", err)
 	}
 	tasks := make([]*model.Task, len(rows))
 	for i, row := range rows {
 		tasks[i] = &model.Task{
-			ID:     row.ID,
-			Text:   row.Text,
-			Status: model.Status(row.Status),
-			UserID: row.UserID,
+			ID:        row.ID,
+			Text:      row.Text,
+			Status:    model.Status(row.Status),
+			UserID:    row.UserID,
+			CreatedAt: row.CreatedAt,
 		}
 	}
 	return tasks, nil
 }
 
 func (r *TaskRepository) ListByUserID(ctx context.Context, userID string) ([]*model.Task, error) {
-	rows, err := models.Tasks(models.TaskWhere.UserID.EQ(userID)).All(ctx, r.db)
+	var rows models.TaskSlice
+	err := r.dialect.query(models.TableNames.Tasks, r.dialect.where(models.TaskTableColumns.UserID, "=", userID)).Bind(ctx, r.db.Reader(ctx), &rows)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get records: %w", err)
 	}
 	tasks := make([]*model.Task, len(rows))
 	for i, row := range rows {
 		tasks[i] = &model.Task{
-			ID:     row.ID,
-			Text:   row.Text,
-			Status: model.Status(row.Status),
-			UserID: row.UserID,
+			ID:        row.ID,
+			Text:      row.Text,
+			Status:    model.Status(row.Status),
+			UserID:    row.UserID,
+			CreatedAt: row.CreatedAt,
 		}
 	}
 	return tasks, nil
 }
 
 func (r *TaskRepository) ListByUserIDs(ctx context.Context, userIDs []string) ([]*model.Task, error) {
-	rows, err := models.Tasks(models.TaskWhere.UserID.IN(userIDs)).All(ctx, r.db)
+	var rows models.TaskSlice
+	err := r.dialect.query(models.TableNames.Tasks, r.dialect.whereIn(models.TaskTableColumns.UserID, userIDs)).Bind(ctx, r.db.Reader(ctx), &rows)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get records: %w", err)
 	}
 	tasks := make([]*model.Task, len(rows))
 	for i, row := range rows {
 		tasks[i] = &model.Task{
-			ID:     row.ID,
-			Text:   row.Text,
-			Status: model.Status(row.Status),
-			UserID: row.UserID,
+			ID:        row.ID,
+			Text:      row.Text,
+			Status:    model.Status(row.Status),
+			UserID:    row.UserID,
+			CreatedAt: row.CreatedAt,
 		}
 	}
 	return tasks, nil
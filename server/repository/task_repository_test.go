@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shota-tech/graphql/server/database"
 	"github.com/shota-tech/graphql/server/graph/model"
 	"github.com/shota-tech/graphql/server/repository"
 	"github.com/stretchr/testify/assert"
@@ -15,17 +16,21 @@ import (
 )
 
 func TestTaskRepository_Store(t *testing.T) {
+	query := map[*repository.Dialect]string{
+		repository.MySQL: "INSERT INTO `tasks` (`id`,`text`,`status`,`user_id`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?) " +
+			"ON DUPLICATE KEY UPDATE `text` = VALUES(`text`),`status` = VALUES(`status`),`user_id` = VALUES(`user_id`),`updated_at` = VALUES(`updated_at`)",
+		repository.Postgres: `INSERT INTO "tasks" ("id","text","status","user_id","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) ` +
+			`ON CONFLICT ("id") DO UPDATE SET "text" = EXCLUDED."text","status" = EXCLUDED."status","user_id" = EXCLUDED."user_id","updated_at" = EXCLUDED."updated_at"`,
+	}
 	tests := map[string]struct {
-		setup     func(sqlmock.Sqlmock)
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
 		task      *model.Task
 		assertErr assert.ErrorAssertionFunc
 	}{
 		"happy path": {
-			setup: func(mock sqlmock.Sqlmock) {
-				query := "INSERT INTO `tasks` (`id`,`text`,`status`,`user_id`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?) " +
-					"ON DUPLICATE KEY UPDATE `text` = VALUES(`text`),`status` = VALUES(`status`),`user_id` = VALUES(`user_id`),`created_at` = VALUES(`created_at`),`updated_at` = VALUES(`updated_at`)"
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				args := []driver.Value{"cg1m0bd1nm6u7kpjp15g", "task1", "TODO", "auth0|123456", sqlmock.AnyArg(), sqlmock.AnyArg()}
-				mock.ExpectExec(regexp.QuoteMeta(query)).
+				mock.ExpectExec(regexp.QuoteMeta(query[d])).
 					WithArgs(args...).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
@@ -43,11 +48,9 @@ func TestTaskRepository_Store(t *testing.T) {
 			assertErr: assert.Error,
 		},
 		"failed to upsert record": {
-			setup: func(mock sqlmock.Sqlmock) {
-				query := "INSERT INTO `tasks` (`id`,`text`,`status`,`user_id`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?) " +
-					"ON DUPLICATE KEY UPDATE `text` = VALUES(`text`),`status` = VALUES(`status`),`user_id` = VALUES(`user_id`),`created_at` = VALUES(`created_at`),`updated_at` = VALUES(`updated_at`)"
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				args := []driver.Value{"cg1m0bd1nm6u7kpjp15g", "task1", "TODO", "auth0|123456", sqlmock.AnyArg(), sqlmock.AnyArg()}
-				mock.ExpectExec(regexp.QuoteMeta(query)).
+				mock.ExpectExec(regexp.QuoteMeta(query[d])).
 					WithArgs(args...).
 					WillReturnError(assert.AnError)
 			},
@@ -60,54 +63,58 @@ func TestTaskRepository_Store(t *testing.T) {
 			assertErr: assert.Error,
 		},
 	}
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			// setup sqlmock
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			if tt.setup != nil {
-				tt.setup(mock)
-			}
-			// test
-			sut := repository.NewTaskRepository(db)
-			err = sut.Store(context.Background(), tt.task)
-			tt.assertErr(t, err)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewTaskRepository(database.New(db), d.dialect)
+				err = sut.Store(context.Background(), tt.task)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
 	}
 }
 
 func TestTaskRepository_Get(t *testing.T) {
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
 	tests := map[string]struct {
-		setup     func(sqlmock.Sqlmock)
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
 		id        string
 		want      *model.Task
 		assertErr assert.ErrorAssertionFunc
 	}{
 		"happy path": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`id` = ?) LIMIT 1;"
 				row := sqlmock.NewRows([]string{"id", "text", "status", "user_id", "created_at", "updated_at"}).
-					AddRow("cg1m0bd1nm6u7kpjp15g", "task1", "TODO", "auth0|123456", time.Now(), time.Now())
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					AddRow("cg1m0bd1nm6u7kpjp15g", "task1", "TODO", "auth0|123456", createdAt, time.Now())
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs("cg1m0bd1nm6u7kpjp15g").
 					WillReturnRows(row)
 			},
 			id: "cg1m0bd1nm6u7kpjp15g",
 			want: &model.Task{
-				ID:     "cg1m0bd1nm6u7kpjp15g",
-				Text:   "task1",
-				Status: model.StatusTodo,
-				UserID: "auth0|123456",
+				ID:        "cg1m0bd1nm6u7kpjp15g",
+				Text:      "task1",
+				Status:    model.StatusTodo,
+				UserID:    "auth0|123456",
+				CreatedAt: createdAt,
 			},
 			assertErr: assert.NoError,
 		},
 		"record not found": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`id` = ?) LIMIT 1;"
 				row := sqlmock.NewRows([]string{"id", "text", "status", "user_id", "created_at", "updated_at"})
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs("cg1m0bd1nm6u7kpjp15g").
 					WillReturnRows(row)
 			},
@@ -116,9 +123,9 @@ func TestTaskRepository_Get(t *testing.T) {
 			assertErr: assert.Error,
 		},
 		"failed to get record": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`id` = ?) LIMIT 1;"
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs("cg1m0bd1nm6u7kpjp15g").
 					WillReturnError(assert.AnError)
 			},
@@ -127,56 +134,110 @@ func TestTaskRepository_Get(t *testing.T) {
 			assertErr: assert.Error,
 		},
 	}
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			// setup sqlmock
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			if tt.setup != nil {
-				tt.setup(mock)
-			}
-			// test
-			sut := repository.NewTaskRepository(db)
-			got, err := sut.Get(context.Background(), tt.id)
-			assert.Equal(t, tt.want, got)
-			tt.assertErr(t, err)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewTaskRepository(database.New(db), d.dialect)
+				got, err := sut.Get(context.Background(), tt.id)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestTaskRepository_GetForUpdate(t *testing.T) {
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`id` = ?) LIMIT 1 FOR UPDATE;"
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		want      *model.Task
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				row := sqlmock.NewRows([]string{"id", "text", "status", "user_id", "created_at", "updated_at"}).
+					AddRow("cg1m0bd1nm6u7kpjp15g", "task1", "TODO", "auth0|123456", createdAt, createdAt)
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("cg1m0bd1nm6u7kpjp15g").
+					WillReturnRows(row)
+			},
+			want:      &model.Task{ID: "cg1m0bd1nm6u7kpjp15g", Text: "task1", Status: model.StatusTodo, UserID: "auth0|123456", CreatedAt: createdAt},
+			assertErr: assert.NoError,
+		},
+		"record not found": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				row := sqlmock.NewRows([]string{"id", "text", "status", "user_id", "created_at", "updated_at"})
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("cg1m0bd1nm6u7kpjp15g").
+					WillReturnRows(row)
+			},
+			want: nil,
+			assertErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, repository.ErrNotFound)
+			},
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				tt.setup(mock, d.dialect)
+				// test
+				sut := repository.NewTaskRepository(database.New(db), d.dialect)
+				got, err := sut.GetForUpdate(context.Background(), "cg1m0bd1nm6u7kpjp15g")
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
 	}
 }
 
 func TestTaskRepository_List(t *testing.T) {
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
 	tests := map[string]struct {
-		setup     func(sqlmock.Sqlmock)
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
 		ids       []string
 		want      []*model.Task
 		assertErr assert.ErrorAssertionFunc
 	}{
 		"happy path": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`id` IN (?,?));"
 				args := []driver.Value{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"}
 				rows := sqlmock.NewRows([]string{"id", "text", "status", "user_id", "created_at", "updated_at"}).
-					AddRow("cg1m0bd1nm6u7kpjp15g", "task1", "TODO", "auth0|123456", time.Now(), time.Now()).
-					AddRow("cg2j6hl1nm6ivqd084m0", "task2", "TODO", "auth0|567890", time.Now(), time.Now())
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					AddRow("cg1m0bd1nm6u7kpjp15g", "task1", "TODO", "auth0|123456", createdAt, time.Now()).
+					AddRow("cg2j6hl1nm6ivqd084m0", "task2", "TODO", "auth0|567890", createdAt, time.Now())
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
 			ids: []string{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"},
 			want: []*model.Task{
-				{ID: "cg1m0bd1nm6u7kpjp15g", Text: "task1", Status: model.StatusTodo, UserID: "auth0|123456"},
-				{ID: "cg2j6hl1nm6ivqd084m0", Text: "task2", Status: model.StatusTodo, UserID: "auth0|567890"},
+				{ID: "cg1m0bd1nm6u7kpjp15g", Text: "task1", Status: model.StatusTodo, UserID: "auth0|123456", CreatedAt: createdAt},
+				{ID: "cg2j6hl1nm6ivqd084m0", Text: "task2", Status: model.StatusTodo, UserID: "auth0|567890", CreatedAt: createdAt},
 			},
 			assertErr: assert.NoError,
 		},
 		"0 records": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`id` IN (?,?));"
 				args := []driver.Value{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"}
 				rows := sqlmock.NewRows([]string{"id", "text", "status", "user_id", "created_at", "updated_at"})
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
@@ -185,10 +246,10 @@ func TestTaskRepository_List(t *testing.T) {
 			assertErr: assert.NoError,
 		},
 		"failed to get records": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`id` IN (?,?));"
 				args := []driver.Value{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"}
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnError(assert.AnError)
 			},
@@ -197,56 +258,59 @@ func TestTaskRepository_List(t *testing.T) {
 			assertErr: assert.Error,
 		},
 	}
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			// setup sqlmock
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			if tt.setup != nil {
-				tt.setup(mock)
-			}
-			// test
-			sut := repository.NewTaskRepository(db)
-			got, err := sut.List(context.Background(), tt.ids)
-			assert.Equal(t, tt.want, got)
-			tt.assertErr(t, err)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewTaskRepository(database.New(db), d.dialect)
+				got, err := sut.List(context.Background(), tt.ids)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
 	}
 }
 
 func TestTaskRepository_ListByUserID(t *testing.T) {
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
 	tests := map[string]struct {
-		setup     func(sqlmock.Sqlmock)
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
 		userID    string
 		want      []*model.Task
 		assertErr assert.ErrorAssertionFunc
 	}{
 		"happy path": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`user_id` = ?);"
 				args := []driver.Value{"auth0|123456"}
 				rows := sqlmock.NewRows([]string{"id", "text", "status", "user_id", "created_at", "updated_at"}).
-					AddRow("cg1m0bd1nm6u7kpjp15g", "task1", "TODO", "auth0|123456", time.Now(), time.Now()).
-					AddRow("cg2j6hl1nm6ivqd084m0", "task2", "TODO", "auth0|123456", time.Now(), time.Now())
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					AddRow("cg1m0bd1nm6u7kpjp15g", "task1", "TODO", "auth0|123456", createdAt, time.Now()).
+					AddRow("cg2j6hl1nm6ivqd084m0", "task2", "TODO", "auth0|123456", createdAt, time.Now())
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
 			userID: "auth0|123456",
 			want: []*model.Task{
-				{ID: "cg1m0bd1nm6u7kpjp15g", Text: "task1", Status: model.StatusTodo, UserID: "auth0|123456"},
-				{ID: "cg2j6hl1nm6ivqd084m0", Text: "task2", Status: model.StatusTodo, UserID: "auth0|123456"},
+				{ID: "cg1m0bd1nm6u7kpjp15g", Text: "task1", Status: model.StatusTodo, UserID: "auth0|123456", CreatedAt: createdAt},
+				{ID: "cg2j6hl1nm6ivqd084m0", Text: "task2", Status: model.StatusTodo, UserID: "auth0|123456", CreatedAt: createdAt},
 			},
 			assertErr: assert.NoError,
 		},
 		"0 records": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`user_id` = ?);"
 				args := []driver.Value{"auth0|123456"}
 				rows := sqlmock.NewRows([]string{"id", "text", "status", "user_id", "created_at", "updated_at"})
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
@@ -255,10 +319,10 @@ func TestTaskRepository_ListByUserID(t *testing.T) {
 			assertErr: assert.NoError,
 		},
 		"failed to get records": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`user_id` = ?);"
 				args := []driver.Value{"auth0|123456"}
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnError(assert.AnError)
 			},
@@ -267,56 +331,59 @@ func TestTaskRepository_ListByUserID(t *testing.T) {
 			assertErr: assert.Error,
 		},
 	}
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			// setup sqlmock
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			if tt.setup != nil {
-				tt.setup(mock)
-			}
-			// test
-			sut := repository.NewTaskRepository(db)
-			got, err := sut.ListByUserID(context.Background(), tt.userID)
-			assert.Equal(t, tt.want, got)
-			tt.assertErr(t, err)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewTaskRepository(database.New(db), d.dialect)
+				got, err := sut.ListByUserID(context.Background(), tt.userID)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
 	}
 }
 
 func TestTaskRepository_ListByUserIDs(t *testing.T) {
+	createdAt := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
 	tests := map[string]struct {
-		setup     func(sqlmock.Sqlmock)
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
 		userIDs   []string
 		want      []*model.Task
 		assertErr assert.ErrorAssertionFunc
 	}{
 		"happy path": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`user_id` IN (?,?));"
 				args := []driver.Value{"auth0|123456", "auth0|567890"}
 				rows := sqlmock.NewRows([]string{"id", "text", "status", "user_id", "created_at", "updated_at"}).
-					AddRow("cg1m0bd1nm6u7kpjp15g", "task1", "TODO", "auth0|123456", time.Now(), time.Now()).
-					AddRow("cg2j6hl1nm6ivqd084m0", "task2", "TODO", "auth0|567890", time.Now(), time.Now())
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					AddRow("cg1m0bd1nm6u7kpjp15g", "task1", "TODO", "auth0|123456", createdAt, time.Now()).
+					AddRow("cg2j6hl1nm6ivqd084m0", "task2", "TODO", "auth0|567890", createdAt, time.Now())
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
 			userIDs: []string{"auth0|123456", "auth0|567890"},
 			want: []*model.Task{
-				{ID: "cg1m0bd1nm6u7kpjp15g", Text: "task1", Status: model.StatusTodo, UserID: "auth0|123456"},
-				{ID: "cg2j6hl1nm6ivqd084m0", Text: "task2", Status: model.StatusTodo, UserID: "auth0|567890"},
+				{ID: "cg1m0bd1nm6u7kpjp15g", Text: "task1", Status: model.StatusTodo, UserID: "auth0|123456", CreatedAt: createdAt},
+				{ID: "cg2j6hl1nm6ivqd084m0", Text: "task2", Status: model.StatusTodo, UserID: "auth0|567890", CreatedAt: createdAt},
 			},
 			assertErr: assert.NoError,
 		},
 		"0 records": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`user_id` IN (?,?));"
 				args := []driver.Value{"auth0|123456", "auth0|567890"}
 				rows := sqlmock.NewRows([]string{"id", "text", "status", "user_id", "created_at", "updated_at"})
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
@@ -325,10 +392,10 @@ func TestTaskRepository_ListByUserIDs(t *testing.T) {
 			assertErr: assert.NoError,
 		},
 		"failed to get records": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `tasks`.* FROM `tasks` WHERE (`tasks`.`user_id` IN (?,?));"
 				args := []driver.Value{"auth0|123456", "auth0|567890"}
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnError(assert.AnError)
 			},
@@ -337,21 +404,23 @@ func TestTaskRepository_ListByUserIDs(t *testing.T) {
 			assertErr: assert.Error,
 		},
 	}
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			// setup sqlmock
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			if tt.setup != nil {
-				tt.setup(mock)
-			}
-			// test
-			sut := repository.NewTaskRepository(db)
-			got, err := sut.ListByUserIDs(context.Background(), tt.userIDs)
-			assert.Equal(t, tt.want, got)
-			tt.assertErr(t, err)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewTaskRepository(database.New(db), d.dialect)
+				got, err := sut.ListByUserIDs(context.Background(), tt.userIDs)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
 	}
 }
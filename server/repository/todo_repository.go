@@ -6,26 +6,33 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/shota-tech/graphql/server/database"
 	"github.com/shota-tech/graphql/server/graph/model"
 	"github.com/shota-tech/graphql/server/repository/models"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 )
 
 type (
 	ITodoRepository interface {
 		Store(context.Context, *model.Todo) error
 		Get(context.Context, string) (*model.Todo, error)
+		GetForUpdate(context.Context, string) (*model.Todo, error)
 		List(context.Context, []string) ([]*model.Todo, error)
 		ListByTaskIDs(context.Context, []string) ([]*model.Todo, error)
+		ListByParentIDs(context.Context, []string) ([]*model.Todo, error)
+		CountByTaskIDs(context.Context, []string) ([]*model.Progress, error)
 	}
 
 	TodoRepository struct {
-		db *sql.DB
+		db      *database.DB
+		dialect *Dialect
 	}
 )
 
-func NewTodoRepository(db *sql.DB) *TodoRepository {
-	return &TodoRepository{db: db}
+func NewTodoRepository(db *database.DB, dialect *Dialect) *TodoRepository {
+	return &TodoRepository{db: db, dialect: dialect}
 }
 
 func (r *TodoRepository) Store(ctx context.Context, todo *model.Todo) error {
@@ -33,63 +40,141 @@ func (r *TodoRepository) Store(ctx context.Context, todo *model.Todo) error {
 		return errors.New("todo is required")
 	}
 	row := models.Todo{
-		ID:     todo.ID,
-		Text:   todo.Text,
-		Done:   todo.Done,
-		TaskID: todo.TaskID,
+		ID:       todo.ID,
+		Text:     todo.Text,
+		Done:     todo.Done,
+		TaskID:   todo.TaskID,
+		ParentID: null.StringFromPtr(todo.ParentID),
+		Position: todo.Position,
 	}
-	if err := row.Upsert(ctx, r.db, boil.Infer(), boil.Infer()); err != nil {
+	if err := r.dialect.upsert(ctx, r.db.Writer(ctx), models.TableNames.Todos, &row); err != nil {
 		return fmt.Errorf("failed to upsert record: %w", err)
 	}
 	return nil
 }
 
 func (r *TodoRepository) Get(ctx context.Context, id string) (*model.Todo, error) {
-	row, err := models.Todos(models.TodoWhere.ID.EQ(id)).One(ctx, r.db)
+	return r.get(ctx, r.db.Reader(ctx), id)
+}
+
+// GetForUpdate is Get locking the row on the primary until the transaction in ctx ends.
+func (r *TodoRepository) GetForUpdate(ctx context.Context, id string) (*model.Todo, error) {
+	return r.get(ctx, r.db.Writer(ctx), id, qm.For("UPDATE"))
+}
+
+func (r *TodoRepository) get(ctx context.Context, exec boil.ContextExecutor, id string, mods ...qm.QueryMod) (*model.Todo, error) {
+	var row models.Todo
+	mods = append([]qm.QueryMod{r.dialect.where(models.TodoTableColumns.ID, "=", id), qm.Limit(1)}, mods...)
+	err := r.dialect.query(models.TableNames.Todos, mods...).Bind(ctx, exec, &row)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("record not found")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
 		}
 		return nil, fmt.Errorf("failed to get record: %w", err)
 	}
 	return &model.Todo{
-		ID:     row.ID,
-		Text:   row.Text,
-		Done:   row.Done,
-		TaskID: row.TaskID,
+		ID:       row.ID,
+		Text:     row.Text,
+		Done:     row.Done,
+		TaskID:   row.TaskID,
+		ParentID: row.ParentID.Ptr(),
+		Position: row.Position,
 	}, nil
 }
 
 func (r *TodoRepository) List(ctx context.Context, ids []string) ([]*model.Todo, error) {
-	rows, err := models.Todos(models.TodoWhere.ID.IN(ids)).All(ctx, r.db)
+	var rows models.TodoSlice
+	err := r.dialect.query(models.TableNames.Todos, r.dialect.whereIn(models.TodoTableColumns.ID, ids)).Bind(ctx, r.db.Reader(ctx), &rows)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get records: %w", err)
 	}
 	todos := make([]*model.Todo, len(rows))
 	for i, row := range rows {
 		todos[i] = &model.Todo{
-			ID:     row.ID,
-			Text:   row.Text,
-			Done:   row.Done,
-			TaskID: row.TaskID,
+			ID:       row.ID,
+			Text:     row.Text,
+			Done:     row.Done,
+			TaskID:   row.TaskID,
+			ParentID: row.ParentID.Ptr(),
+			Position: row.Position,
 		}
 	}
 	return todos, nil
 }
 
 func (r *TodoRepository) ListByTaskIDs(ctx context.Context, taskIDs []string) ([]*model.Todo, error) {
-	rows, err := models.Todos(models.TodoWhere.TaskID.IN(taskIDs)).All(ctx, r.db)
+	var rows models.TodoSlice
+	err := r.dialect.query(models.TableNames.Todos,
+		r.dialect.whereIn(models.TodoTableColumns.TaskID, taskIDs),
+		qm.OrderBy(models.TodoColumns.Position),
+	).Bind(ctx, r.db.Reader(ctx), &rows)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get records: %w", err)
 	}
 	todos := make([]*model.Todo, len(rows))
 	for i, row := range rows {
 		todos[i] = &model.Todo{
-			ID:     row.ID,
-			Text:   row.Text,
-			Done:   row.Done,
-			TaskID: row.TaskID,
+			ID:       row.ID,
+			Text:     row.Text,
+			Done:     row.Done,
+			TaskID:   row.TaskID,
+			ParentID: row.ParentID.Ptr(),
+			Position: row.Position,
 		}
 	}
 	return todos, nil
 }
+
+func (r *TodoRepository) ListByParentIDs(ctx context.Context, parentIDs []string) ([]*model.Todo, error) {
+	var rows models.TodoSlice
+	err := r.dialect.query(models.TableNames.Todos,
+		r.dialect.whereIn(models.TodoTableColumns.ParentID, parentIDs),
+		qm.OrderBy(models.TodoColumns.Position),
+	).Bind(ctx, r.db.Reader(ctx), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get records: %w", err)
+	}
+	todos := make([]*model.Todo, len(rows))
+	for i, row := range rows {
+		todos[i] = &model.Todo{
+			ID:       row.ID,
+			Text:     row.Text,
+			Done:     row.Done,
+			TaskID:   row.TaskID,
+			ParentID: row.ParentID.Ptr(),
+			Position: row.Position,
+		}
+	}
+	return todos, nil
+}
+
+// CountByTaskIDs counts the todos of each task without loading them.
+// Tasks without any todo are omitted.
+func (r *TodoRepository) CountByTaskIDs(ctx context.Context, taskIDs []string) ([]*model.Progress, error) {
+	var rows []struct {
+		TaskID string `boil:"task_id"`
+		Done   int    `boil:"done"`
+		Total  int    `boil:"total"`
+	}
+	err := r.dialect.query(models.TableNames.Todos,
+		qm.Select(
+			models.TodoColumns.TaskID,
+			r.dialect.countTrue(models.TodoColumns.Done)+" AS done",
+			"COUNT(*) AS total",
+		),
+		r.dialect.whereIn(models.TodoTableColumns.TaskID, taskIDs),
+		qm.GroupBy(models.TodoColumns.TaskID),
+	).Bind(ctx, r.db.Reader(ctx), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count records: %w", err)
+	}
+	progresses := make([]*model.Progress, len(rows))
+	for i, row := range rows {
+		progresses[i] = &model.Progress{
+			TaskID: row.TaskID,
+			Done:   row.Done,
+			Total:  row.Total,
+		}
+	}
+	return progresses, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shota-tech/graphql/server/database"
 	"github.com/shota-tech/graphql/server/graph/model"
 	"github.com/shota-tech/graphql/server/repository"
 	"github.com/stretchr/testify/assert"
@@ -15,25 +16,32 @@ import (
 )
 
 func TestTodoRepository_Store(t *testing.T) {
+	parentID := "cgf90odvqc7hkkh47tg0"
+	query := map[*repository.Dialect]string{
+		repository.MySQL: "INSERT INTO `todos` (`id`,`text`,`done`,`task_id`,`parent_id`,`position`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?) " +
+			"ON DUPLICATE KEY UPDATE `text` = VALUES(`text`),`done` = VALUES(`done`),`task_id` = VALUES(`task_id`),`parent_id` = VALUES(`parent_id`),`position` = VALUES(`position`),`updated_at` = VALUES(`updated_at`)",
+		repository.Postgres: `INSERT INTO "todos" ("id","text","done","task_id","parent_id","position","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) ` +
+			`ON CONFLICT ("id") DO UPDATE SET "text" = EXCLUDED."text","done" = EXCLUDED."done","task_id" = EXCLUDED."task_id","parent_id" = EXCLUDED."parent_id","position" = EXCLUDED."position","updated_at" = EXCLUDED."updated_at"`,
+	}
 	tests := map[string]struct {
-		setup     func(sqlmock.Sqlmock)
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
 		todo      *model.Todo
 		assertErr assert.ErrorAssertionFunc
 	}{
 		"happy path": {
-			setup: func(mock sqlmock.Sqlmock) {
-				query := "INSERT INTO `todos` (`id`,`text`,`done`,`task_id`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?) " +
-					"ON DUPLICATE KEY UPDATE `text` = VALUES(`text`),`done` = VALUES(`done`),`task_id` = VALUES(`task_id`),`created_at` = VALUES(`created_at`),`updated_at` = VALUES(`updated_at`)"
-				args := []driver.Value{"cgf90odvqc7hkkh47tg0", "todo1", false, "cg1m0bd1nm6u7kpjp15g", sqlmock.AnyArg(), sqlmock.AnyArg()}
-				mock.ExpectExec(regexp.QuoteMeta(query)).
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				args := []driver.Value{"cgf95atvqc7hriet4at0", "todo2", false, "cg1m0bd1nm6u7kpjp15g", "cgf90odvqc7hkkh47tg0", 1, sqlmock.AnyArg(), sqlmock.AnyArg()}
+				mock.ExpectExec(regexp.QuoteMeta(query[d])).
 					WithArgs(args...).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			todo: &model.Todo{
-				ID:     "cgf90odvqc7hkkh47tg0",
-				Text:   "todo1",
-				Done:   false,
-				TaskID: "cg1m0bd1nm6u7kpjp15g",
+				ID:       "cgf95atvqc7hriet4at0",
+				Text:     "todo2",
+				Done:     false,
+				TaskID:   "cg1m0bd1nm6u7kpjp15g",
+				ParentID: &parentID,
+				Position: 1,
 			},
 			assertErr: assert.NoError,
 		},
@@ -43,54 +51,56 @@ func TestTodoRepository_Store(t *testing.T) {
 			assertErr: assert.Error,
 		},
 		"failed to upsert record": {
-			setup: func(mock sqlmock.Sqlmock) {
-				query := "INSERT INTO `todos` (`id`,`text`,`done`,`task_id`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?) " +
-					"ON DUPLICATE KEY UPDATE `text` = VALUES(`text`),`done` = VALUES(`done`),`task_id` = VALUES(`task_id`),`created_at` = VALUES(`created_at`),`updated_at` = VALUES(`updated_at`)"
-				args := []driver.Value{"cgf90odvqc7hkkh47tg0", "todo1", false, "cg1m0bd1nm6u7kpjp15g", sqlmock.AnyArg(), sqlmock.AnyArg()}
-				mock.ExpectExec(regexp.QuoteMeta(query)).
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				args := []driver.Value{"cgf95atvqc7hriet4at0", "todo2", false, "cg1m0bd1nm6u7kpjp15g", "cgf90odvqc7hkkh47tg0", 1, sqlmock.AnyArg(), sqlmock.AnyArg()}
+				mock.ExpectExec(regexp.QuoteMeta(query[d])).
 					WithArgs(args...).
 					WillReturnError(assert.AnError)
 			},
 			todo: &model.Todo{
-				ID:     "cgf90odvqc7hkkh47tg0",
-				Text:   "todo1",
-				Done:   false,
-				TaskID: "cg1m0bd1nm6u7kpjp15g",
+				ID:       "cgf95atvqc7hriet4at0",
+				Text:     "todo2",
+				Done:     false,
+				TaskID:   "cg1m0bd1nm6u7kpjp15g",
+				ParentID: &parentID,
+				Position: 1,
 			},
 			assertErr: assert.Error,
 		},
 	}
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			// setup sqlmock
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			if tt.setup != nil {
-				tt.setup(mock)
-			}
-			// test
-			sut := repository.NewTodoRepository(db)
-			err = sut.Store(context.Background(), tt.todo)
-			tt.assertErr(t, err)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewTodoRepository(database.New(db), d.dialect)
+				err = sut.Store(context.Background(), tt.todo)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
 	}
 }
 
 func TestTodoRepository_Get(t *testing.T) {
 	tests := map[string]struct {
-		setup     func(sqlmock.Sqlmock)
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
 		id        string
 		want      *model.Todo
 		assertErr assert.ErrorAssertionFunc
 	}{
 		"happy path": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`id` = ?) LIMIT 1;"
-				row := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "created_at", "updated_at"}).
-					AddRow("cgf90odvqc7hkkh47tg0", "todo1", false, "cg1m0bd1nm6u7kpjp15g", time.Now(), time.Now())
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				row := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "parent_id", "position", "created_at", "updated_at"}).
+					AddRow("cgf90odvqc7hkkh47tg0", "todo1", false, "cg1m0bd1nm6u7kpjp15g", nil, 0, time.Now(), time.Now())
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs("cgf90odvqc7hkkh47tg0").
 					WillReturnRows(row)
 			},
@@ -104,10 +114,10 @@ func TestTodoRepository_Get(t *testing.T) {
 			assertErr: assert.NoError,
 		},
 		"record not found": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`id` = ?) LIMIT 1;"
-				row := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "created_at", "updated_at"})
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				row := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "parent_id", "position", "created_at", "updated_at"})
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs("cgf90odvqc7hkkh47tg0").
 					WillReturnRows(row)
 			},
@@ -116,9 +126,9 @@ func TestTodoRepository_Get(t *testing.T) {
 			assertErr: assert.Error,
 		},
 		"failed to get record": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`id` = ?) LIMIT 1;"
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs("cgf90odvqc7hkkh47tg0").
 					WillReturnError(assert.AnError)
 			},
@@ -127,56 +137,108 @@ func TestTodoRepository_Get(t *testing.T) {
 			assertErr: assert.Error,
 		},
 	}
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			// setup sqlmock
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			if tt.setup != nil {
-				tt.setup(mock)
-			}
-			// test
-			sut := repository.NewTodoRepository(db)
-			got, err := sut.Get(context.Background(), tt.id)
-			assert.Equal(t, tt.want, got)
-			tt.assertErr(t, err)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewTodoRepository(database.New(db), d.dialect)
+				got, err := sut.Get(context.Background(), tt.id)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestTodoRepository_GetForUpdate(t *testing.T) {
+	query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`id` = ?) LIMIT 1 FOR UPDATE;"
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		want      *model.Todo
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				row := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "parent_id", "position", "created_at", "updated_at"}).
+					AddRow("cgf90odvqc7hkkh47tg0", "todo1", false, "cg1m0bd1nm6u7kpjp15g", nil, 0, time.Now(), time.Now())
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("cgf90odvqc7hkkh47tg0").
+					WillReturnRows(row)
+			},
+			want:      &model.Todo{ID: "cgf90odvqc7hkkh47tg0", Text: "todo1", TaskID: "cg1m0bd1nm6u7kpjp15g"},
+			assertErr: assert.NoError,
+		},
+		"record not found": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				row := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "parent_id", "position", "created_at", "updated_at"})
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("cgf90odvqc7hkkh47tg0").
+					WillReturnRows(row)
+			},
+			want: nil,
+			assertErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, repository.ErrNotFound)
+			},
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				tt.setup(mock, d.dialect)
+				// test
+				sut := repository.NewTodoRepository(database.New(db), d.dialect)
+				got, err := sut.GetForUpdate(context.Background(), "cgf90odvqc7hkkh47tg0")
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
 	}
 }
 
 func TestTodoRepository_List(t *testing.T) {
 	tests := map[string]struct {
-		setup     func(sqlmock.Sqlmock)
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
 		ids       []string
 		want      []*model.Todo
 		assertErr assert.ErrorAssertionFunc
 	}{
 		"happy path": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`id` IN (?,?));"
 				args := []driver.Value{"cgf90odvqc7hkkh47tg0", "cgf95atvqc7hriet4at0"}
-				rows := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "created_at", "updated_at"}).
-					AddRow("cgf90odvqc7hkkh47tg0", "todo1", false, "cg1m0bd1nm6u7kpjp15g", time.Now(), time.Now()).
-					AddRow("cgf95atvqc7hriet4at0", "todo2", true, "cg2j6hl1nm6ivqd084m0", time.Now(), time.Now())
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				rows := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "parent_id", "position", "created_at", "updated_at"}).
+					AddRow("cgf90odvqc7hkkh47tg0", "todo1", false, "cg1m0bd1nm6u7kpjp15g", nil, 0, time.Now(), time.Now()).
+					AddRow("cgf95atvqc7hriet4at0", "todo2", true, "cg2j6hl1nm6ivqd084m0", nil, 1, time.Now(), time.Now())
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
 			ids: []string{"cgf90odvqc7hkkh47tg0", "cgf95atvqc7hriet4at0"},
 			want: []*model.Todo{
 				{ID: "cgf90odvqc7hkkh47tg0", Text: "todo1", Done: false, TaskID: "cg1m0bd1nm6u7kpjp15g"},
-				{ID: "cgf95atvqc7hriet4at0", Text: "todo2", Done: true, TaskID: "cg2j6hl1nm6ivqd084m0"},
+				{ID: "cgf95atvqc7hriet4at0", Text: "todo2", Done: true, TaskID: "cg2j6hl1nm6ivqd084m0", Position: 1},
 			},
 			assertErr: assert.NoError,
 		},
 		"0 records": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`id` IN (?,?));"
 				args := []driver.Value{"cgf90odvqc7hkkh47tg0", "cgf95atvqc7hriet4at0"}
-				rows := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "created_at", "updated_at"})
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				rows := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "parent_id", "position", "created_at", "updated_at"})
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
@@ -185,10 +247,10 @@ func TestTodoRepository_List(t *testing.T) {
 			assertErr: assert.NoError,
 		},
 		"failed to get records": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`id` IN (?,?));"
 				args := []driver.Value{"cgf90odvqc7hkkh47tg0", "cgf95atvqc7hriet4at0"}
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnError(assert.AnError)
 			},
@@ -197,56 +259,58 @@ func TestTodoRepository_List(t *testing.T) {
 			assertErr: assert.Error,
 		},
 	}
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			// setup sqlmock
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			if tt.setup != nil {
-				tt.setup(mock)
-			}
-			// test
-			sut := repository.NewTodoRepository(db)
-			got, err := sut.List(context.Background(), tt.ids)
-			assert.Equal(t, tt.want, got)
-			tt.assertErr(t, err)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewTodoRepository(database.New(db), d.dialect)
+				got, err := sut.List(context.Background(), tt.ids)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
 	}
 }
 
 func TestTodoRepository_ListByTaskIDs(t *testing.T) {
 	tests := map[string]struct {
-		setup     func(sqlmock.Sqlmock)
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
 		taskIDs   []string
 		want      []*model.Todo
 		assertErr assert.ErrorAssertionFunc
 	}{
 		"happy path": {
-			setup: func(mock sqlmock.Sqlmock) {
-				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`task_id` IN (?,?));"
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`task_id` IN (?,?)) ORDER BY position;"
 				args := []driver.Value{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"}
-				rows := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "created_at", "updated_at"}).
-					AddRow("cgf90odvqc7hkkh47tg0", "todo1", false, "cg1m0bd1nm6u7kpjp15g", time.Now(), time.Now()).
-					AddRow("cgf95atvqc7hriet4at0", "todo2", true, "cg2j6hl1nm6ivqd084m0", time.Now(), time.Now())
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				rows := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "parent_id", "position", "created_at", "updated_at"}).
+					AddRow("cgf90odvqc7hkkh47tg0", "todo1", false, "cg1m0bd1nm6u7kpjp15g", nil, 0, time.Now(), time.Now()).
+					AddRow("cgf95atvqc7hriet4at0", "todo2", true, "cg2j6hl1nm6ivqd084m0", nil, 1, time.Now(), time.Now())
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
 			taskIDs: []string{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"},
 			want: []*model.Todo{
 				{ID: "cgf90odvqc7hkkh47tg0", Text: "todo1", Done: false, TaskID: "cg1m0bd1nm6u7kpjp15g"},
-				{ID: "cgf95atvqc7hriet4at0", Text: "todo2", Done: true, TaskID: "cg2j6hl1nm6ivqd084m0"},
+				{ID: "cgf95atvqc7hriet4at0", Text: "todo2", Done: true, TaskID: "cg2j6hl1nm6ivqd084m0", Position: 1},
 			},
 			assertErr: assert.NoError,
 		},
 		"0 records": {
-			setup: func(mock sqlmock.Sqlmock) {
-				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`task_id` IN (?,?));"
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`task_id` IN (?,?)) ORDER BY position;"
 				args := []driver.Value{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"}
-				rows := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "created_at", "updated_at"})
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				rows := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "parent_id", "position", "created_at", "updated_at"})
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
@@ -255,10 +319,156 @@ func TestTodoRepository_ListByTaskIDs(t *testing.T) {
 			assertErr: assert.NoError,
 		},
 		"failed to get records": {
-			setup: func(mock sqlmock.Sqlmock) {
-				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`task_id` IN (?,?));"
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`task_id` IN (?,?)) ORDER BY position;"
+				args := []driver.Value{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"}
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnError(assert.AnError)
+			},
+			taskIDs:   []string{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"},
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewTodoRepository(database.New(db), d.dialect)
+				got, err := sut.ListByTaskIDs(context.Background(), tt.taskIDs)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestTodoRepository_ListByParentIDs(t *testing.T) {
+	parentID := "cgf90odvqc7hkkh47tg0"
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		parentIDs []string
+		want      []*model.Todo
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`parent_id` IN (?)) ORDER BY position;"
+				args := []driver.Value{"cgf90odvqc7hkkh47tg0"}
+				rows := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "parent_id", "position", "created_at", "updated_at"}).
+					AddRow("cgf95atvqc7hriet4at0", "todo2", true, "cg1m0bd1nm6u7kpjp15g", "cgf90odvqc7hkkh47tg0", 1, time.Now(), time.Now()).
+					AddRow("cgh2d6tvqc7k1rmoo5v0", "todo3", false, "cg1m0bd1nm6u7kpjp15g", "cgf90odvqc7hkkh47tg0", 2, time.Now(), time.Now())
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			parentIDs: []string{"cgf90odvqc7hkkh47tg0"},
+			want: []*model.Todo{
+				{ID: "cgf95atvqc7hriet4at0", Text: "todo2", Done: true, TaskID: "cg1m0bd1nm6u7kpjp15g", ParentID: &parentID, Position: 1},
+				{ID: "cgh2d6tvqc7k1rmoo5v0", Text: "todo3", Done: false, TaskID: "cg1m0bd1nm6u7kpjp15g", ParentID: &parentID, Position: 2},
+			},
+			assertErr: assert.NoError,
+		},
+		"0 records": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`parent_id` IN (?)) ORDER BY position;"
+				args := []driver.Value{"cgf90odvqc7hkkh47tg0"}
+				rows := sqlmock.NewRows([]string{"id", "text", "done", "task_id", "parent_id", "position", "created_at", "updated_at"})
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			parentIDs: []string{"cgf90odvqc7hkkh47tg0"},
+			want:      []*model.Todo{},
+			assertErr: assert.NoError,
+		},
+		"failed to get records": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `todos`.* FROM `todos` WHERE (`todos`.`parent_id` IN (?)) ORDER BY position;"
+				args := []driver.Value{"cgf90odvqc7hkkh47tg0"}
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnError(assert.AnError)
+			},
+			parentIDs: []string{"cgf90odvqc7hkkh47tg0"},
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewTodoRepository(database.New(db), d.dialect)
+				got, err := sut.ListByParentIDs(context.Background(), tt.parentIDs)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestTodoRepository_CountByTaskIDs(t *testing.T) {
+	query := map[*repository.Dialect]string{
+		repository.MySQL:    "SELECT `task_id`, COALESCE(SUM(done), 0) AS done, COUNT(*) AS total FROM `todos` WHERE (`todos`.`task_id` IN (?,?)) GROUP BY task_id;",
+		repository.Postgres: `SELECT "task_id", COUNT(CASE WHEN done THEN 1 END) AS done, COUNT(*) AS total FROM "todos" WHERE ("todos"."task_id" IN ($1,$2)) GROUP BY task_id;`,
+	}
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		taskIDs   []string
+		want      []*model.Progress
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				args := []driver.Value{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"}
+				rows := sqlmock.NewRows([]string{"task_id", "done", "total"}).
+					AddRow("cg1m0bd1nm6u7kpjp15g", 1, 3).
+					AddRow("cg2j6hl1nm6ivqd084m0", 0, 2)
+				mock.ExpectQuery(regexp.QuoteMeta(query[d])).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			taskIDs: []string{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"},
+			want: []*model.Progress{
+				{TaskID: "cg1m0bd1nm6u7kpjp15g", Done: 1, Total: 3},
+				{TaskID: "cg2j6hl1nm6ivqd084m0", Done: 0, Total: 2},
+			},
+			assertErr: assert.NoError,
+		},
+		"0 records": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				args := []driver.Value{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"}
+				rows := sqlmock.NewRows([]string{"task_id", "done", "total"})
+				mock.ExpectQuery(regexp.QuoteMeta(query[d])).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			taskIDs:   []string{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"},
+			want:      []*model.Progress{},
+			assertErr: assert.NoError,
+		},
+		"failed to count records": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				args := []driver.Value{"cg1m0bd1nm6u7kpjp15g", "cg2j6hl1nm6ivqd084m0"}
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(regexp.QuoteMeta(query[d])).
 					WithArgs(args...).
 					WillReturnError(assert.AnError)
 			},
@@ -267,21 +477,23 @@ func TestTodoRepository_ListByTaskIDs(t *testing.T) {
 			assertErr: assert.Error,
 		},
 	}
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			// setup sqlmock
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			if tt.setup != nil {
-				tt.setup(mock)
-			}
-			// test
-			sut := repository.NewTodoRepository(db)
-			got, err := sut.ListByTaskIDs(context.Background(), tt.taskIDs)
-			assert.Equal(t, tt.want, got)
-			tt.assertErr(t, err)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewTodoRepository(database.New(db), d.dialect)
+				got, err := sut.CountByTaskIDs(context.Background(), tt.taskIDs)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
 	}
 }
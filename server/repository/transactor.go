@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/shota-tech/graphql/server/database"
+)
+
+// ITransactor runs fn in a transaction, the repositories called with the ctx given to fn taking part in it.
+type ITransactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+var _ ITransactor = &database.DB{}
@@ -5,25 +5,33 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/shota-tech/graphql/server/database"
 	"github.com/shota-tech/graphql/server/graph/model"
 	"github.com/shota-tech/graphql/server/repository/models"
-	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 )
 
 type (
 	IUserRepository interface {
 		Store(context.Context, *model.User) error
+		Get(context.Context, string) (*model.User, error)
+		UpdateName(ctx context.Context, id, name string) error
+		UpdateRole(ctx context.Context, id string, role model.Role) error
 		List(context.Context, []string) ([]*model.User, error)
+		Search(context.Context, *model.UserFilter) ([]*model.User, error)
 	}
 
 	UserRepository struct {
-		db *sql.DB
+		db      *database.DB
+		dialect *Dialect
 	}
 )
 
-func NewUserRepository(db *sql.DB) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(db *database.DB, dialect *Dialect) *UserRepository {
+	return &UserRepository{db: db, dialect: dialect}
 }
 
 func (r *UserRepository) Store(ctx context.Context, user *model.User) error {
@@ -31,26 +39,94 @@ func (r *UserRepository) Store(ctx context.Context, user *model.User) error {
 		return errors.New("user is required")
 	}
 	row := models.User{
-		ID:   user.ID,
-		Name: user.Name,
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     null.StringFromPtr(user.Email),
+		AvatarURL: null.StringFromPtr(user.AvatarURL),
+		Role:      user.Role.String(),
 	}
-	if err := row.Upsert(ctx, r.db, boil.Infer(), boil.Infer()); err != nil {
+	if err := r.dialect.upsert(ctx, r.db.Writer(ctx), models.TableNames.Users, &row); err != nil {
 		return fmt.Errorf("failed to upsert record: %w", err)
 	}
 	return nil
 }
 
+func (r *UserRepository) Get(ctx context.Context, id string) (*model.User, error) {
+	var row models.User
+	err := r.dialect.query(models.TableNames.Users,
+		r.dialect.where(models.UserTableColumns.ID, "=", id),
+		qm.Limit(1),
+	).Bind(ctx, r.db.Reader(ctx), &row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get record: %w", err)
+	}
+	return toUsers(models.UserSlice{&row})[0], nil
+}
+
+// UpdateName renames the user, leaving the fields written by others such as the role as they are.
+func (r *UserRepository) UpdateName(ctx context.Context, id, name string) error {
+	return r.update(ctx, id, models.UserColumns.Name, name)
+}
+
+// UpdateRole changes the role of the user, leaving its profile as it is.
+func (r *UserRepository) UpdateRole(ctx context.Context, id string, role model.Role) error {
+	return r.update(ctx, id, models.UserColumns.Role, role.String())
+}
+
+func (r *UserRepository) update(ctx context.Context, id, column string, value interface{}) error {
+	err := r.dialect.update(ctx, r.db.Writer(ctx), models.TableNames.Users, id, map[string]interface{}{column: value})
+	if err != nil {
+		return fmt.Errorf("failed to update record: %w", err)
+	}
+	return nil
+}
+
 func (r *UserRepository) List(ctx context.Context, ids []string) ([]*model.User, error) {
-	rows, err := models.Users(models.UserWhere.ID.IN(ids)).All(ctx, r.db)
+	var rows models.UserSlice
+	err := r.dialect.query(models.TableNames.Users, r.dialect.whereIn(models.UserTableColumns.ID, ids)).Bind(ctx, r.db.Reader(ctx), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get records: %w", err)
+	}
+	return toUsers(rows), nil
+}
+
+// Search returns the users matching every condition of the filter, ordered by id.
+func (r *UserRepository) Search(ctx context.Context, filter *model.UserFilter) ([]*model.User, error) {
+	var mods []qm.QueryMod
+	if filter != nil && filter.Role != nil {
+		mods = append(mods, r.dialect.where(models.UserTableColumns.Role, "=", filter.Role.String()))
+	}
+	if filter != nil && filter.Query != nil && *filter.Query != "" {
+		pattern := "%" + likeEscaper.Replace(*filter.Query) + "%"
+		mods = append(mods, qm.Where(
+			"("+models.UserTableColumns.Name+" "+r.dialect.iLike+" ? OR "+models.UserTableColumns.Email+" "+r.dialect.iLike+" ?)",
+			pattern, pattern,
+		))
+	}
+	mods = append(mods, qm.OrderBy(models.UserTableColumns.ID))
+	var rows models.UserSlice
+	err := r.dialect.query(models.TableNames.Users, mods...).Bind(ctx, r.db.Reader(ctx), &rows)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get records: %w", err)
 	}
+	return toUsers(rows), nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func toUsers(rows models.UserSlice) []*model.User {
 	users := make([]*model.User, len(rows))
 	for i, row := range rows {
 		users[i] = &model.User{
-			ID:   row.ID,
-			Name: row.Name,
+			ID:        row.ID,
+			Name:      row.Name,
+			Email:     row.Email.Ptr(),
+			AvatarURL: row.AvatarURL.Ptr(),
+			Role:      model.Role(row.Role),
 		}
 	}
-	return users, nil
+	return users
 }
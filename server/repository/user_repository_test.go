@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shota-tech/graphql/server/database"
 	"github.com/shota-tech/graphql/server/graph/model"
 	"github.com/shota-tech/graphql/server/repository"
 	"github.com/stretchr/testify/assert"
@@ -15,23 +16,30 @@ import (
 )
 
 func TestUserRepository_Store(t *testing.T) {
+	email := "user1@example.com"
+	query := map[*repository.Dialect]string{
+		repository.MySQL: "INSERT INTO `users` (`id`,`name`,`email`,`avatar_url`,`role`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?) " +
+			"ON DUPLICATE KEY UPDATE `name` = VALUES(`name`),`email` = VALUES(`email`),`avatar_url` = VALUES(`avatar_url`),`role` = VALUES(`role`),`updated_at` = VALUES(`updated_at`)",
+		repository.Postgres: `INSERT INTO "users" ("id","name","email","avatar_url","role","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) ` +
+			`ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name","email" = EXCLUDED."email","avatar_url" = EXCLUDED."avatar_url","role" = EXCLUDED."role","updated_at" = EXCLUDED."updated_at"`,
+	}
 	tests := map[string]struct {
-		setup     func(sqlmock.Sqlmock)
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
 		user      *model.User
 		assertErr assert.ErrorAssertionFunc
 	}{
 		"happy path": {
-			setup: func(mock sqlmock.Sqlmock) {
-				query := "INSERT INTO `users` (`id`,`name`,`created_at`,`updated_at`) VALUES (?,?,?,?) " +
-					"ON DUPLICATE KEY UPDATE `name` = VALUES(`name`),`created_at` = VALUES(`created_at`),`updated_at` = VALUES(`updated_at`)"
-				args := []driver.Value{"auth0|123456", "user1", sqlmock.AnyArg(), sqlmock.AnyArg()}
-				mock.ExpectExec(regexp.QuoteMeta(query)).
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				args := []driver.Value{"auth0|123456", "user1", "user1@example.com", nil, "MEMBER", sqlmock.AnyArg(), sqlmock.AnyArg()}
+				mock.ExpectExec(regexp.QuoteMeta(query[d])).
 					WithArgs(args...).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			user: &model.User{
-				ID:   "auth0|123456",
-				Name: "user1",
+				ID:    "auth0|123456",
+				Name:  "user1",
+				Email: &email,
+				Role:  model.RoleMember,
 			},
 			assertErr: assert.NoError,
 		},
@@ -41,70 +49,226 @@ func TestUserRepository_Store(t *testing.T) {
 			assertErr: assert.Error,
 		},
 		"failed to upsert record": {
-			setup: func(mock sqlmock.Sqlmock) {
-				query := "INSERT INTO `users` (`id`,`name`,`created_at`,`updated_at`) VALUES (?,?,?,?) " +
-					"ON DUPLICATE KEY UPDATE `name` = VALUES(`name`),`created_at` = VALUES(`created_at`),`updated_at` = VALUES(`updated_at`)"
-				args := []driver.Value{"auth0|123456", "user1", sqlmock.AnyArg(), sqlmock.AnyArg()}
-				mock.ExpectExec(regexp.QuoteMeta(query)).
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				args := []driver.Value{"auth0|123456", "user1", "user1@example.com", nil, "MEMBER", sqlmock.AnyArg(), sqlmock.AnyArg()}
+				mock.ExpectExec(regexp.QuoteMeta(query[d])).
 					WithArgs(args...).
 					WillReturnError(assert.AnError)
 			},
 			user: &model.User{
-				ID:   "auth0|123456",
-				Name: "user1",
+				ID:    "auth0|123456",
+				Name:  "user1",
+				Email: &email,
+				Role:  model.RoleMember,
+			},
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewUserRepository(database.New(db), d.dialect)
+				err = sut.Store(context.Background(), tt.user)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestUserRepository_Get(t *testing.T) {
+	email := "user1@example.com"
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		id        string
+		want      *model.User
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `users`.* FROM `users` WHERE (`users`.`id` = ?) LIMIT 1;"
+				rows := sqlmock.NewRows([]string{"id", "name", "email", "avatar_url", "role", "created_at", "updated_at"}).
+					AddRow("auth0|123456", "user1", email, nil, "ADMIN", time.Now(), time.Now())
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("auth0|123456").
+					WillReturnRows(rows)
+			},
+			id:        "auth0|123456",
+			want:      &model.User{ID: "auth0|123456", Name: "user1", Email: &email, Role: model.RoleAdmin},
+			assertErr: assert.NoError,
+		},
+		"not found": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `users`.* FROM `users` WHERE (`users`.`id` = ?) LIMIT 1;"
+				rows := sqlmock.NewRows([]string{"id", "name", "email", "avatar_url", "role", "created_at", "updated_at"})
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("auth0|123456").
+					WillReturnRows(rows)
+			},
+			id:   "auth0|123456",
+			want: nil,
+			assertErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, repository.ErrNotFound)
+			},
+		},
+		"failed to get record": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `users`.* FROM `users` WHERE (`users`.`id` = ?) LIMIT 1;"
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs("auth0|123456").
+					WillReturnError(assert.AnError)
+			},
+			id:        "auth0|123456",
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewUserRepository(database.New(db), d.dialect)
+				got, err := sut.Get(context.Background(), tt.id)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestUserRepository_UpdateName(t *testing.T) {
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "UPDATE `users` SET `name` = ?, `updated_at` = ? WHERE (`users`.`id` = ?);"
+				mock.ExpectExec(expect(d, query)).
+					WithArgs("user1", sqlmock.AnyArg(), "auth0|123456").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assertErr: assert.NoError,
+		},
+		"failed to update record": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "UPDATE `users` SET `name` = ?, `updated_at` = ? WHERE (`users`.`id` = ?);"
+				mock.ExpectExec(expect(d, query)).
+					WithArgs("user1", sqlmock.AnyArg(), "auth0|123456").
+					WillReturnError(assert.AnError)
 			},
 			assertErr: assert.Error,
 		},
 	}
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			// setup sqlmock
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			if tt.setup != nil {
-				tt.setup(mock)
-			}
-			// test
-			sut := repository.NewUserRepository(db)
-			err = sut.Store(context.Background(), tt.user)
-			tt.assertErr(t, err)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				tt.setup(mock, d.dialect)
+				// test
+				sut := repository.NewUserRepository(database.New(db), d.dialect)
+				err = sut.UpdateName(context.Background(), "auth0|123456", "user1")
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestUserRepository_UpdateRole(t *testing.T) {
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "UPDATE `users` SET `role` = ?, `updated_at` = ? WHERE (`users`.`id` = ?);"
+				mock.ExpectExec(expect(d, query)).
+					WithArgs("ADMIN", sqlmock.AnyArg(), "auth0|123456").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assertErr: assert.NoError,
+		},
+		"failed to update record": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "UPDATE `users` SET `role` = ?, `updated_at` = ? WHERE (`users`.`id` = ?);"
+				mock.ExpectExec(expect(d, query)).
+					WithArgs("ADMIN", sqlmock.AnyArg(), "auth0|123456").
+					WillReturnError(assert.AnError)
+			},
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				tt.setup(mock, d.dialect)
+				// test
+				sut := repository.NewUserRepository(database.New(db), d.dialect)
+				err = sut.UpdateRole(context.Background(), "auth0|123456", model.RoleAdmin)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
 	}
 }
 
 func TestUserRepository_List(t *testing.T) {
+	email := "user1@example.com"
+	avatarURL := "https://example.com/user1.png"
 	tests := map[string]struct {
-		setup     func(sqlmock.Sqlmock)
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
 		ids       []string
 		want      []*model.User
 		assertErr assert.ErrorAssertionFunc
 	}{
 		"happy path": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `users`.* FROM `users` WHERE (`users`.`id` IN (?,?));"
 				args := []driver.Value{"auth0|123456", "auth0|567890"}
-				rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
-					AddRow("auth0|123456", "user1", time.Now(), time.Now()).
-					AddRow("auth0|567890", "user2", time.Now(), time.Now())
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				rows := sqlmock.NewRows([]string{"id", "name", "email", "avatar_url", "role", "created_at", "updated_at"}).
+					AddRow("auth0|123456", "user1", email, avatarURL, "ADMIN", time.Now(), time.Now()).
+					AddRow("auth0|567890", "user2", nil, nil, "MEMBER", time.Now(), time.Now())
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
 			ids: []string{"auth0|123456", "auth0|567890"},
 			want: []*model.User{
-				{ID: "auth0|123456", Name: "user1"},
-				{ID: "auth0|567890", Name: "user2"},
+				{ID: "auth0|123456", Name: "user1", Email: &email, AvatarURL: &avatarURL, Role: model.RoleAdmin},
+				{ID: "auth0|567890", Name: "user2", Role: model.RoleMember},
 			},
 			assertErr: assert.NoError,
 		},
 		"0 records": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `users`.* FROM `users` WHERE (`users`.`id` IN (?,?));"
 				args := []driver.Value{"auth0|123456", "auth0|567890"}
-				rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"})
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				rows := sqlmock.NewRows([]string{"id", "name", "email", "avatar_url", "role", "created_at", "updated_at"})
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnRows(rows)
 			},
@@ -113,10 +277,10 @@ func TestUserRepository_List(t *testing.T) {
 			assertErr: assert.NoError,
 		},
 		"failed to get records": {
-			setup: func(mock sqlmock.Sqlmock) {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
 				query := "SELECT `users`.* FROM `users` WHERE (`users`.`id` IN (?,?));"
 				args := []driver.Value{"auth0|123456", "auth0|567890"}
-				mock.ExpectQuery(regexp.QuoteMeta(query)).
+				mock.ExpectQuery(expect(d, query)).
 					WithArgs(args...).
 					WillReturnError(assert.AnError)
 			},
@@ -125,21 +289,101 @@ func TestUserRepository_List(t *testing.T) {
 			assertErr: assert.Error,
 		},
 	}
-	for name, tt := range tests {
-		t.Run(name, func(t *testing.T) {
-			// setup sqlmock
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			if tt.setup != nil {
-				tt.setup(mock)
-			}
-			// test
-			sut := repository.NewUserRepository(db)
-			got, err := sut.List(context.Background(), tt.ids)
-			assert.Equal(t, tt.want, got)
-			tt.assertErr(t, err)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewUserRepository(database.New(db), d.dialect)
+				got, err := sut.List(context.Background(), tt.ids)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	}
+}
+
+func TestUserRepository_Search(t *testing.T) {
+	role := model.RoleAdmin
+	query := "50%_off"
+	tests := map[string]struct {
+		setup     func(sqlmock.Sqlmock, *repository.Dialect)
+		filter    *model.UserFilter
+		want      []*model.User
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"happy path": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := map[*repository.Dialect]string{
+					repository.MySQL:    "SELECT `users`.* FROM `users` WHERE (`users`.`role` = ?) AND ((users.name LIKE ? OR users.email LIKE ?)) ORDER BY users.id;",
+					repository.Postgres: `SELECT "users".* FROM "users" WHERE ("users"."role" = $1) AND ((users.name ILIKE $2 OR users.email ILIKE $3)) ORDER BY users.id;`,
+				}[d]
+				args := []driver.Value{"ADMIN", `%50\%\_off%`, `%50\%\_off%`}
+				rows := sqlmock.NewRows([]string{"id", "name", "email", "avatar_url", "role", "created_at", "updated_at"}).
+					AddRow("auth0|123456", "50%_off", nil, nil, "ADMIN", time.Now(), time.Now())
+				mock.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(args...).
+					WillReturnRows(rows)
+			},
+			filter: &model.UserFilter{Role: &role, Query: &query},
+			want: []*model.User{
+				{ID: "auth0|123456", Name: "50%_off", Role: model.RoleAdmin},
+			},
+			assertErr: assert.NoError,
+		},
+		"no filter": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `users`.* FROM `users` ORDER BY users.id;"
+				rows := sqlmock.NewRows([]string{"id", "name", "email", "avatar_url", "role", "created_at", "updated_at"}).
+					AddRow("auth0|123456", "user1", nil, nil, "ADMIN", time.Now(), time.Now()).
+					AddRow("auth0|567890", "user2", nil, nil, "VIEWER", time.Now(), time.Now())
+				mock.ExpectQuery(expect(d, query)).
+					WillReturnRows(rows)
+			},
+			filter: nil,
+			want: []*model.User{
+				{ID: "auth0|123456", Name: "user1", Role: model.RoleAdmin},
+				{ID: "auth0|567890", Name: "user2", Role: model.RoleViewer},
+			},
+			assertErr: assert.NoError,
+		},
+		"failed to get records": {
+			setup: func(mock sqlmock.Sqlmock, d *repository.Dialect) {
+				query := "SELECT `users`.* FROM `users` WHERE (`users`.`role` = ?) ORDER BY users.id;"
+				args := []driver.Value{"ADMIN"}
+				mock.ExpectQuery(expect(d, query)).
+					WithArgs(args...).
+					WillReturnError(assert.AnError)
+			},
+			filter:    &model.UserFilter{Role: &role},
+			want:      nil,
+			assertErr: assert.Error,
+		},
+	}
+	for _, d := range dialects {
+		for name, tt := range tests {
+			t.Run(d.name+"/"+name, func(t *testing.T) {
+				// setup sqlmock
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				defer db.Close()
+				if tt.setup != nil {
+					tt.setup(mock, d.dialect)
+				}
+				// test
+				sut := repository.NewUserRepository(database.New(db), d.dialect)
+				got, err := sut.Search(context.Background(), tt.filter)
+				assert.Equal(t, tt.want, got)
+				tt.assertErr(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
 	}
 }
@@ -2,78 +2,236 @@ package main
 
 //go:generate gqlgen generate
 //go:generate sqlboiler mysql
+//go:generate go run server.go manifest -out persisted_queries.json ../client/src/graphql
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/XSAM/otelsql"
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	"github.com/shota-tech/graphql/server/cache"
+	"github.com/shota-tech/graphql/server/database"
 	"github.com/shota-tech/graphql/server/graph"
 	"github.com/shota-tech/graphql/server/loader"
+	"github.com/shota-tech/graphql/server/logging"
+	"github.com/shota-tech/graphql/server/metrics"
 	"github.com/shota-tech/graphql/server/middleware/auth"
+	"github.com/shota-tech/graphql/server/middleware/csrf"
+	"github.com/shota-tech/graphql/server/middleware/limit"
+	"github.com/shota-tech/graphql/server/middleware/persisted"
+	"github.com/shota-tech/graphql/server/middleware/ratelimit"
+	"github.com/shota-tech/graphql/server/middleware/respcache"
+	"github.com/shota-tech/graphql/server/policy"
 	"github.com/shota-tech/graphql/server/repository"
+	"github.com/shota-tech/graphql/server/repository/memory"
+	"github.com/shota-tech/graphql/server/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 )
 
-const defaultPort = "8080"
+const (
+	defaultPort          = "8080"
+	defaultMetricsPort   = "9090"
+	defaultAuthIssuer    = "http://localhost:8080/"
+	defaultAuthAudience  = "graphql"
+	defaultAuthDevSecret = "graphql-dev-secret"
+	defaultMaxDepth      = 10
+	defaultMaxComplexity = 5000
+	defaultAPQCacheSize  = 1000
+	defaultAPQCacheTTL   = 24 * time.Hour
+	defaultRespCacheSize = 1000
+	defaultRespCacheTTL  = time.Minute
+	defaultUserCacheTTL  = 5 * time.Minute
+	defaultManifestPath  = "persisted_queries.json"
+	defaultDBDriver      = "mysql"
+	defaultMySQLAddr     = "db"
+	defaultPostgresAddr  = "postgres"
+	defaultServiceName   = "graphql-server"
+	defaultLogFormat     = "json"
+	defaultLogLevel      = "info"
+	defaultQueryLimit    = "300/m"
+	defaultMutationLimit = "60/m"
+	defaultIPLimit       = "600/m"
+)
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "token" {
+		if err := issueToken(os.Args[2:]); err != nil {
+			fatal("failed to issue token", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "manifest" {
+		if err := generateManifest(os.Args[2:]); err != nil {
+			fatal("failed to generate manifest", err)
+		}
+		return
+	}
+
+	logger, err := logging.New(os.Stdout, getenv("LOG_FORMAT", defaultLogFormat), getenv("LOG_LEVEL", defaultLogLevel))
+	if err != nil {
+		fatal("failed to setup logger", err)
+	}
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), os.Getenv("OTEL_TRACES_EXPORTER"), getenv("OTEL_SERVICE_NAME", defaultServiceName))
+	if err != nil {
+		fatal("failed to setup tracing", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = defaultPort
 	}
 
-	// connect db
-	jst, err := time.LoadLocation("Asia/Tokyo")
+	// the operations of the persisted queries label the metrics, PERSISTED_QUERIES_STRICT also rejects any other
+	strict := os.Getenv("PERSISTED_QUERIES_STRICT") == "true"
+	manifest, err := persisted.LoadManifest(getenv("PERSISTED_QUERIES_MANIFEST", defaultManifestPath))
 	if err != nil {
-		log.Fatalf("failed to load location: %v", err)
-	}
-	config := mysql.Config{
-		DBName:    os.Getenv("MYSQL_DATABASE"),
-		User:      os.Getenv("MYSQL_USER"),
-		Passwd:    os.Getenv("MYSQL_PASSWORD"),
-		Addr:      "db",
-		Net:       "tcp",
-		ParseTime: true,
-		Collation: "utf8mb4_general_ci",
-		Loc:       jst,
-	}
-	db, err := sql.Open("mysql", config.FormatDSN())
-	if err != nil {
-		log.Fatalf("failed to connect db: %v", err)
+		if strict {
+			fatal("failed to load persisted queries", err)
+		}
+		slog.Warn("persisted queries are not loaded, operations are labelled other in the metrics", "error", err)
+	}
+	m := metrics.New(manifest.OperationNames()...)
+
+	// connect db, DB_DRIVER=memory runs without any
+	var repositories repositories
+	if driver := getenv("DB_DRIVER", defaultDBDriver); driver == "memory" {
+		slog.Warn("memory db driver is enabled, records are lost on restart")
+		repositories = newMemoryRepositories(memory.NewDB())
+	} else {
+		source, err := newDataSource(driver)
+		if err != nil {
+			fatal("failed to setup db", err)
+		}
+		primary := openDB(source, source.addr)
+		defer primary.Close()
+		m.RegisterDB(primary, source.name)
+		// reads go to the replicas when MYSQL_REPLICA_ADDRS or POSTGRES_REPLICA_ADDRS is set
+		var replicas []*sql.DB
+		for _, addr := range source.replicaAddrs {
+			replica := openDB(source, addr)
+			defer replica.Close()
+			m.RegisterDB(replica, source.name+"@"+addr)
+			replicas = append(replicas, replica)
+		}
+		repositories = newRepositories(source.driver, database.New(primary, replicas...))
 	}
-	defer db.Close()
 
 	// DI
-	userRepository := repository.NewUserRepository(db)
-	taskRepository := repository.NewTaskRepository(db)
-	todoRepository := repository.NewTodoRepository(db)
+	userRepository := repositories.user
+	taskRepository := repositories.task
+	todoRepository := repositories.todo
+	activityRepository := repositories.activity
+	statusTransitionRepository := repositories.statusTransition
+	accessTokenRepository := repositories.accessToken
 	taskLoader := loader.NewTaskLoader(taskRepository)
 	userLoader := loader.NewUserLoader(userRepository)
 	todoLoader := loader.NewTodoLoader(todoRepository)
+	// USER_CACHE_SIZE keeps users across requests, tasks and todos are always read from the db
+	if size := getenvInt("USER_CACHE_SIZE", 0); size > 0 {
+		userCache := loader.NewUserCache(size, getenvDuration("USER_CACHE_TTL", defaultUserCacheTTL), m)
+		userRepository = userCache.Repository(userRepository)
+		userLoader = loader.NewCachedUserLoader(userRepository, userCache)
+	}
 	loaders := loader.NewLoaders(
 		userLoader,
 		taskLoader,
 		todoLoader,
+		m,
+		tracing.LoaderObserver{},
 	)
+	// RESPONSE_CACHE_SIZE=0 disables the cache of fetchTasks responses
+	var responseCache *respcache.Cache
+	if size := getenvInt("RESPONSE_CACHE_SIZE", defaultRespCacheSize); size > 0 {
+		responseCache = respcache.New(size, getenvDuration("RESPONSE_CACHE_TTL", defaultRespCacheTTL))
+	}
 	resolver := &graph.Resolver{
-		Loaders:        loaders,
-		UserRepository: userRepository,
-		TaskRepository: taskRepository,
-		TodoRepository: todoRepository,
+		Loaders:                    loaders,
+		Policy:                     policy.NewEngine(policy.DefaultRules),
+		Transactor:                 repositories.transactor,
+		UserRepository:             userRepository,
+		TaskRepository:             taskRepository,
+		TodoRepository:             todoRepository,
+		ActivityRepository:         activityRepository,
+		StatusTransitionRepository: statusTransitionRepository,
+		AccessTokenRepository:      accessTokenRepository,
+		ResponseCache:              responseCache,
+	}
+	authenticator, err := newAuthenticator()
+	if err != nil {
+		fatal("failed to setup authenticator", err)
+	}
+	authenticator = auth.NewAccessTokenAuthenticator(accessTokenRepository, authenticator)
+	apqCache, err := newAPQCache()
+	if err != nil {
+		fatal("failed to setup apq cache", err)
+	}
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{
+		Resolvers:  resolver,
+		Complexity: graph.NewComplexityRoot(),
+	}))
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.SetQueryCache(lru.New(1000))
+	srv.Use(logging.OperationLogger{})
+	srv.Use(tracing.Extension{})
+	srv.AroundOperations(m.AroundOperations)
+	srv.AroundFields(m.AroundFields)
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{Cache: apqCache})
+	if strict {
+		srv.Use(persisted.Allowlist{Manifest: manifest})
+	}
+	srv.Use(ratelimit.RateLimit{
+		Queries:   newLimiter("RATE_LIMIT_QUERIES", defaultQueryLimit),
+		Mutations: newLimiter("RATE_LIMIT_MUTATIONS", defaultMutationLimit),
+	})
+	srv.Use(limit.FixedDepthLimit(getenvInt("GRAPHQL_MAX_DEPTH", defaultMaxDepth)))
+	srv.Use(extension.FixedComplexityLimit(getenvInt("GRAPHQL_MAX_COMPLEXITY", defaultMaxComplexity)))
+	if responseCache != nil {
+		srv.Use(respcache.Extension{Cache: responseCache, Fields: []string{"fetchTasks"}})
+	}
+
+	// TRUSTED_PROXIES lists the proxies whose X-Forwarded-For and X-Real-IP headers give the client IP
+	trustedProxies, err := ratelimit.ParsePrefixes(splitList(os.Getenv("TRUSTED_PROXIES")))
+	if err != nil {
+		fatal("invalid trusted proxies", err)
 	}
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
 
 	// setup router
 	router := chi.NewRouter()
-	router.Use(chiMiddleware.AllowContentType("application/json"))
+	router.Use(chiMiddleware.RequestID)
+	router.Use(logging.Middleware)
 	router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
@@ -81,9 +239,343 @@ func main() {
 		AllowCredentials: true,
 	}))
 	router.Handle("/", playground.Handler("GraphQL playground", "/graphql"))
-	router.With(auth.EnsureValidToken()).Handle("/graphql", srv)
+	var responses auth.ResponseCache
+	if responseCache != nil {
+		responses = responseCache
+	}
+	// queries are also served over GET, HTTP_CACHE_SHARED_MAX_AGE lets CDNs keyed by Authorization cache them
+	// ADMIN_USER_IDS lists the users made admins when they are provisioned, to bootstrap the first admin
+	router.With(
+		csrf.RequirePreflight("Authorization", "X-CSRF-Token"),
+		ratelimit.RealIP(trustedProxies),
+		ratelimit.ByIP(newLimiter("RATE_LIMIT_IP", defaultIPLimit)),
+		auth.EnsureValidToken(authenticator),
+		database.Middleware,
+		auth.ProvisionUser(repositories.transactor, userRepository, activityRepository, responses, splitList(os.Getenv("ADMIN_USER_IDS"))),
+		respcache.ConditionalGET(getenvDuration("HTTP_CACHE_SHARED_MAX_AGE", 0)),
+		ratelimit.Middleware,
+	).Handle("/graphql", srv)
+
+	// start server, the metrics being served on METRICS_PORT apart from the public routes
+	metricsPort := getenv("METRICS_PORT", defaultMetricsPort)
+	go func() {
+		metricsRouter := chi.NewRouter()
+		metricsRouter.Handle("/metrics", m.Handler())
+		fatal("failed to serve metrics", http.ListenAndServe(":"+metricsPort, metricsRouter))
+	}()
+	slog.Info("server started", "playground", fmt.Sprintf("http://localhost:%s/", port))
+	handler := otelhttp.NewHandler(router, "http.server", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return r.Method + " " + r.URL.Path
+	}))
+	fatal("failed to serve", http.ListenAndServe(":"+port, handler))
+}
+
+// dataSource tells how to connect the database selected by DB_DRIVER.
+type dataSource struct {
+	driver       string
+	name         string
+	system       attribute.KeyValue
+	addr         string
+	replicaAddrs []string
+	dsn          func(addr string) string
+}
+
+// newDataSource reads the connection settings of the driver, "mysql" or "postgres".
+func newDataSource(driver string) (dataSource, error) {
+	switch driver {
+	case "mysql":
+		jst, err := time.LoadLocation("Asia/Tokyo")
+		if err != nil {
+			return dataSource{}, fmt.Errorf("failed to load location: %w", err)
+		}
+		config := mysql.Config{
+			DBName:    os.Getenv("MYSQL_DATABASE"),
+			User:      os.Getenv("MYSQL_USER"),
+			Passwd:    os.Getenv("MYSQL_PASSWORD"),
+			Net:       "tcp",
+			ParseTime: true,
+			Collation: "utf8mb4_general_ci",
+			Loc:       jst,
+		}
+		return dataSource{
+			driver:       driver,
+			name:         config.DBName,
+			system:       semconv.DBSystemMySQL,
+			addr:         getenv("MYSQL_ADDR", defaultMySQLAddr),
+			replicaAddrs: splitList(os.Getenv("MYSQL_REPLICA_ADDRS")),
+			dsn: func(addr string) string {
+				config := config
+				config.Addr = addr
+				return config.FormatDSN()
+			},
+		}, nil
+	case "postgres":
+		name := os.Getenv("POSTGRES_DB")
+		user := url.UserPassword(os.Getenv("POSTGRES_USER"), os.Getenv("POSTGRES_PASSWORD"))
+		query := url.Values{
+			"sslmode":  {getenv("POSTGRES_SSLMODE", "disable")},
+			"timezone": {"Asia/Tokyo"},
+		}
+		return dataSource{
+			driver:       driver,
+			name:         name,
+			system:       semconv.DBSystemPostgreSQL,
+			addr:         getenv("POSTGRES_ADDR", defaultPostgresAddr),
+			replicaAddrs: splitList(os.Getenv("POSTGRES_REPLICA_ADDRS")),
+			dsn: func(addr string) string {
+				u := url.URL{Scheme: "postgres", User: user, Host: addr, Path: "/" + name, RawQuery: query.Encode()}
+				return u.String()
+			},
+		}, nil
+	default:
+		return dataSource{}, fmt.Errorf("unknown db driver: %s", driver)
+	}
+}
+
+type repositories struct {
+	transactor       repository.ITransactor
+	user             repository.IUserRepository
+	task             repository.ITaskRepository
+	todo             repository.ITodoRepository
+	activity         repository.IActivityRepository
+	statusTransition repository.IStatusTransitionRepository
+	accessToken      repository.IAccessTokenRepository
+}
+
+// newRepositories returns the repositories speaking the SQL dialect of the driver.
+func newRepositories(driver string, db *database.DB) repositories {
+	dialect := repository.MySQL
+	if driver == "postgres" {
+		dialect = repository.Postgres
+	}
+	return repositories{
+		transactor:       db,
+		user:             repository.NewUserRepository(db, dialect),
+		task:             repository.NewTaskRepository(db, dialect),
+		todo:             repository.NewTodoRepository(db, dialect),
+		activity:         repository.NewActivityRepository(db, dialect),
+		statusTransition: repository.NewStatusTransitionRepository(db, dialect),
+		accessToken:      repository.NewAccessTokenRepository(db, dialect),
+	}
+}
+
+func newMemoryRepositories(db *memory.DB) repositories {
+	return repositories{
+		transactor:       db,
+		user:             memory.NewUserRepository(db),
+		task:             memory.NewTaskRepository(db),
+		todo:             memory.NewTodoRepository(db),
+		activity:         memory.NewActivityRepository(db),
+		statusTransition: memory.NewStatusTransitionRepository(db),
+		accessToken:      memory.NewAccessTokenRepository(db),
+	}
+}
+
+// openDB connects the database at the address, waiting for it to be up.
+func openDB(source dataSource, addr string) *sql.DB {
+	db, err := otelsql.Open(source.driver, source.dsn(addr), otelsql.WithAttributes(source.system))
+	if err != nil {
+		fatal("failed to connect db", err)
+	}
+	if err := database.Bootstrap(context.Background(), db, databaseConfig()); err != nil {
+		fatal("failed to connect db "+addr, err)
+	}
+	return db
+}
+
+// databaseConfig overrides the defaults of the connection pool with DB_* variables.
+func databaseConfig() database.Config {
+	config := database.DefaultConfig
+	config.MaxOpenConns = getenvInt("DB_MAX_OPEN_CONNS", config.MaxOpenConns)
+	config.MaxIdleConns = getenvInt("DB_MAX_IDLE_CONNS", config.MaxIdleConns)
+	config.ConnMaxLifetime = getenvDuration("DB_CONN_MAX_LIFETIME", config.ConnMaxLifetime)
+	config.ConnMaxIdleTime = getenvDuration("DB_CONN_MAX_IDLE_TIME", config.ConnMaxIdleTime)
+	config.ConnectTimeout = getenvDuration("DB_CONNECT_TIMEOUT", config.ConnectTimeout)
+	return config
+}
+
+// newAuthenticator selects the token validation backend by AUTH_MODE.
+func newAuthenticator() (auth.Authenticator, error) {
+	switch mode := os.Getenv("AUTH_MODE"); mode {
+	case "", "auth0":
+		return auth.NewJWKSAuthenticator(os.Getenv("AUTH0_DOMAIN"), os.Getenv("AUTH0_AUDIENCE"))
+	case "keyfile":
+		return auth.NewKeyFileAuthenticator(os.Getenv("AUTH_KEY_FILE"), authIssuer(), authAudience())
+	case "oidc":
+		return newOIDCAuthenticator()
+	case "dev":
+		slog.Warn("dev auth mode is enabled, do not use it in production")
+		return auth.NewDevAuthenticator(authDevSecret(), authIssuer(), authAudience())
+	default:
+		return nil, fmt.Errorf("unknown auth mode: %s", mode)
+	}
+}
+
+// newOIDCAuthenticator trusts every issuer in OIDC_ISSUERS with the same audiences and algorithms.
+// OIDC_SCOPE_MAPPING grants scopes to roles, e.g. "admin=read:user write:user,member=read:tasks write:tasks".
+func newOIDCAuthenticator() (auth.Authenticator, error) {
+	config := auth.OIDCConfig{
+		ScopeMapping: map[string][]string{},
+	}
+	for _, issuer := range splitList(os.Getenv("OIDC_ISSUERS")) {
+		config.Providers = append(config.Providers, auth.OIDCProvider{
+			Issuer:     issuer,
+			Audiences:  splitList(os.Getenv("OIDC_AUDIENCES")),
+			Algorithms: splitList(os.Getenv("OIDC_ALGORITHMS")),
+		})
+	}
+	for _, mapping := range splitList(os.Getenv("OIDC_SCOPE_MAPPING")) {
+		grant, scopes, ok := strings.Cut(mapping, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid scope mapping: %s", mapping)
+		}
+		grant = strings.TrimSpace(grant)
+		config.ScopeMapping[grant] = append(config.ScopeMapping[grant], strings.Fields(scopes)...)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return auth.NewOIDCAuthenticator(ctx, config)
+}
+
+// newAPQCache selects where automatic persisted queries are kept by APQ_CACHE.
+// Redis lets every instance of the server share them.
+func newAPQCache() (graphql.Cache, error) {
+	ttl := getenvDuration("APQ_CACHE_TTL", defaultAPQCacheTTL)
+	switch backend := os.Getenv("APQ_CACHE"); backend {
+	case "", "memory":
+		return cache.NewMemory(getenvInt("APQ_CACHE_SIZE", defaultAPQCacheSize), ttl), nil
+	case "redis":
+		client, err := cache.NewGoRedisClient(os.Getenv("REDIS_URL"))
+		if err != nil {
+			return nil, err
+		}
+		return cache.NewRedis(client, "apq:", ttl), nil
+	default:
+		return nil, fmt.Errorf("unknown apq cache: %s", backend)
+	}
+}
+
+// newLimiter returns a token bucket for a limit such as "60/m", "off" disabling the limit.
+func newLimiter(key, fallback string) ratelimit.Limiter {
+	v := getenv(key, fallback)
+	if v == "off" {
+		return nil
+	}
+	parsed, err := ratelimit.ParseLimit(v)
+	if err != nil {
+		fatal("invalid rate limit "+key, err)
+	}
+	return ratelimit.NewTokenBucket(parsed)
+}
+
+// generateManifest writes the operations of the client, which are the only ones
+// executed when PERSISTED_QUERIES_STRICT is enabled.
+//
+//	go run server.go manifest -out persisted_queries.json ../client/src/graphql
+func generateManifest(args []string) error {
+	flags := flag.NewFlagSet("manifest", flag.ContinueOnError)
+	out := flags.String("out", defaultManifestPath, "path of the manifest")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("operation files or directories are required")
+	}
+
+	schema := graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}).Schema()
+	manifest, err := persisted.GenerateManifest(schema, flags.Args()...)
+	if err != nil {
+		return err
+	}
+	return manifest.Write(*out)
+}
+
+// issueToken mints a token for the keyfile or dev auth mode and prints it.
+//
+//	go run server.go token -sub user1 -scope "read:tasks write:tasks"
+func issueToken(args []string) error {
+	flags := flag.NewFlagSet("token", flag.ContinueOnError)
+	sub := flags.String("sub", "dev|user", "subject of the token")
+	scope := flags.String("scope", strings.Join(auth.AllScopes, " "), "space separated scopes")
+	ttl := flags.Duration("ttl", 24*time.Hour, "lifetime of the token")
+	key := flags.String("key", "", "PEM encoded RSA private key, signs with RS256 instead of HS256")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var issuer *auth.Issuer
+	var err error
+	if *key != "" {
+		issuer, err = auth.NewRS256Issuer(*key, authIssuer(), authAudience())
+	} else {
+		issuer, err = auth.NewHS256Issuer(authDevSecret(), authIssuer(), authAudience())
+	}
+	if err != nil {
+		return err
+	}
+	token, err := issuer.Issue(*sub, strings.Fields(*scope), *ttl)
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
+
+func authIssuer() string {
+	return getenv("AUTH_ISSUER", defaultAuthIssuer)
+}
+
+func authAudience() string {
+	return getenv("AUTH_AUDIENCE", defaultAuthAudience)
+}
+
+func authDevSecret() string {
+	return getenv("AUTH_DEV_SECRET", defaultAuthDevSecret)
+}
+
+// splitList splits a comma separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getenvInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		fatal(key+" must be an integer", err)
+	}
+	return i
+}
+
+func getenvDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		fatal(key+" must be a duration", err)
+	}
+	return d
+}
 
-	// start server
-	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
-	log.Fatal(http.ListenAndServe(":"+port, router))
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
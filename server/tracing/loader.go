@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/shota-tech/graphql/server/loader"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LoaderObserver starts a span for each batch of the loaders,
+// the queries of the batch function being its children.
+type LoaderObserver struct{}
+
+var _ loader.Observer = LoaderObserver{}
+
+func (LoaderObserver) StartBatch(ctx context.Context, name string, size int, wait time.Duration) (context.Context, func()) {
+	ctx, span := tracer().Start(ctx, "dataloader "+name,
+		trace.WithAttributes(
+			attribute.String("dataloader.name", name),
+			attribute.Int("dataloader.batch_size", size),
+			attribute.Int64("dataloader.wait_ms", wait.Milliseconds()),
+		),
+	)
+	return ctx, func() {
+		span.End()
+	}
+}
@@ -0,0 +1,75 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/shota-tech/graphql/server/graph"
+	"github.com/shota-tech/graphql/server/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(tracing.Extension{})
+	// the request is anonymous, so the resolver fails without touching the repositories.
+	var resp interface{}
+	err := client.New(srv).Post(`query fetchUser { fetchUser { id } }`, &resp)
+	assert.Error(t, err)
+	_, finish := tracing.LoaderObserver{}.StartBatch(context.Background(), "user", 3, 10*time.Millisecond)
+	finish()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	field, operation, batch := spans[0], spans[1], spans[2]
+	assert.Equal(t, "Query.fetchUser", field.Name())
+	assert.Equal(t, codes.Error, field.Status().Code)
+	assert.Equal(t, operation.SpanContext().SpanID(), field.Parent().SpanID())
+	assert.Equal(t, "query fetchUser", operation.Name())
+	assert.Equal(t, codes.Error, operation.Status().Code)
+	assert.Equal(t, "dataloader user", batch.Name())
+	assert.Contains(t, batch.Attributes(), attribute.Int("dataloader.batch_size", 3))
+}
+
+func TestSetup(t *testing.T) {
+	tests := map[string]struct {
+		exporter  string
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"disabled": {
+			exporter:  "",
+			assertErr: assert.NoError,
+		},
+		"stdout": {
+			exporter:  "stdout",
+			assertErr: assert.NoError,
+		},
+		"unknown exporter": {
+			exporter:  "zipkin",
+			assertErr: assert.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			shutdown, err := tracing.Setup(context.Background(), tt.exporter, "graphql-server")
+			tt.assertErr(t, err)
+			if err == nil {
+				assert.NoError(t, shutdown(context.Background()))
+			}
+		})
+	}
+}